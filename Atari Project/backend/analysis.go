@@ -0,0 +1,578 @@
+/**************************************/
+/*                                    */
+/*    Duplicates and ROM Set Health   */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package library
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*                 THE REPORT                     */
+/*   Read-only: nothing is moved or deleted, the  */
+/*   actions are suggestions for the user         */
+/*                                                */
+/**************************************************/
+
+var DefaultRegions = []string{"USA", "World", "Europe", "Japan"}
+
+type AnalysisOptions struct {
+	PreferRegions []string /*   best first; DefaultRegions if empty */
+}
+
+type AnalysisReport struct {
+	GeneratedAt      time.Time         `json:"generated_at"`
+	GamesAnalyzed    int               `json:"games_analyzed"`
+	PreferRegions    []string          `json:"prefer_regions"`
+	Duplicates       []DuplicateGroup  `json:"duplicates"`
+	Alternates       []AlternateGroup  `json:"alternates"`
+	Problems         []RomProblem      `json:"problems"`
+	Actions          []SuggestedAction `json:"actions"`
+	ReclaimableBytes int64             `json:"reclaimable_bytes"`
+}
+
+/*   What the file name says about a dump, in    */
+/*   No-Intro "(USA) (Rev 1)" and GoodTools      */
+/*   "(U) [!]" style                             */
+type RomEntry struct {
+	GameID   string   `json:"game_id"`
+	Title    string   `json:"title"`
+	Path     string   `json:"path"`
+	Platform string   `json:"platform"`
+	Size     int64    `json:"size"`
+	Regions  []string `json:"regions,omitempty"`
+	Revision string   `json:"revision,omitempty"`
+	Flags    []string `json:"flags,omitempty"`
+}
+
+/*   Same content under more than one path      */
+type DuplicateGroup struct {
+	Hash  string     `json:"hash"`
+	Size  int64      `json:"size"`
+	Keep  string     `json:"keep"`
+	Games []RomEntry `json:"games"`
+}
+
+/*   Different content, same game: regions,     */
+/*   revisions, hacks and bad dumps             */
+type AlternateGroup struct {
+	Platform  string     `json:"platform"`
+	Title     string     `json:"title"`
+	Preferred string     `json:"preferred"`
+	Games     []RomEntry `json:"games"`
+}
+
+type RomProblem struct {
+	GameID string `json:"game_id"`
+	Title  string `json:"title"`
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+const (
+	ActionRemove  = "remove"  /*   exact duplicate of Keep        */
+	ActionPrefer  = "prefer"  /*   best of its alternates         */
+	ActionReplace = "replace" /*   damaged, find a good dump      */
+)
+
+type SuggestedAction struct {
+	Action       string   `json:"action"`
+	GameID       string   `json:"game_id"`
+	Path         string   `json:"path"`
+	Reason       string   `json:"reason"`
+	Keep         string   `json:"keep,omitempty"`
+	Alternatives []string `json:"alternatives,omitempty"`
+}
+
+/**************************************************/
+/*                                                */
+/*                  ANALYZE                       */
+/*                                                */
+/**************************************************/
+
+/*   Whole files are read up to this size; past */
+/*   it only the header is, for N64 giants      */
+const analyzeReadLimit = 64 << 20
+
+const analyzeHeaderBytes = 0x10000 + 512
+
+type analyzed struct {
+	game     GameInfo
+	entry    RomEntry
+	hash     string
+	problems int
+}
+
+func (lib *Library) Analyze(ctx context.Context, opts AnalysisOptions) (AnalysisReport, error) {
+	regions := opts.PreferRegions
+	if len(regions) == 0 {
+		regions = DefaultRegions
+	}
+
+	lib.mu.RLock()
+	games := make([]GameInfo, len(lib.Games))
+	copy(games, lib.Games)
+	lib.mu.RUnlock()
+
+	report := AnalysisReport{
+		GeneratedAt: time.Now(), GamesAnalyzed: len(games), PreferRegions: regions,
+		Duplicates: []DuplicateGroup{}, Alternates: []AlternateGroup{},
+		Problems: []RomProblem{}, Actions: []SuggestedAction{},
+	}
+
+	items := make([]*analyzed, 0, len(games))
+	for _, game := range games {
+		if err := ctx.Err(); err != nil {
+			return AnalysisReport{}, err
+		}
+		item := &analyzed{game: game, entry: describeROM(game)}
+		problem := func(kind, detail string) {
+			item.problems++
+			report.Problems = append(report.Problems, RomProblem{
+				GameID: game.ID, Title: game.Title, Path: game.Path, Kind: kind, Detail: detail,
+			})
+		}
+
+		data, sum, err := readForAnalysis(game.Path)
+		if err != nil {
+			problem(ProblemUnreadable, err.Error())
+			items = append(items, item)
+			continue
+		}
+		item.entry.Size = sum.Size
+		if game.Hash != "" && game.Hash != sum.SHA1 {
+			problem(ProblemChecksumMismatch, "content changed since the last scan")
+		}
+		item.hash = sum.SHA1
+
+		for _, finding := range checkROM(game.Platform, game.Path, data, sum.Size) {
+			problem(finding.Kind, finding.Detail)
+		}
+		items = append(items, item)
+	}
+
+	report.addDuplicates(items, regions)
+	report.addAlternates(items, regions)
+	report.addReplacements(items)
+	return report, nil
+}
+
+/*   One pass for both the check data and the   */
+/*   hashes                                      */
+func readForAnalysis(path string) ([]byte, fileSum, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fileSum{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fileSum{}, err
+	}
+	if info.IsDir() {
+		return nil, fileSum{}, fmt.Errorf("%s is a directory", path)
+	}
+
+	var data []byte
+	if info.Size() <= analyzeReadLimit {
+		if data, err = io.ReadAll(file); err != nil {
+			return nil, fileSum{}, err
+		}
+		sha := sha1.Sum(data)
+		return data, fileSum{
+			SHA1:  hex.EncodeToString(sha[:]),
+			CRC32: fmt.Sprintf("%08X", crc32.ChecksumIEEE(data)),
+			Size:  int64(len(data)),
+		}, nil
+	}
+
+	data = make([]byte, analyzeHeaderBytes)
+	n, err := io.ReadFull(file, data)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fileSum{}, err
+	}
+	sha, crc := sha1.New(), crc32.NewIEEE()
+	w := io.MultiWriter(sha, crc)
+	w.Write(data[:n])
+	rest, err := io.Copy(w, file)
+	if err != nil {
+		return nil, fileSum{}, err
+	}
+	return data[:n], fileSum{
+		SHA1:  hex.EncodeToString(sha.Sum(nil)),
+		CRC32: fmt.Sprintf("%08X", crc.Sum32()),
+		Size:  int64(n) + rest,
+	}, nil
+}
+
+/**************************************************/
+/*                                                */
+/*               EXACT DUPLICATES                 */
+/*   Keep the copy holding favorites and play     */
+/*   history, then the best named one             */
+/*                                                */
+/**************************************************/
+
+func (r *AnalysisReport) addDuplicates(items []*analyzed, regions []string) {
+	byHash := make(map[string][]*analyzed)
+	order := make([]string, 0)
+	for _, item := range items {
+		if item.hash == "" {
+			continue
+		}
+		if _, ok := byHash[item.hash]; !ok {
+			order = append(order, item.hash)
+		}
+		byHash[item.hash] = append(byHash[item.hash], item)
+	}
+
+	for _, hash := range order {
+		group := byHash[hash]
+		if len(group) < 2 {
+			continue
+		}
+		sortBest(group, regions, true)
+		keep := group[0]
+
+		dup := DuplicateGroup{Hash: hash, Size: keep.entry.Size, Keep: keep.game.ID}
+		for _, item := range group {
+			dup.Games = append(dup.Games, item.entry)
+			if item == keep {
+				continue
+			}
+			r.ReclaimableBytes += item.entry.Size
+			r.Actions = append(r.Actions, SuggestedAction{
+				Action: ActionRemove, GameID: item.game.ID, Path: item.game.Path, Keep: keep.game.ID,
+				Reason: fmt.Sprintf("same content as %s", filepath.Base(keep.game.Path)),
+			})
+		}
+		r.Duplicates = append(r.Duplicates, dup)
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                 ALTERNATES                     */
+/*   Same platform and normalized title, but      */
+/*   different content                            */
+/*                                                */
+/**************************************************/
+
+func (r *AnalysisReport) addAlternates(items []*analyzed, regions []string) {
+	byTitle := make(map[string][]*analyzed)
+	order := make([]string, 0)
+	for _, item := range items {
+		title := normalizeTitle(item.game.Path)
+		if title == "" {
+			continue
+		}
+		key := item.game.Platform + "\x00" + title
+		if _, ok := byTitle[key]; !ok {
+			order = append(order, key)
+		}
+		byTitle[key] = append(byTitle[key], item)
+	}
+
+	for _, key := range order {
+		/*   Exact duplicates are reported above;     */
+		/*   one representative per content here      */
+		group := make([]*analyzed, 0)
+		seen := make(map[string]bool)
+		sortBest(byTitle[key], regions, true)
+		for _, item := range byTitle[key] {
+			if item.hash != "" && seen[item.hash] {
+				continue
+			}
+			seen[item.hash] = true
+			group = append(group, item)
+		}
+		if len(group) < 2 {
+			continue
+		}
+
+		sortBest(group, regions, false)
+		best := group[0]
+		platform, title, _ := strings.Cut(key, "\x00")
+		alt := AlternateGroup{Platform: platform, Title: title, Preferred: best.game.ID}
+		others := make([]string, 0, len(group)-1)
+		for _, item := range group {
+			alt.Games = append(alt.Games, item.entry)
+			if item != best {
+				others = append(others, item.game.ID)
+			}
+		}
+		r.Alternates = append(r.Alternates, alt)
+		r.Actions = append(r.Actions, SuggestedAction{
+			Action: ActionPrefer, GameID: best.game.ID, Path: best.game.Path, Alternatives: others,
+			Reason: preferReason(best, len(group), regions),
+		})
+	}
+}
+
+func preferReason(best *analyzed, versions int, regions []string) string {
+	reason := fmt.Sprintf("best of %d versions", versions)
+	if regionRank(best.entry.Regions, regions) < len(regions) {
+		reason += " for region preference " + strings.Join(regions, " > ")
+	}
+	if best.entry.Revision != "" {
+		reason += ", revision " + best.entry.Revision
+	}
+	if best.problems > 0 {
+		reason += ", though it has problems of its own"
+	}
+	return reason
+}
+
+/**************************************************/
+/*                                                */
+/*                REPLACEMENTS                    */
+/*                                                */
+/**************************************************/
+
+func (r *AnalysisReport) addReplacements(items []*analyzed) {
+	details := make(map[string][]string)
+	for _, p := range r.Problems {
+		details[p.Path] = append(details[p.Path], p.Detail)
+	}
+	for _, item := range items {
+		if item.problems == 0 {
+			continue
+		}
+		r.Actions = append(r.Actions, SuggestedAction{
+			Action: ActionReplace, GameID: item.game.ID, Path: item.game.Path,
+			Reason: strings.Join(details[item.game.Path], "; "),
+		})
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                  RANKING                       */
+/*                                                */
+/**************************************************/
+
+/*   Damage and bad-dump flags outweigh region; */
+/*   region outweighs revision. userFirst puts  */
+/*   favorites and play history ahead of all    */
+func sortBest(items []*analyzed, regions []string, userFirst bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if userFirst {
+			if a.game.Favorite != b.game.Favorite {
+				return a.game.Favorite
+			}
+			if a.game.PlayCount != b.game.PlayCount {
+				return a.game.PlayCount > b.game.PlayCount
+			}
+		}
+		if (a.problems == 0) != (b.problems == 0) {
+			return a.problems == 0
+		}
+		if badA, badB := badDump(a.entry.Flags), badDump(b.entry.Flags); badA != badB {
+			return !badA
+		}
+		if ra, rb := regionRank(a.entry.Regions, regions), regionRank(b.entry.Regions, regions); ra != rb {
+			return ra < rb
+		}
+		if va, vb := containsString(a.entry.Flags, "verified"), containsString(b.entry.Flags, "verified"); va != vb {
+			return va
+		}
+		if a.entry.Revision != b.entry.Revision {
+			return compareRevision(a.entry.Revision, b.entry.Revision) > 0
+		}
+		return a.game.Path < b.game.Path
+	})
+}
+
+/*   Index of the best preferred region; past   */
+/*   the end when none match                    */
+func regionRank(have, prefer []string) int {
+	best := len(prefer)
+	for _, region := range have {
+		for i, want := range prefer {
+			if strings.EqualFold(region, want) && i < best {
+				best = i
+			}
+		}
+	}
+	return best
+}
+
+func badDump(flags []string) bool {
+	for _, flag := range flags {
+		if flag != "verified" && flag != "alternate" {
+			return true
+		}
+	}
+	return false
+}
+
+/*   "Rev 2" > "Rev 1" > "" and "v1.10" > "v1.9" */
+func compareRevision(a, b string) int {
+	pa, pb := revisionParts(a), revisionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y string
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if len(x) != len(y) && isDigits(x) && isDigits(y) {
+			return len(x) - len(y)
+		}
+		if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func revisionParts(rev string) []string {
+	rev = strings.TrimPrefix(strings.ToLower(rev), "rev ")
+	rev = strings.TrimPrefix(rev, "v")
+	return strings.FieldsFunc(rev, func(r rune) bool { return r == '.' || r == ' ' })
+}
+
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+/**************************************************/
+/*                                                */
+/*             FILE NAME PARSING                  */
+/*                                                */
+/**************************************************/
+
+var (
+	tagPattern      = regexp.MustCompile(`[(\[]([^)\]]*)[)\]]`)
+	revisionPattern = regexp.MustCompile(`(?i)^(rev [0-9a-z.]+|v[0-9][0-9a-z.]*)$`)
+	nonAlnumPattern = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+var regionNames = map[string]string{
+	"usa": "USA", "world": "World", "europe": "Europe", "japan": "Japan",
+	"germany": "Germany", "france": "France", "spain": "Spain", "italy": "Italy",
+	"australia": "Australia", "korea": "Korea", "brazil": "Brazil", "asia": "Asia",
+	"canada": "Canada", "china": "China", "netherlands": "Netherlands", "sweden": "Sweden",
+}
+
+/*   GoodTools single letter codes, as in (U)   */
+/*   or combined as in (JU)                     */
+var regionCodes = map[rune]string{
+	'U': "USA", 'W': "World", 'E': "Europe", 'J': "Japan", 'G': "Germany", 'F': "France",
+	'S': "Spain", 'I': "Italy", 'A': "Australia", 'K': "Korea", 'B': "Brazil", 'C': "China",
+}
+
+/*   GoodTools [x] dump codes and No-Intro       */
+/*   status tags                                 */
+var dumpFlags = map[string]string{
+	"!": "verified", "b": "bad_dump", "o": "overdump", "h": "hack", "t": "trainer",
+	"f": "fixed", "p": "pirate", "a": "alternate",
+	"beta": "beta", "proto": "prototype", "prototype": "prototype", "demo": "demo",
+	"sample": "demo", "unl": "unlicensed", "hack": "hack", "pirate": "pirate",
+}
+
+func describeROM(game GameInfo) RomEntry {
+	entry := RomEntry{GameID: game.ID, Title: game.Title, Path: game.Path, Platform: game.Platform, Size: game.Size}
+	name := filepath.Base(game.Path)
+
+	for _, match := range tagPattern.FindAllStringSubmatch(name, -1) {
+		tag := strings.TrimSpace(match[1])
+		switch {
+		case revisionPattern.MatchString(tag):
+			entry.Revision = tag
+		case match[0][0] == '[':
+			if flag := dumpFlag(tag); flag != "" {
+				entry.Flags = appendUnique(entry.Flags, flag)
+			}
+		default:
+			if regions := parseRegions(tag); len(regions) > 0 {
+				for _, region := range regions {
+					entry.Regions = appendUnique(entry.Regions, region)
+				}
+			} else if flag := dumpFlags[strings.ToLower(strings.SplitN(tag, " ", 2)[0])]; flag != "" {
+				entry.Flags = appendUnique(entry.Flags, flag)
+			}
+		}
+	}
+	return entry
+}
+
+/*   "[b1]" and "[T+Eng]" keep only their code   */
+func dumpFlag(tag string) string {
+	switch {
+	case tag == "!":
+		return dumpFlags["!"]
+	case strings.HasPrefix(tag, "T+") || strings.HasPrefix(tag, "T-"):
+		return "translation"
+	case tag == "":
+		return ""
+	}
+	return dumpFlags[strings.ToLower(tag[:1])]
+}
+
+func parseRegions(tag string) []string {
+	regions := make([]string, 0)
+	for _, part := range strings.Split(tag, ",") {
+		if region, ok := regionNames[strings.ToLower(strings.TrimSpace(part))]; ok {
+			regions = append(regions, region)
+		}
+	}
+	if len(regions) > 0 {
+		return regions
+	}
+	if len(tag) > 4 || strings.ToUpper(tag) != tag {
+		return nil
+	}
+	for _, code := range tag {
+		region, ok := regionCodes[code]
+		if !ok {
+			return nil
+		}
+		regions = append(regions, region)
+	}
+	return regions
+}
+
+/*   "Legend of Zelda, The (USA) (Rev 1).nes"    */
+/*   and "The_Legend_of_Zelda [!].nes" both      */
+/*   become "the legend of zelda"                */
+func normalizeTitle(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name = strings.ToLower(tagPattern.ReplaceAllString(name, " "))
+	name = strings.TrimSpace(nonAlnumPattern.ReplaceAllString(name, " "))
+	for _, article := range []string{"the", "a", "an"} {
+		if strings.HasSuffix(name, " "+article) {
+			name = article + " " + strings.TrimSuffix(name, " "+article)
+		}
+	}
+	return name
+}
+
+func appendUnique(values []string, value string) []string {
+	if containsString(values, value) {
+		return values
+	}
+	return append(values, value)
+}
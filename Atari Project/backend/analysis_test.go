@@ -0,0 +1,396 @@
+/**************************************/
+/*                                    */
+/*     Duplicate Analysis Tests       */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package library
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+/**************************************************/
+/*                                                */
+/*             FILE NAME PARSING                  */
+/*                                                */
+/**************************************************/
+
+func TestDescribeROM(t *testing.T) {
+	tests := []struct {
+		name     string
+		regions  []string
+		revision string
+		flags    []string
+	}{
+		/*   No-Intro   */
+		{"Super Mario Bros. (World).nes", []string{"World"}, "", nil},
+		{"Legend of Zelda, The (USA) (Rev 1).nes", []string{"USA"}, "Rev 1", nil},
+		{"Sonic the Hedgehog (USA, Europe) (v1.1).md", []string{"USA", "Europe"}, "v1.1", nil},
+		{"Mother (Japan) (Proto).nes", []string{"Japan"}, "", []string{"prototype"}},
+		{"Star Fox (USA) (Beta 2).sfc", []string{"USA"}, "", []string{"beta"}},
+		{"Tetris (Japan) (En,Ja).gb", []string{"Japan"}, "", nil},
+		{"Action 52 (USA) (Unl).nes", []string{"USA"}, "", []string{"unlicensed"}},
+
+		/*   GoodTools   */
+		{"Super Mario Bros (JU) [!].nes", []string{"Japan", "USA"}, "", []string{"verified"}},
+		{"Zelda (U) [b1].nes", []string{"USA"}, "", []string{"bad_dump"}},
+		{"Contra (U) [o1][h2].nes", []string{"USA"}, "", []string{"overdump", "hack"}},
+		{"Metroid (E) [T+Eng1.0].nes", []string{"Europe"}, "", []string{"translation"}},
+		{"Castlevania (U) [a1] [!].nes", []string{"USA"}, "", []string{"alternate", "verified"}},
+		{"Mega Man (U) (V1.1) [p1].nes", []string{"USA"}, "V1.1", []string{"pirate"}},
+
+		/*   Lowercase codes are not regions   */
+		{"Pac-Man (u).nes", nil, "", nil},
+		{"Plain Name.nes", nil, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := GameInfo{ID: "id", Title: "Title", Path: filepath.Join("roms", tt.name), Platform: "NES", Size: 40976}
+			want := RomEntry{
+				GameID: "id", Title: "Title", Path: game.Path, Platform: "NES", Size: 40976,
+				Regions: tt.regions, Revision: tt.revision, Flags: tt.flags,
+			}
+			if got := describeROM(game); !reflect.DeepEqual(got, want) {
+				t.Errorf("describeROM = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseRegions(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"USA", []string{"USA"}},
+		{"usa", []string{"USA"}},
+		{"USA, Europe", []string{"USA", "Europe"}},
+		{"Japan, Korea", []string{"Japan", "Korea"}},
+		{"USA, Narnia", []string{"USA"}},
+		{"U", []string{"USA"}},
+		{"JU", []string{"Japan", "USA"}},
+		{"UEJ", []string{"USA", "Europe", "Japan"}},
+		{"UX", nil},
+		{"u", nil},
+		{"PAL", nil},
+		{"Rev 1", nil},
+		{"En,Fr,De", nil},
+		{"Beta", nil},
+		{"JUEBK", nil},
+	}
+	for _, tt := range tests {
+		if got := parseRegions(tt.tag); !slices.Equal(got, tt.want) {
+			t.Errorf("parseRegions(%q) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeTitle(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"Legend of Zelda, The (USA) (Rev 1).nes", "the legend of zelda"},
+		{"The_Legend_of_Zelda [!].nes", "the legend of zelda"},
+		{"roms/nes/Legend of Zelda, The (U) [b1].nes", "the legend of zelda"},
+		{"Bug's Life, A (USA).n64", "a bug s life"},
+		{"Super Mario Bros. 3 (USA) (Rev 1).nes", "super mario bros 3"},
+		{"Street Fighter II' - Champion Edition (Japan).md", "street fighter ii champion edition"},
+		{"Theme Park (Europe).md", "theme park"},
+		{"(Beta).nes", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeTitle(tt.path); got != tt.want {
+			t.Errorf("normalizeTitle(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCompareRevision(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int /*   sign only   */
+	}{
+		{"Rev 2", "Rev 1", 1},
+		{"Rev 1", "", 1},
+		{"", "Rev 1", -1},
+		{"", "", 0},
+		{"Rev B", "Rev A", 1},
+		{"rev 1", "Rev 1", 0},
+		{"v1.10", "v1.9", 1},
+		{"v1.9", "v1.10", -1},
+		{"v2", "v1.9", 1},
+		{"v1.1", "v1.1", 0},
+		{"V1.1", "v1.1", 0},
+		{"Rev 1.1", "Rev 1", 1},
+	}
+	for _, tt := range tests {
+		got := compareRevision(tt.a, tt.b)
+		if (got > 0) != (tt.want > 0) || (got < 0) != (tt.want < 0) {
+			t.Errorf("compareRevision(%q, %q) = %d, want sign of %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                  RANKING                       */
+/*                                                */
+/**************************************************/
+
+type testROM struct {
+	name     string
+	hash     string
+	problems int
+	favorite bool
+	plays    int
+}
+
+func analyzedROMs(platform string, roms []testROM) []*analyzed {
+	items := make([]*analyzed, 0, len(roms))
+	for _, rom := range roms {
+		game := GameInfo{
+			ID: rom.name, Title: rom.name, Path: rom.name, Platform: platform, Size: 40976,
+			Favorite: rom.favorite, PlayCount: rom.plays,
+		}
+		items = append(items, &analyzed{game: game, entry: describeROM(game), hash: rom.hash, problems: rom.problems})
+	}
+	return items
+}
+
+func itemNames(items []*analyzed) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.game.Path)
+	}
+	return names
+}
+
+func TestSortBest(t *testing.T) {
+	tests := []struct {
+		name      string
+		regions   []string
+		userFirst bool
+		roms      []testROM
+		want      []string
+	}{
+		{
+			"region preference", DefaultRegions, false,
+			[]testROM{{name: "G (Japan).nes"}, {name: "G (Europe).nes"}, {name: "G (USA).nes"}, {name: "G (World).nes"}},
+			[]string{"G (USA).nes", "G (World).nes", "G (Europe).nes", "G (Japan).nes"},
+		},
+		{
+			"custom preference", []string{"Japan", "USA"}, false,
+			[]testROM{{name: "G (U).nes"}, {name: "G (Europe).nes"}, {name: "G (J).nes"}},
+			[]string{"G (J).nes", "G (U).nes", "G (Europe).nes"},
+		},
+		{
+			"best region of several", DefaultRegions, false,
+			[]testROM{{name: "G (Europe).nes"}, {name: "G (Japan, USA).nes"}},
+			[]string{"G (Japan, USA).nes", "G (Europe).nes"},
+		},
+		{
+			"bad dump outweighs region", DefaultRegions, false,
+			[]testROM{{name: "G (U) [b1].nes"}, {name: "G (J).nes"}},
+			[]string{"G (J).nes", "G (U) [b1].nes"},
+		},
+		{
+			"hack outweighs region", DefaultRegions, false,
+			[]testROM{{name: "G (U) [h1].nes"}, {name: "G (J).nes"}},
+			[]string{"G (J).nes", "G (U) [h1].nes"},
+		},
+		{
+			"alternate is not a bad dump", DefaultRegions, false,
+			[]testROM{{name: "G (J).nes"}, {name: "G (U) [a1].nes"}},
+			[]string{"G (U) [a1].nes", "G (J).nes"},
+		},
+		{
+			"damage outweighs everything", DefaultRegions, false,
+			[]testROM{{name: "G (U) [!].nes", problems: 1}, {name: "G (J) [b1].nes"}},
+			[]string{"G (J) [b1].nes", "G (U) [!].nes"},
+		},
+		{
+			"verified beats unverified", DefaultRegions, false,
+			[]testROM{{name: "G (U).nes"}, {name: "G (U) [!].nes"}},
+			[]string{"G (U) [!].nes", "G (U).nes"},
+		},
+		{
+			"region outweighs revision", DefaultRegions, false,
+			[]testROM{{name: "G (Japan) (Rev 2).nes"}, {name: "G (USA).nes"}},
+			[]string{"G (USA).nes", "G (Japan) (Rev 2).nes"},
+		},
+		{
+			"newest revision first", DefaultRegions, false,
+			[]testROM{{name: "G (USA).nes"}, {name: "G (USA) (Rev 1).nes"}, {name: "G (USA) (Rev 2).nes"}},
+			[]string{"G (USA) (Rev 2).nes", "G (USA) (Rev 1).nes", "G (USA).nes"},
+		},
+		{
+			"path breaks ties", DefaultRegions, false,
+			[]testROM{{name: "b/G (USA).nes"}, {name: "a/G (USA).nes"}},
+			[]string{"a/G (USA).nes", "b/G (USA).nes"},
+		},
+		{
+			"favorite ignored for alternates", DefaultRegions, false,
+			[]testROM{{name: "G (J).nes", favorite: true}, {name: "G (U).nes"}},
+			[]string{"G (U).nes", "G (J).nes"},
+		},
+		{
+			"favorite first for duplicates", DefaultRegions, true,
+			[]testROM{{name: "G (U).nes"}, {name: "G (J).nes", favorite: true}},
+			[]string{"G (J).nes", "G (U).nes"},
+		},
+		{
+			"play count next for duplicates", DefaultRegions, true,
+			[]testROM{{name: "G (U).nes", plays: 1}, {name: "G (J).nes", plays: 5}, {name: "G (E).nes", favorite: true}},
+			[]string{"G (E).nes", "G (J).nes", "G (U).nes"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := analyzedROMs("NES", tt.roms)
+			sortBest(items, tt.regions, tt.userFirst)
+			if got := itemNames(items); !slices.Equal(got, tt.want) {
+				t.Errorf("order %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*          DUPLICATES AND ALTERNATES             */
+/*                                                */
+/**************************************************/
+
+func TestDuplicateAndAlternateGroups(t *testing.T) {
+	items := append(analyzedROMs("NES", []testROM{
+		{name: "Zelda (USA).nes", hash: "h1"},
+		{name: "copy/Zelda (USA).nes", hash: "h1", favorite: true},
+		{name: "Zelda (Japan).nes", hash: "h2"},
+		{name: "Zelda (USA) [b1].nes", hash: "h3"},
+		{name: "Metroid (Japan).nes", hash: "h4"},
+		{name: "Unreadable (USA).nes"},
+	}), analyzedROMs("SNES", []testROM{
+		{name: "Metroid (USA).sfc", hash: "h5"},
+	})...)
+
+	report := AnalysisReport{}
+	report.addDuplicates(items, DefaultRegions)
+	report.addAlternates(items, DefaultRegions)
+
+	/*   The favorite copy is kept   */
+	if len(report.Duplicates) != 1 {
+		t.Fatalf("duplicates %+v, want one group", report.Duplicates)
+	}
+	dup := report.Duplicates[0]
+	if dup.Hash != "h1" || dup.Keep != "copy/Zelda (USA).nes" || len(dup.Games) != 2 {
+		t.Errorf("duplicate group %+v, want h1 keeping the favorite", dup)
+	}
+	if report.ReclaimableBytes != 40976 {
+		t.Errorf("ReclaimableBytes = %d, want 40976", report.ReclaimableBytes)
+	}
+
+	/*   One copy of h1 stands in for both; the     */
+	/*   Metroids are on different platforms        */
+	if len(report.Alternates) != 1 {
+		t.Fatalf("alternates %+v, want one group", report.Alternates)
+	}
+	alt := report.Alternates[0]
+	games := make([]string, 0, len(alt.Games))
+	for _, entry := range alt.Games {
+		games = append(games, entry.Path)
+	}
+	want := []string{"copy/Zelda (USA).nes", "Zelda (Japan).nes", "Zelda (USA) [b1].nes"}
+	if alt.Platform != "NES" || alt.Title != "zelda" || alt.Preferred != want[0] || !slices.Equal(games, want) {
+		t.Errorf("alternate group %s %q preferring %s: %v, want %v", alt.Platform, alt.Title, alt.Preferred, games, want)
+	}
+
+	actions := make([]string, 0, len(report.Actions))
+	for _, action := range report.Actions {
+		actions = append(actions, action.Action+" "+action.GameID)
+	}
+	wantActions := []string{"remove Zelda (USA).nes", "prefer copy/Zelda (USA).nes"}
+	if !slices.Equal(actions, wantActions) {
+		t.Errorf("actions %v, want %v", actions, wantActions)
+	}
+	if alts := report.Actions[1].Alternatives; !slices.Equal(alts, want[1:]) {
+		t.Errorf("prefer alternatives %v, want %v", alts, want[1:])
+	}
+}
+
+/*   Reads real files: hashes, header checks    */
+/*   and the replace suggestions they lead to   */
+func TestAnalyze(t *testing.T) {
+	dir := t.TempDir()
+	good := append(inesHeader(1, 1, 0, 0, 0), make([]byte, 16384+8192)...)
+	files := map[string][]byte{
+		"Zelda (USA).nes":    good,
+		"Zelda (USA) 2.nes":  good,
+		"Zelda (Japan).nes":  good[:len(good)-100],
+		"Metroid (USA).nes":  []byte("not a rom"),
+		"Kid Icarus (E).nes": append(inesHeader(1, 0, 0, 0, 0), make([]byte, 16384)...),
+	}
+	lib := NewLibrary(filepath.Join(dir, "library.json"))
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		lib.Games = append(lib.Games, GameInfo{ID: name, Title: name, Path: path, Platform: "NES"})
+	}
+	lib.Games = append(lib.Games, GameInfo{ID: "gone", Path: filepath.Join(dir, "Gone (USA).nes"), Platform: "NES"})
+
+	report, err := lib.Analyze(context.Background(), AnalysisOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.GamesAnalyzed != 6 || !slices.Equal(report.PreferRegions, DefaultRegions) {
+		t.Errorf("analyzed %d with %v, want 6 with the defaults", report.GamesAnalyzed, report.PreferRegions)
+	}
+	if len(report.Duplicates) != 1 || report.ReclaimableBytes != int64(len(good)) {
+		t.Errorf("duplicates %+v reclaiming %d, want one of %d bytes", report.Duplicates, report.ReclaimableBytes, len(good))
+	}
+
+	problems := make(map[string]string)
+	for _, p := range report.Problems {
+		problems[p.GameID] = p.Kind
+	}
+	wantProblems := map[string]string{
+		"Zelda (Japan).nes": ProblemBadSize,
+		"Metroid (USA).nes": ProblemBadHeader,
+		"gone":              ProblemUnreadable,
+	}
+	if !reflect.DeepEqual(problems, wantProblems) {
+		t.Errorf("problems %v, want %v", problems, wantProblems)
+	}
+
+	replace := 0
+	for _, action := range report.Actions {
+		if action.Action == ActionReplace {
+			replace++
+		}
+	}
+	if replace != len(wantProblems) {
+		t.Errorf("%d replace actions, want %d", replace, len(wantProblems))
+	}
+}
+
+func TestAnalyzeCancelled(t *testing.T) {
+	lib := NewLibrary(filepath.Join(t.TempDir(), "library.json"))
+	lib.Games = []GameInfo{{ID: "a", Path: "a.nes", Platform: "NES"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := lib.Analyze(ctx, AnalysisOptions{}); err != context.Canceled {
+		t.Errorf("Analyze = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,198 @@
+/**************************************/
+/*                                    */
+/*    IPC Authentication - Go Backend */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+/**************************************************/
+/*                                                */
+/*              PERMISSION SCOPES                 */
+/*                                                */
+/**************************************************/
+
+type Scope string
+
+const (
+	ScopeRead   Scope = "read"
+	ScopeLaunch Scope = "launch"
+	ScopePlayer Scope = "player"
+	ScopeAdmin  Scope = "admin"
+)
+
+/*     Scopes implied by a granted scope         */
+var scopeImplies = map[Scope][]Scope{
+	ScopeRead:   {ScopeRead},
+	ScopeLaunch: {ScopeLaunch},
+	ScopePlayer: {ScopePlayer, ScopeRead, ScopeLaunch},
+	ScopeAdmin:  {ScopeAdmin, ScopePlayer, ScopeRead, ScopeLaunch},
+}
+
+/*    Message types allowed before the client    */
+/*    has authenticated; status then answers     */
+/*    with version and liveness only             */
+var publicMessages = map[string]bool{
+	MsgTypeHello:  true,
+	MsgTypeAuth:   true,
+	MsgTypeStatus: true,
+}
+
+/*   Types without a spec need admin             */
+func (s *IPCServer) requiredScope(msgType string) Scope {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if spec, ok := s.specs[msgType]; ok {
+		return spec.Scope
+	}
+	return ScopeAdmin
+}
+
+func hasScope(granted []Scope, required Scope) bool {
+	for _, g := range granted {
+		for _, implied := range scopeImplies[g] {
+			if implied == required {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/**************************************************/
+/*                                                */
+/*              TOKEN STRUCTURES                  */
+/*                                                */
+/**************************************************/
+
+type Token struct {
+	Name   string  `json:"name"`
+	Token  string  `json:"token"`
+	Scopes []Scope `json:"scopes"`
+}
+
+type tokenFile struct {
+	Tokens []Token `json:"tokens"`
+}
+
+type AuthPayload struct {
+	Token string `json:"token"`
+}
+
+type Authenticator struct {
+	tokens []Token
+}
+
+/**************************************************/
+/*                                                */
+/*             LOAD / CREATE TOKENS               */
+/*                                                */
+/**************************************************/
+
+func LoadTokens(path string) (*Authenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file tokenFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid token file %s: %w", path, err)
+	}
+
+	for _, t := range file.Tokens {
+		if t.Token == "" {
+			return nil, fmt.Errorf("token %q in %s is empty", t.Name, path)
+		}
+		for _, scope := range t.Scopes {
+			if _, ok := scopeImplies[scope]; !ok {
+				return nil, fmt.Errorf("token %q in %s has unknown scope %q", t.Name, path, scope)
+			}
+		}
+	}
+	return &Authenticator{tokens: file.Tokens}, nil
+}
+
+/*   Creates a secrets file holding a single     */
+/*   admin token when none exists yet            */
+func EnsureTokenFile(path string) (*Authenticator, error) {
+	if _, err := os.Stat(path); err == nil {
+		return LoadTokens(path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	secret, err := GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	file := tokenFile{Tokens: []Token{
+		{Name: "local", Token: secret, Scopes: []Scope{ScopeAdmin}},
+	}}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, err
+	}
+	return &Authenticator{tokens: file.Tokens}, nil
+}
+
+func GenerateToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+/**************************************************/
+/*                                                */
+/*               AUTHENTICATE                     */
+/*                                                */
+/**************************************************/
+
+/*   Looks a secret up by token name, for local  */
+/*   tools that share the backend's secrets file */
+func (a *Authenticator) Token(name string) (string, bool) {
+	for _, t := range a.tokens {
+		if t.Name == name {
+			return t.Token, true
+		}
+	}
+	return "", false
+}
+
+func (a *Authenticator) Authenticate(secret string) (*Token, bool) {
+	if secret == "" {
+		return nil, false
+	}
+	var match *Token
+	for i := range a.tokens {
+		/*   Compare every token to keep timing flat   */
+		if subtle.ConstantTimeCompare([]byte(a.tokens[i].Token), []byte(secret)) == 1 {
+			match = &a.tokens[i]
+		}
+	}
+	return match, match != nil
+}
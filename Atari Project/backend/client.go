@@ -0,0 +1,263 @@
+/**************************************/
+/*                                    */
+/*      IPC Client - Go Backend       */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"retro-gaming-ui/backend/server"
+)
+
+/**************************************************/
+/*                                                */
+/*                 CLIENT TYPES                   */
+/*                                                */
+/**************************************************/
+
+/*   A failed response from the backend; Code    */
+/*   is one of the server.ErrCode* values        */
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s (%s)", e.Message, e.Code)
+}
+
+var ErrClosed = errors.New("connection to backend closed")
+
+/*   Event data is left raw for the caller to    */
+/*   decode by topic                             */
+type Event struct {
+	Topic string          `json:"topic"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+type response struct {
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Code    string          `json:"code,omitempty"`
+}
+
+type Client struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+	nextID  atomic.Uint64
+	events  chan Event
+
+	mu      sync.Mutex
+	pending map[string]chan response
+	err     error
+	done    chan struct{}
+}
+
+/**************************************************/
+/*                                                */
+/*                DIAL / CLOSE                    */
+/*                                                */
+/**************************************************/
+
+func Dial(ctx context.Context, addr string) (*Client, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		conn:    conn,
+		events:  make(chan Event, 64),
+		pending: make(map[string]chan response),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop()
+	return c, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+/*   Closed when the connection goes away; slow  */
+/*   readers lose events rather than stall calls */
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+/**************************************************/
+/*                                                */
+/*                    CALLS                       */
+/*                                                */
+/**************************************************/
+
+/*   Sends one request and waits for the reply   */
+/*   with the same ID; out may be nil            */
+func (c *Client) Call(ctx context.Context, msgType string, payload, out interface{}) error {
+	req := server.Request{Type: msgType, ID: strconv.FormatUint(c.nextID.Add(1), 10)}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		req.Payload = raw
+	}
+	line, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	reply := make(chan response, 1)
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return c.err
+	}
+	c.pending[req.ID] = reply
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, req.ID)
+		c.mu.Unlock()
+	}()
+
+	c.writeMu.Lock()
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetWriteDeadline(deadline)
+	} else {
+		c.conn.SetWriteDeadline(time.Time{})
+	}
+	_, err = c.conn.Write(append(line, '\n'))
+	c.writeMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case resp := <-reply:
+		if !resp.Success {
+			return &Error{Code: resp.Code, Message: resp.Error}
+		}
+		if out != nil && len(resp.Data) > 0 {
+			return json.Unmarshal(resp.Data, out)
+		}
+		return nil
+	case <-c.done:
+		return c.closeErr()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+/*   Negotiates the protocol and, with a token,  */
+/*   authenticates in the same round trip        */
+func (c *Client) Hello(ctx context.Context, name, token string) (*server.Capabilities, error) {
+	var caps server.Capabilities
+	payload := server.HelloPayload{ProtocolVersion: server.ProtocolVersion, Client: name, Token: token}
+	if err := c.Call(ctx, server.MsgTypeHello, payload, &caps); err != nil {
+		return nil, err
+	}
+	return &caps, nil
+}
+
+func (c *Client) Subscribe(ctx context.Context, topics ...string) error {
+	return c.Call(ctx, server.MsgTypeSubscribe, server.SubscribePayload{Topics: topics}, nil)
+}
+
+/**************************************************/
+/*                                                */
+/*                  READ LOOP                     */
+/*                                                */
+/**************************************************/
+
+func (c *Client) readLoop() {
+	reader := bufio.NewReader(c.conn)
+	var err error
+	for {
+		var line []byte
+		line, err = reader.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+
+		var resp response
+		if json.Unmarshal(line, &resp) != nil {
+			continue
+		}
+		if resp.Type == server.MsgTypeEvent && resp.ID == "" {
+			var event Event
+			if json.Unmarshal(resp.Data, &event) == nil {
+				select {
+				case c.events <- event:
+				default:
+				}
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		reply, ok := c.pending[resp.ID]
+		c.mu.Unlock()
+		if ok {
+			reply <- resp
+		}
+	}
+
+	c.mu.Lock()
+	c.err = ErrClosed
+	if err != nil && !errors.Is(err, net.ErrClosed) {
+		c.err = fmt.Errorf("%w: %v", ErrClosed, err)
+	}
+	c.mu.Unlock()
+	close(c.done)
+	close(c.events)
+}
+
+func (c *Client) closeErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+/**************************************************/
+/*                                                */
+/*                 LOCAL TOKENS                   */
+/*                                                */
+/**************************************************/
+
+/*   Reads a named token from the backend's      */
+/*   secrets file, as written by EnsureTokenFile */
+func LoadToken(path, name string) (string, error) {
+	auth, err := server.LoadTokens(path)
+	if err != nil {
+		return "", err
+	}
+	token, ok := auth.Token(name)
+	if !ok {
+		return "", fmt.Errorf("no token named %q in %s", name, path)
+	}
+	return token, nil
+}
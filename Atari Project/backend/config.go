@@ -0,0 +1,405 @@
+/**************************************/
+/*                                    */
+/*   Backend Configuration - Go       */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"retro-gaming-ui/backend/firmware"
+	"retro-gaming-ui/backend/library"
+	"retro-gaming-ui/backend/logging"
+)
+
+/**************************************************/
+/*                                                */
+/*                   DEFAULTS                     */
+/*                                                */
+/**************************************************/
+
+const (
+	DefaultIPCPort = 9847
+	AppDirName     = "retro-gaming-hub"
+	ConfigFile     = "config.json"
+	LibraryFile    = "library.json"
+	TokensFile     = "auth.json"
+	FirmwareDir    = "bios"
+	EnvPrefix      = "RETROHUB_"
+)
+
+/**************************************************/
+/*                                                */
+/*              CONFIG STRUCTURES                 */
+/*                                                */
+/**************************************************/
+
+/*   JSON durations are written as "30s", "5m"   */
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+type LimitsConfig struct {
+	MaxMessageSize int      `json:"max_message_size"`
+	IdleTimeout    Duration `json:"idle_timeout"`
+	ReadTimeout    Duration `json:"read_timeout"`
+	WriteTimeout   Duration `json:"write_timeout"`
+	MaxConnections int      `json:"max_connections"`
+	RateLimit      float64  `json:"rate_limit"`
+	RateBurst      int      `json:"rate_burst"`
+}
+
+type IPCConfig struct {
+	Listen     string       `json:"listen"`
+	TokensFile string       `json:"tokens_file"`
+	Limits     LimitsConfig `json:"limits"`
+}
+
+type MetricsConfig struct {
+	Listen string `json:"listen"`
+}
+
+type LibraryConfig struct {
+	Path      string             `json:"path"`
+	ScanPaths []library.ScanPath `json:"scan_paths"`
+}
+
+/*   Args may contain {rom}, {core}, {id} and    */
+/*   {platform}; without {rom} the game's path   */
+/*   is appended. Games can override any field   */
+/*   with a launch profile                       */
+type EmulatorConfig struct {
+	Command    string            `json:"command"`
+	Core       string            `json:"core,omitempty"`
+	Args       []string          `json:"args,omitempty"`
+	WorkingDir string            `json:"working_dir,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+	PreLaunch  string            `json:"pre_launch,omitempty"`
+	PostExit   string            `json:"post_exit,omitempty"`
+
+	/*   Registry file names this emulator cannot  */
+	/*   run without, e.g. "gba_bios.bin"          */
+	Firmware []string `json:"firmware,omitempty"`
+}
+
+/*   Searched in order, a few folders deep      */
+type FirmwareConfig struct {
+	Dirs []string `json:"dirs"`
+}
+
+type Config struct {
+	IPC       IPCConfig                 `json:"ipc"`
+	Metrics   MetricsConfig             `json:"metrics"`
+	Library   LibraryConfig             `json:"library"`
+	Emulators map[string]EmulatorConfig `json:"emulators"`
+	Firmware  FirmwareConfig            `json:"firmware"`
+	Log       logging.Options           `json:"log"`
+
+	/*   Where the config was resolved from      */
+	Dir  string `json:"-"`
+	File string `json:"-"`
+}
+
+func Default(dir string) *Config {
+	return &Config{
+		IPC: IPCConfig{
+			Listen:     fmt.Sprintf("127.0.0.1:%d", DefaultIPCPort),
+			TokensFile: filepath.Join(dir, TokensFile),
+			Limits: LimitsConfig{
+				MaxMessageSize: 1 << 20,
+				IdleTimeout:    Duration(5 * time.Minute),
+				ReadTimeout:    Duration(10 * time.Second),
+				WriteTimeout:   Duration(10 * time.Second),
+				MaxConnections: 32,
+				RateLimit:      50,
+				RateBurst:      100,
+			},
+		},
+		Library: LibraryConfig{
+			Path:      filepath.Join(dir, LibraryFile),
+			ScanPaths: []library.ScanPath{},
+		},
+		Emulators: map[string]EmulatorConfig{},
+		Firmware:  FirmwareConfig{Dirs: []string{filepath.Join(dir, FirmwareDir)}},
+		Log:       logging.DefaultOptions(),
+		Dir:       dir,
+		File:      filepath.Join(dir, ConfigFile),
+	}
+}
+
+func DefaultDir() string {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		base = "."
+	}
+	return filepath.Join(base, AppDirName)
+}
+
+/**************************************************/
+/*                                                */
+/*                   LOADER                       */
+/*    defaults < config file < env < flags        */
+/*                                                */
+/**************************************************/
+
+type Loader struct {
+	args    []string
+	flags   *flag.FlagSet
+	values  *Config
+	dirFlag string
+	cfgFlag string
+}
+
+/*   Registers backend flags on fs and parses    */
+/*   args; call Load afterwards (and again on    */
+/*   reload) to build the merged config          */
+func NewLoader(fs *flag.FlagSet, args []string) (*Loader, error) {
+	l := &Loader{args: args, flags: fs, values: Default("")}
+	v := l.values
+
+	fs.StringVar(&l.dirFlag, "config-dir", "", "configuration directory (default "+DefaultDir()+")")
+	fs.StringVar(&l.cfgFlag, "config", "", "config file (default <config-dir>/"+ConfigFile+")")
+	fs.StringVar(&v.IPC.Listen, "listen", "", "IPC listen address")
+	fs.StringVar(&v.IPC.TokensFile, "tokens-file", "", "auth tokens file")
+	fs.StringVar(&v.Metrics.Listen, "metrics-addr", "", "serve /metrics, /healthz and /readyz on this address")
+	fs.StringVar(&v.Library.Path, "library", "", "library.json path")
+	fs.Func("scan-path", "add a scan path (repeatable)", func(path string) error {
+		v.Library.ScanPaths = append(v.Library.ScanPaths, library.ScanPath{Path: path})
+		return nil
+	})
+	fs.StringVar(&v.Log.Level, "log-level", "", "log level: debug, info, warn or error")
+	fs.StringVar(&v.Log.Format, "log-format", "", "log output format: text or json")
+	fs.StringVar(&v.Log.File, "log-file", "", "write logs to this file instead of stderr")
+	fs.IntVar(&v.Log.MaxSizeMB, "log-max-size", 0, "rotate the log file after this many megabytes")
+	fs.IntVar(&v.Log.MaxBackups, "log-max-backups", 0, "rotated log files to keep")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Loader) Load() (*Config, error) {
+	dir := firstNonEmpty(l.dirFlag, os.Getenv(EnvPrefix+"CONFIG_DIR"), DefaultDir())
+	file := firstNonEmpty(l.cfgFlag, os.Getenv(EnvPrefix+"CONFIG"))
+	cfg, err := load(dir, file, l.cfgFlag != "")
+	if err != nil {
+		return nil, err
+	}
+	l.applyFlags(cfg)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+/*   Defaults < file < env, for tools that read  */
+/*   the backend's config without its flags;     */
+/*   empty arguments fall back to the defaults   */
+func LoadFile(dir, file string) (*Config, error) {
+	dir = firstNonEmpty(dir, os.Getenv(EnvPrefix+"CONFIG_DIR"), DefaultDir())
+	cfg, err := load(dir, firstNonEmpty(file, os.Getenv(EnvPrefix+"CONFIG")), file != "")
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func load(dir, file string, required bool) (*Config, error) {
+	cfg := Default(dir)
+	cfg.File = firstNonEmpty(file, cfg.File)
+
+	/*   Config file (optional unless named)     */
+	data, err := os.ReadFile(cfg.File)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", cfg.File, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && !required:
+	default:
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	applyEnv(cfg)
+	return cfg, nil
+}
+
+func applyEnv(cfg *Config) {
+	setFromEnv(&cfg.IPC.Listen, "LISTEN")
+	setFromEnv(&cfg.IPC.TokensFile, "TOKENS_FILE")
+	setFromEnv(&cfg.Metrics.Listen, "METRICS_ADDR")
+	setFromEnv(&cfg.Library.Path, "LIBRARY")
+	setFromEnv(&cfg.Log.Level, "LOG_LEVEL")
+	setFromEnv(&cfg.Log.Format, "LOG_FORMAT")
+	setFromEnv(&cfg.Log.File, "LOG_FILE")
+
+	if dirs := os.Getenv(EnvPrefix + "FIRMWARE_DIRS"); dirs != "" {
+		cfg.Firmware.Dirs = filepath.SplitList(dirs)
+	}
+	if paths := os.Getenv(EnvPrefix + "SCAN_PATHS"); paths != "" {
+		for _, path := range filepath.SplitList(paths) {
+			cfg.Library.ScanPaths = append(cfg.Library.ScanPaths, library.ScanPath{Path: path})
+		}
+	}
+}
+
+/*   Only flags given on the command line win    */
+func (l *Loader) applyFlags(cfg *Config) {
+	v := l.values
+	l.flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "listen":
+			cfg.IPC.Listen = v.IPC.Listen
+		case "tokens-file":
+			cfg.IPC.TokensFile = v.IPC.TokensFile
+		case "metrics-addr":
+			cfg.Metrics.Listen = v.Metrics.Listen
+		case "library":
+			cfg.Library.Path = v.Library.Path
+		case "scan-path":
+			cfg.Library.ScanPaths = append(cfg.Library.ScanPaths, v.Library.ScanPaths...)
+		case "log-level":
+			cfg.Log.Level = v.Log.Level
+		case "log-format":
+			cfg.Log.Format = v.Log.Format
+		case "log-file":
+			cfg.Log.File = v.Log.File
+		case "log-max-size":
+			cfg.Log.MaxSizeMB = v.Log.MaxSizeMB
+		case "log-max-backups":
+			cfg.Log.MaxBackups = v.Log.MaxBackups
+		}
+	})
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.IPC.Listen == "" {
+		return errors.New("ipc.listen must not be empty")
+	}
+	if _, err := logging.ParseLevel(cfg.Log.Level); err != nil {
+		return err
+	}
+	for platform, emu := range cfg.Emulators {
+		if emu.Command == "" {
+			return fmt.Errorf("emulators.%s.command must not be empty", platform)
+		}
+		if unknown := firmware.Unknown(platform, emu.Firmware); len(unknown) > 0 {
+			return fmt.Errorf("emulators.%s.firmware: unknown file %q", platform, unknown[0])
+		}
+	}
+	return nil
+}
+
+/*   Each platform's emulator firmware list, in  */
+/*   the form firmware.Check takes               */
+func (cfg *Config) RequiredFirmware() map[string][]string {
+	required := make(map[string][]string, len(cfg.Emulators))
+	for platform, emu := range cfg.Emulators {
+		required[platform] = emu.Firmware
+	}
+	return required
+}
+
+/**************************************************/
+/*                                                */
+/*                 HOT RELOAD                     */
+/*                                                */
+/**************************************************/
+
+/*   Keys that take effect without a restart     */
+var reloadableKeys = map[string]bool{
+	"log.level":          true,
+	"library.scan_paths": true,
+	"emulators":          true,
+	"firmware.dirs":      true,
+	"ipc.limits":         true,
+}
+
+type ReloadPlan struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"`
+}
+
+/*   Lists changed keys, split by whether they  */
+/*   can be applied live                         */
+func Diff(old, next *Config) ReloadPlan {
+	plan := ReloadPlan{Applied: []string{}, RestartRequired: []string{}}
+	compare := func(key string, a, b interface{}) {
+		if reflect.DeepEqual(a, b) {
+			return
+		}
+		if reloadableKeys[key] {
+			plan.Applied = append(plan.Applied, key)
+		} else {
+			plan.RestartRequired = append(plan.RestartRequired, key)
+		}
+	}
+
+	compare("ipc.listen", old.IPC.Listen, next.IPC.Listen)
+	compare("ipc.tokens_file", old.IPC.TokensFile, next.IPC.TokensFile)
+	compare("ipc.limits", old.IPC.Limits, next.IPC.Limits)
+	compare("metrics.listen", old.Metrics.Listen, next.Metrics.Listen)
+	compare("library.path", old.Library.Path, next.Library.Path)
+	compare("library.scan_paths", old.Library.ScanPaths, next.Library.ScanPaths)
+	compare("emulators", old.Emulators, next.Emulators)
+	compare("firmware.dirs", old.Firmware.Dirs, next.Firmware.Dirs)
+	compare("log.level", old.Log.Level, next.Log.Level)
+	compare("log.format", old.Log.Format, next.Log.Format)
+	compare("log.file", old.Log.File, next.Log.File)
+	return plan
+}
+
+/**************************************************/
+/*                                                */
+/*                  HELPERS                       */
+/*                                                */
+/**************************************************/
+
+func setFromEnv(target *string, name string) {
+	if value, ok := os.LookupEnv(EnvPrefix + name); ok && value != "" {
+		*target = value
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
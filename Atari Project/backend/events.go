@@ -0,0 +1,180 @@
+/**************************************/
+/*                                    */
+/*     IPC Event Broadcasting - Go    */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+/**************************************************/
+/*                                                */
+/*                 EVENT TOPICS                   */
+/*                                                */
+/**************************************************/
+
+const (
+	TopicAll            = "*"
+	TopicServerShutdown = "server.shutdown"
+	TopicLogRecord      = "logs.record"
+	TopicScanProgress   = "scan.progress"
+	TopicGameStarted    = "game.started"
+	TopicGameExited     = "game.exited"
+	TopicLibraryChanged = "library.changed"
+)
+
+/*   Scope needed to hear a topic family; the    */
+/*   rest need read                              */
+var topicScopes = map[string]Scope{
+	"logs": ScopeAdmin,
+}
+
+/*   Events waiting for a slow client; more are  */
+/*   dropped rather than stall the broadcaster   */
+const eventQueueSize = 256
+
+func topicScope(topic string) Scope {
+	family, _, _ := strings.Cut(topic, ".")
+	if scope, ok := topicScopes[family]; ok {
+		return scope
+	}
+	return ScopeRead
+}
+
+/*   Pushed to subscribers as a Response with    */
+/*   type "event" and the event as data          */
+type Event struct {
+	Topic string      `json:"topic"`
+	Data  interface{} `json:"data,omitempty"`
+}
+
+type SubscribePayload struct {
+	Topics []string `json:"topics"`
+}
+
+/**************************************************/
+/*                                                */
+/*            SUBSCRIBE / UNSUBSCRIBE             */
+/*                                                */
+/**************************************************/
+
+func (s *IPCServer) subscribe(client *Client, req Request) Response {
+	var payload SubscribePayload
+	if req.Payload != nil {
+		if err := json.Unmarshal(req.Payload, &payload); err != nil {
+			return Response{
+				Type: MsgTypeError, ID: req.ID, Success: false,
+				Error: fmt.Sprintf("Invalid subscribe payload: %v", err), Code: ErrCodeBadRequest,
+			}
+		}
+	}
+	if len(payload.Topics) == 0 {
+		payload.Topics = []string{TopicAll}
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	/*   "*" is always allowed; Broadcast skips     */
+	/*   the topics it does not cover               */
+	if req.Type == MsgTypeSubscribe {
+		for _, topic := range payload.Topics {
+			if topic != TopicAll && !hasScope(client.scopes, topicScope(topic)) {
+				return Response{
+					Type: MsgTypeError, ID: req.ID, Success: false,
+					Error: fmt.Sprintf("Forbidden: topic %s requires %s scope", topic, topicScope(topic)),
+					Code:  ErrCodeForbidden,
+				}
+			}
+		}
+	}
+	for _, topic := range payload.Topics {
+		if req.Type == MsgTypeUnsubscribe {
+			delete(client.topics, topic)
+		} else {
+			client.topics[topic] = true
+		}
+	}
+
+	topics := make([]string, 0, len(client.topics))
+	for topic := range client.topics {
+		topics = append(topics, topic)
+	}
+	return Response{
+		Type: MsgTypeSuccess, ID: req.ID, Success: true,
+		Data: map[string]interface{}{"topics": topics},
+	}
+}
+
+/*   "scan" matches "scan.progress" and so on.   */
+/*   Scopes are checked again here, so "*" and   */
+/*   older subscriptions never leak a topic      */
+func (c *Client) subscribed(topic string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !hasScope(c.scopes, topicScope(topic)) {
+		return false
+	}
+	for t := range c.topics {
+		if t == TopicAll || t == topic || strings.HasPrefix(topic, t+".") {
+			return true
+		}
+	}
+	return false
+}
+
+/**************************************************/
+/*                                                */
+/*                  BROADCAST                     */
+/*                                                */
+/**************************************************/
+
+func (s *IPCServer) Broadcast(topic string, data interface{}) {
+	s.mu.RLock()
+	targets := make([]*Client, 0, len(s.clients))
+	for _, client := range s.clients {
+		if client.subscribed(topic) {
+			targets = append(targets, client)
+		}
+	}
+	s.mu.RUnlock()
+
+	resp := Response{Type: MsgTypeEvent, Success: true, Data: Event{Topic: topic, Data: data}}
+	for _, client := range targets {
+		select {
+		case client.events <- resp:
+		default:
+			client.dropped.Add(1)
+		}
+	}
+}
+
+/*   One per client, so a slow reader only       */
+/*   stalls its own events. Drains what is       */
+/*   queued once the connection is done.         */
+func (s *IPCServer) writeEvents(client *Client) {
+	defer close(client.drained)
+	for {
+		select {
+		case resp := <-client.events:
+			s.sendResponse(client, resp)
+		case <-client.done:
+			for {
+				select {
+				case resp := <-client.events:
+					s.sendResponse(client, resp)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,503 @@
+/**************************************/
+/*                                    */
+/*    Library Export and Import       */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package library
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*           PORTABLE JSON DOCUMENT               */
+/*                                                */
+/*   {                                            */
+/*     "format":      "retro-gaming-hub/library", */
+/*     "version":     2,                          */
+/*     "exported_at": RFC 3339 time,              */
+/*     "scan_paths":  [ directory or ScanPath ],  */
+/*     "games": [ {                               */
+/*       "id", "title", "platform", "path",       */
+/*       "category", "description", "cover_path", */
+/*       "favorite":    bool,                     */
+/*       "tags":        [ string, ... ],          */
+/*       "play_count":  int,                      */
+/*       "last_played": RFC 3339 time,            */
+/*       "hash":  SHA-1 of the ROM, lowercase hex,*/
+/*       "crc32": CRC-32 of the ROM, 8 hex digits,*/
+/*       "size":  ROM size in bytes,              */
+/*       "play_seconds": int,                     */
+/*       "launch": launch profile, see profiles.go*/
+/*     } ]                                        */
+/*   }                                            */
+/*                                                */
+/*   hash is the identity used on import; id and  */
+/*   path only match within the same machine.     */
+/*   Version 1 documents (no format, no hashes)   */
+/*   are still accepted.                          */
+/*                                                */
+/**************************************************/
+
+const (
+	exportFormatName = "retro-gaming-hub/library"
+	exportVersion    = 2
+)
+
+type ExportDocument struct {
+	Format     string     `json:"format,omitempty"`
+	Version    int        `json:"version"`
+	ExportedAt time.Time  `json:"exported_at"`
+	ScanPaths  []ScanPath `json:"scan_paths"`
+	Games      []GameInfo `json:"games"`
+}
+
+type ExportOptions struct {
+	Platform  string
+	Favorites bool
+
+	/*   gamelist.xml paths under BaseDir are      */
+	/*   written relative to it                    */
+	BaseDir string
+}
+
+func (lib *Library) Export() ExportDocument {
+	return lib.exportDocument(ExportOptions{})
+}
+
+func (lib *Library) exportDocument(opts ExportOptions) ExportDocument {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
+
+	games := make([]GameInfo, 0, len(lib.Games))
+	for _, game := range lib.Games {
+		if opts.Platform != "" && game.Platform != opts.Platform {
+			continue
+		}
+		if opts.Favorites && !game.Favorite {
+			continue
+		}
+		games = append(games, game)
+	}
+	return ExportDocument{
+		Format:     exportFormatName,
+		Version:    exportVersion,
+		ExportedAt: time.Now(),
+		ScanPaths:  append([]ScanPath{}, lib.ScanPaths...),
+		Games:      games,
+	}
+}
+
+func (lib *Library) ExportJSON(w io.Writer) error {
+	return lib.ExportTo(w, FormatJSON, ExportOptions{})
+}
+
+func (lib *Library) ExportTo(w io.Writer, format Format, opts ExportOptions) error {
+	doc := lib.exportDocument(opts)
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	case FormatCSV:
+		return writeCSV(w, doc.Games)
+	case FormatGamelist:
+		return writeGamelist(w, doc.Games, opts.BaseDir)
+	case FormatLPL:
+		return writeLPL(w, doc.Games)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+/**************************************************/
+/*                                                */
+/*                IMPORT REPORT                   */
+/*                                                */
+/**************************************************/
+
+const (
+	PreferLocal    = "local"
+	PreferImported = "imported"
+)
+
+type ImportOptions struct {
+	/*   Relative paths are resolved against this  */
+	BaseDir string
+
+	/*   Conflict winner: PreferLocal (default)    */
+	/*   or PreferImported                         */
+	Prefer string
+
+	/*   Adopts launch profiles, whose hooks run   */
+	/*   as commands. Off by default; every one    */
+	/*   adopted is reported as a conflict         */
+	Launch bool
+}
+
+/*   A curated field set differently on both     */
+/*   sides; Kept says which value survived       */
+type Conflict struct {
+	GameID   string `json:"game_id"`
+	Title    string `json:"title"`
+	Field    string `json:"field"`
+	Local    string `json:"local"`
+	Imported string `json:"imported"`
+	Kept     string `json:"kept"`
+}
+
+type SkippedEntry struct {
+	Title  string `json:"title"`
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+type ImportReport struct {
+	Format    Format         `json:"format"`
+	Added     int            `json:"added"`
+	Updated   int            `json:"updated"`
+	Unchanged int            `json:"unchanged"`
+	Conflicts []Conflict     `json:"conflicts"`
+	Skipped   []SkippedEntry `json:"skipped"`
+}
+
+/**************************************************/
+/*                                                */
+/*                    IMPORT                      */
+/*   Matches by content hash, then CRC32, then    */
+/*   path or ID; entries that match nothing are   */
+/*   added if their file exists here              */
+/*                                                */
+/**************************************************/
+
+func (lib *Library) ImportJSON(r io.Reader) (ImportReport, error) {
+	return lib.ImportFrom(r, FormatJSON, ImportOptions{})
+}
+
+func (lib *Library) ImportFrom(r io.Reader, format Format, opts ImportOptions) (ImportReport, error) {
+	var doc ExportDocument
+	var err error
+	switch format {
+	case FormatJSON:
+		err = json.NewDecoder(r).Decode(&doc)
+	case FormatCSV:
+		doc.Games, err = readCSV(r)
+	case FormatGamelist:
+		doc.Games, err = readGamelist(r)
+	case FormatLPL:
+		doc.Games, err = readLPL(r)
+	default:
+		return ImportReport{}, fmt.Errorf("unknown import format %q", format)
+	}
+	if err != nil {
+		return ImportReport{}, fmt.Errorf("invalid %s import: %w", format, err)
+	}
+	report, err := lib.importDocument(doc, opts)
+	report.Format = format
+	return report, err
+}
+
+func (lib *Library) Import(doc ExportDocument) (ImportReport, error) {
+	report, err := lib.importDocument(doc, ImportOptions{})
+	report.Format = FormatJSON
+	return report, err
+}
+
+func (lib *Library) importDocument(doc ExportDocument, opts ImportOptions) (ImportReport, error) {
+	report := ImportReport{Conflicts: []Conflict{}, Skipped: []SkippedEntry{}}
+	if doc.Format != "" && doc.Format != exportFormatName {
+		return report, fmt.Errorf("not a library export: format is %q", doc.Format)
+	}
+	if doc.Version > exportVersion {
+		return report, fmt.Errorf("export version %d is newer than supported version %d", doc.Version, exportVersion)
+	}
+	if opts.Prefer == "" {
+		opts.Prefer = PreferLocal
+	}
+	if opts.Prefer != PreferLocal && opts.Prefer != PreferImported {
+		return report, fmt.Errorf("prefer must be %q or %q", PreferLocal, PreferImported)
+	}
+
+	incoming := make([]GameInfo, len(doc.Games))
+	for i, game := range doc.Games {
+		game.Path = resolvePath(game.Path, opts.BaseDir)
+		if !opts.Launch {
+			game.Launch = nil
+		}
+		incoming[i] = game
+	}
+
+	/*   Hash outside the lock: files that match   */
+	/*   nothing yet and local games without one.  */
+	/*   A hashed entry no local game has may      */
+	/*   still be on disk at its path              */
+	lib.hashMissing()
+	lib.mu.RLock()
+	known := make(map[string]bool, len(lib.Games))
+	for _, game := range lib.Games {
+		known[game.Hash] = true
+	}
+	lib.mu.RUnlock()
+
+	fresh := make(map[int]fileSum)
+	for i, game := range incoming {
+		if game.Path == "" || (game.Hash != "" && known[game.Hash]) {
+			continue
+		}
+		if sum, err := hashFile(game.Path); err == nil {
+			fresh[i] = sum
+		}
+	}
+
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+
+	byHash := make(map[string][]int)
+	byCRC := make(map[string][]int)
+	byPath := make(map[string]int)
+	byID := make(map[string]int)
+	for i, game := range lib.Games {
+		if game.Hash != "" {
+			byHash[game.Hash] = append(byHash[game.Hash], i)
+		}
+		if game.CRC32 != "" {
+			byCRC[strings.ToUpper(game.CRC32)] = append(byCRC[strings.ToUpper(game.CRC32)], i)
+		}
+		byPath[game.Path] = i
+		byID[game.ID] = i
+	}
+
+	for n, game := range incoming {
+		sum, hashedHere := fresh[n]
+		if game.Hash == "" && hashedHere {
+			game.Hash = sum.SHA1
+		}
+
+		var matches []int
+		switch {
+		case game.Hash != "":
+			matches = byHash[game.Hash]
+		case game.CRC32 != "":
+			matches = byCRC[strings.ToUpper(game.CRC32)]
+		}
+		if len(matches) == 0 && game.Hash == "" {
+			if i, ok := byPath[game.Path]; ok && game.Path != "" {
+				matches = []int{i}
+			} else if i, ok := byID[game.ID]; ok && game.ID != "" {
+				matches = []int{i}
+			}
+		}
+
+		if len(matches) == 0 {
+			if !hashedHere || sum.SHA1 != game.Hash {
+				reason := "no matching game in this library"
+				if hashedHere {
+					reason = "file at this path differs from the exported one"
+				}
+				report.Skipped = append(report.Skipped, SkippedEntry{
+					Title: game.Title, Path: game.Path, Reason: reason,
+				})
+				continue
+			}
+			added := newImportedGame(game, sum)
+			if added.Launch != nil {
+				report.Conflicts = append(report.Conflicts, launchConflict(added, nil, added.Launch, PreferImported))
+			}
+			lib.Games = append(lib.Games, added)
+			i := len(lib.Games) - 1
+			byHash[added.Hash] = append(byHash[added.Hash], i)
+			byPath[added.Path] = i
+			byID[added.ID] = i
+			report.Added++
+			continue
+		}
+
+		for _, i := range matches {
+			merged, conflicts := mergeImported(lib.Games[i], game, opts.Prefer)
+			report.Conflicts = append(report.Conflicts, conflicts...)
+			if reflect.DeepEqual(merged, lib.Games[i]) {
+				report.Unchanged++
+			} else {
+				lib.Games[i] = merged
+				report.Updated++
+			}
+		}
+	}
+
+	for _, sp := range doc.ScanPaths {
+		sp = sp.normalized()
+		if checkScanDir(sp.Path) == nil && sp.Validate() == nil && lib.scanPathIndex(sp.Path) < 0 {
+			lib.ScanPaths = append(lib.ScanPaths, sp)
+		}
+	}
+
+	lib.recountUnlocked()
+	return report, lib.saveUnlocked()
+}
+
+/*   Fills in hashes for games scanned before    */
+/*   hashing existed                             */
+func (lib *Library) hashMissing() {
+	lib.mu.RLock()
+	missing := make(map[string]bool)
+	for _, game := range lib.Games {
+		if game.Hash == "" {
+			missing[game.Path] = true
+		}
+	}
+	lib.mu.RUnlock()
+	if len(missing) == 0 {
+		return
+	}
+
+	sums := make(map[string]fileSum, len(missing))
+	for path := range missing {
+		if sum, err := hashFile(path); err == nil {
+			sums[path] = sum
+		}
+	}
+
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+	for i, game := range lib.Games {
+		if sum, ok := sums[game.Path]; ok && game.Hash == "" {
+			lib.Games[i].Hash, lib.Games[i].CRC32, lib.Games[i].Size = sum.SHA1, sum.CRC32, sum.Size
+		}
+	}
+}
+
+func newImportedGame(game GameInfo, sum fileSum) GameInfo {
+	game.ID = generateID(game.Path)
+	game.Hash, game.CRC32, game.Size = sum.SHA1, sum.CRC32, sum.Size
+	if game.Platform == "" {
+		game.Platform = detectPlatform(strings.ToLower(filepath.Ext(game.Path)))
+	}
+	if game.Title == "" {
+		game.Title = cleanGameTitle(filepath.Base(game.Path))
+	}
+	if game.Category == "" {
+		game.Category = "Uncategorized"
+	}
+	return game
+}
+
+/**************************************************/
+/*                                                */
+/*                 FIELD MERGE                    */
+/*   Stats and flags combine; text fields set on  */
+/*   both sides are conflicts                     */
+/*                                                */
+/**************************************************/
+
+func mergeImported(current, incoming GameInfo, prefer string) (GameInfo, []Conflict) {
+	current.Favorite = current.Favorite || incoming.Favorite
+	if incoming.PlayCount > current.PlayCount {
+		current.PlayCount = incoming.PlayCount
+	}
+	if incoming.LastPlayed.After(current.LastPlayed) {
+		current.LastPlayed = incoming.LastPlayed
+	}
+	if incoming.PlaySeconds > current.PlaySeconds {
+		current.PlaySeconds = incoming.PlaySeconds
+	}
+	for _, tag := range incoming.Tags {
+		if !containsString(current.Tags, tag) {
+			current.Tags = append(current.Tags, tag)
+		}
+	}
+
+	conflicts := make([]Conflict, 0)
+	switch {
+	case incoming.Launch == nil || reflect.DeepEqual(current.Launch, incoming.Launch):
+	case current.Launch == nil:
+		conflicts = append(conflicts, launchConflict(current, nil, incoming.Launch, PreferImported))
+		current.Launch = incoming.Launch
+	default:
+		conflicts = append(conflicts, launchConflict(current, current.Launch, incoming.Launch, prefer))
+		if prefer == PreferImported {
+			current.Launch = incoming.Launch
+		}
+	}
+	mergeText := func(field string, local *string, imported string, empty string) {
+		switch {
+		case imported == "" || imported == empty || imported == *local:
+		case *local == "" || *local == empty:
+			*local = imported
+		default:
+			conflict := Conflict{
+				GameID: current.ID, Title: current.Title, Field: field,
+				Local: *local, Imported: imported, Kept: prefer,
+			}
+			if prefer == PreferImported {
+				*local = imported
+			}
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	/*   A title still derived from the file name  */
+	/*   counts as unset                           */
+	mergeText("title", &current.Title, incoming.Title, cleanGameTitle(filepath.Base(current.Path)))
+	mergeText("category", &current.Category, incoming.Category, "Uncategorized")
+	mergeText("description", &current.Description, incoming.Description, "")
+	mergeText("cover_path", &current.CoverPath, incoming.CoverPath, "")
+	return current, conflicts
+}
+
+/**************************************************/
+/*                                                */
+/*                  HELPERS                       */
+/*                                                */
+/**************************************************/
+
+/*   Profiles as JSON, so the hooks show in the  */
+/*   report                                      */
+func launchConflict(game GameInfo, local, imported *LaunchProfile, kept string) Conflict {
+	text := func(p *LaunchProfile) string {
+		if p == nil {
+			return ""
+		}
+		data, _ := json.Marshal(p)
+		return string(data)
+	}
+	return Conflict{
+		GameID: game.ID, Title: game.Title, Field: "launch",
+		Local: text(local), Imported: text(imported), Kept: kept,
+	}
+}
+
+/*   "./x" and "x" are relative to base; "~/"    */
+/*   is the home directory, as in gamelist.xml   */
+func resolvePath(path, base string) string {
+	switch {
+	case path == "":
+		return ""
+	case strings.HasPrefix(path, "~/"):
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	case !filepath.IsAbs(path) && base != "":
+		return filepath.Join(base, path)
+	}
+	return filepath.Clean(path)
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,324 @@
+/**************************************/
+/*                                    */
+/*   BIOS and Firmware File Registry  */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package firmware
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/**************************************************/
+/*                                                */
+/*               KNOWN FIRMWARE                   */
+/*   MD5s are the No-Intro / libretro reference   */
+/*   dumps. Required marks files no emulator can  */
+/*   do without; emulators.<platform>.firmware    */
+/*   in the config adds more per emulator.        */
+/*   Regional dumps share a Group, and a required */
+/*   group needs any one of them                  */
+/*                                                */
+/**************************************************/
+
+type File struct {
+	Platform    string   `json:"platform"`
+	Name        string   `json:"name"`
+	Aliases     []string `json:"aliases,omitempty"`
+	MD5         string   `json:"md5"`
+	Size        int64    `json:"size"`
+	Description string   `json:"description"`
+	Required    bool     `json:"required"`
+	Group       string   `json:"group,omitempty"`
+}
+
+var Known = []File{
+	{
+		Platform: "GBA", Name: "gba_bios.bin", MD5: "a860e8c0b6d573d191e4ec7db1b1e4f6", Size: 16384,
+		Description: "Game Boy Advance BIOS (gpSP needs it; mGBA falls back to HLE)",
+	},
+	{
+		Platform: "GB", Name: "gb_bios.bin", Aliases: []string{"dmg_boot.bin"},
+		MD5: "32fbbd84168d3482956eb3c5051637f5", Size: 256,
+		Description: "Game Boy boot ROM",
+	},
+	{
+		Platform: "GB", Name: "gbc_bios.bin", Aliases: []string{"cgb_boot.bin"},
+		MD5: "dbfce9db9deaa2567f6a84fde55f9680", Size: 2304,
+		Description: "Game Boy Color boot ROM",
+	},
+	{
+		Platform: "NES", Name: "disksys.rom", MD5: "ca30b50f880eb660a320674ed365ef7a", Size: 8192,
+		Description: "Famicom Disk System BIOS, for .fds disk images",
+	},
+	{
+		Platform: "PS1", Name: "scph5500.bin", MD5: "8dd7d5296a650fac7319bce665a6a53c", Size: 524288,
+		Description: "PlayStation BIOS (Japan)", Required: true, Group: "ps1-bios",
+	},
+	{
+		Platform: "PS1", Name: "scph5501.bin", MD5: "490f666e1afb15b7362b406ed1cea246", Size: 524288,
+		Description: "PlayStation BIOS (USA)", Required: true, Group: "ps1-bios",
+	},
+	{
+		Platform: "PS1", Name: "scph5502.bin", MD5: "32736f17079d0b2b7024407c39bd3050", Size: 524288,
+		Description: "PlayStation BIOS (Europe)", Required: true, Group: "ps1-bios",
+	},
+	{
+		Platform: "SEGACD", Name: "bios_CD_J.bin", MD5: "278a9397d192149e84e820ac621a8edd", Size: 131072,
+		Description: "Mega-CD BIOS (Japan)", Required: true, Group: "segacd-bios",
+	},
+	{
+		Platform: "SEGACD", Name: "bios_CD_U.bin", MD5: "2efd74e3232ff260e371b99f84024f7f", Size: 131072,
+		Description: "Sega CD BIOS (USA)", Required: true, Group: "segacd-bios",
+	},
+	{
+		Platform: "SEGACD", Name: "bios_CD_E.bin", MD5: "e66fa1dc5820d254611fdcdba0662372", Size: 131072,
+		Description: "Mega-CD BIOS (Europe)", Required: true, Group: "segacd-bios",
+	},
+}
+
+/*   Known files for platform, in registry order */
+func ForPlatform(platform string) []File {
+	files := make([]File, 0)
+	for _, f := range Known {
+		if strings.EqualFold(f.Platform, platform) {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+func Lookup(platform, name string) (File, bool) {
+	for _, f := range ForPlatform(platform) {
+		if f.matches(name) {
+			return f, true
+		}
+	}
+	return File{}, false
+}
+
+func (f File) matches(name string) bool {
+	if strings.EqualFold(f.Name, name) {
+		return true
+	}
+	for _, alias := range f.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+/**************************************************/
+/*                                                */
+/*                   STATUS                       */
+/*                                                */
+/**************************************************/
+
+const (
+	StatusOK       = "ok"
+	StatusMissing  = "missing"
+	StatusMismatch = "mismatch" /*   found, but not the known dump */
+)
+
+type FileStatus struct {
+	File
+	Status   string `json:"status"`
+	Path     string `json:"path,omitempty"`
+	FoundMD5 string `json:"found_md5,omitempty"`
+}
+
+type Report struct {
+	Dirs  []string     `json:"dirs"`
+	Files []FileStatus `json:"files"`
+}
+
+/*   Searches dirs (and their subfolders, a few  */
+/*   levels down) for every known file. extra    */
+/*   names more required files per platform; a   */
+/*   file named there leaves its group, as no    */
+/*   other dump will do                          */
+func Check(dirs []string, extra map[string][]string) Report {
+	found := index(dirs)
+	report := Report{Dirs: append([]string{}, dirs...), Files: make([]FileStatus, 0, len(Known))}
+
+	named := make(map[string][]string, len(extra))
+	for platform, names := range extra {
+		key := strings.ToUpper(platform)
+		named[key] = append(named[key], names...)
+	}
+
+	for _, f := range Known {
+		for _, name := range named[strings.ToUpper(f.Platform)] {
+			if f.matches(name) {
+				f.Required, f.Group = true, ""
+			}
+		}
+		status := FileStatus{File: f, Status: StatusMissing}
+
+		candidates := append([]string{}, found[strings.ToLower(f.Name)]...)
+		for _, alias := range f.Aliases {
+			candidates = append(candidates, found[strings.ToLower(alias)]...)
+		}
+		for _, path := range candidates {
+			sum, err := md5File(path)
+			if err != nil {
+				continue
+			}
+			if sum == f.MD5 {
+				status.Status, status.Path, status.FoundMD5 = StatusOK, path, sum
+				break
+			}
+			if status.Status == StatusMissing {
+				status.Status, status.Path, status.FoundMD5 = StatusMismatch, path, sum
+			}
+		}
+		report.Files = append(report.Files, status)
+	}
+	return report
+}
+
+/*   Only this platform's entries               */
+func (r Report) Platform(platform string) Report {
+	out := Report{Dirs: r.Dirs, Files: make([]FileStatus, 0)}
+	for _, f := range r.Files {
+		if strings.EqualFold(f.Platform, platform) {
+			out.Files = append(out.Files, f)
+		}
+	}
+	return out
+}
+
+/*   nil when platform can launch; otherwise a   */
+/*   *MissingError naming every bad file         */
+func (r Report) Require(platform string) error {
+	bad := r.Platform(platform).Missing()
+	if len(bad) == 0 {
+		return nil
+	}
+	return &MissingError{Platform: platform, Files: bad, Dirs: r.Dirs}
+}
+
+/*   Required files that are not OK; a group is  */
+/*   listed whole, and only if no member is OK    */
+func (r Report) Missing() []FileStatus {
+	present := make(map[string]bool)
+	for _, f := range r.Files {
+		if f.Group != "" && f.Status == StatusOK {
+			present[f.Group] = true
+		}
+	}
+	bad := make([]FileStatus, 0)
+	for _, f := range r.Files {
+		if f.Required && f.Status != StatusOK && !present[f.Group] {
+			bad = append(bad, f)
+		}
+	}
+	return bad
+}
+
+/*   Required names that are in no registry     */
+/*   entry for the platform, so that a typo in  */
+/*   the config is not silently ignored         */
+func Unknown(platform string, names []string) []string {
+	unknown := make([]string, 0)
+	for _, name := range names {
+		if _, ok := Lookup(platform, name); !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+type MissingError struct {
+	Platform string
+	Files    []FileStatus
+	Dirs     []string
+}
+
+func (e *MissingError) Error() string {
+	problems := make([]string, 0, len(e.Files))
+	slots := make(map[string]int) /*   group to its place in problems */
+	members := make(map[string][]string)
+	for _, f := range e.Files {
+		switch {
+		case f.Group != "":
+			if _, ok := slots[f.Group]; !ok {
+				slots[f.Group] = len(problems)
+				problems = append(problems, "")
+			}
+			members[f.Group] = append(members[f.Group], f.Name)
+		case f.Status == StatusMismatch:
+			problems = append(problems, fmt.Sprintf("%s at %s has MD5 %s, expected %s", f.Name, f.Path, f.FoundMD5, f.MD5))
+		default:
+			problems = append(problems, fmt.Sprintf("%s (MD5 %s) is missing", f.Name, f.MD5))
+		}
+	}
+	for group, i := range slots {
+		problems[i] = fmt.Sprintf("none of %s is present with a known MD5", strings.Join(members[group], ", "))
+	}
+	where := "no firmware directories are configured"
+	if len(e.Dirs) > 0 {
+		where = "searched " + strings.Join(e.Dirs, ", ")
+	}
+	return fmt.Sprintf("%s firmware: %s; %s", e.Platform, strings.Join(problems, "; "), where)
+}
+
+/**************************************************/
+/*                                                */
+/*                  HELPERS                       */
+/*                                                */
+/**************************************************/
+
+/*   Firmware folders are small, but skip the   */
+/*   deep end of a misconfigured root           */
+const maxDepth = 3
+
+/*   Lower-case file name to paths, earlier     */
+/*   dirs first                                 */
+func index(dirs []string) map[string][]string {
+	found := make(map[string][]string)
+	for _, dir := range dirs {
+		root := filepath.Clean(dir)
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if rel, _ := filepath.Rel(root, path); rel != "." && strings.Count(rel, string(filepath.Separator)) >= maxDepth-1 {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			name := strings.ToLower(d.Name())
+			found[name] = append(found[name], path)
+			return nil
+		})
+	}
+	return found
+}
+
+func md5File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	sum := md5.New()
+	if _, err := io.Copy(sum, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
@@ -0,0 +1,345 @@
+/**************************************/
+/*                                    */
+/*   Library Interchange Formats      */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package library
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*                   FORMATS                      */
+/*                                                */
+/**************************************************/
+
+type Format string
+
+const (
+	FormatJSON     Format = "json"     /*   portable document, see export.go */
+	FormatCSV      Format = "csv"      /*   one row per game                 */
+	FormatGamelist Format = "gamelist" /*   EmulationStation gamelist.xml    */
+	FormatLPL      Format = "lpl"      /*   RetroArch playlist               */
+)
+
+var Formats = []Format{FormatJSON, FormatCSV, FormatGamelist, FormatLPL}
+
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q (want json, csv, gamelist or lpl)", name)
+}
+
+/*   Guesses from a file name; JSON otherwise    */
+func FormatForPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".xml":
+		return FormatGamelist
+	case ".lpl":
+		return FormatLPL
+	}
+	return FormatJSON
+}
+
+/**************************************************/
+/*                                                */
+/*                     CSV                        */
+/*   Header row first; tags are ";"-separated     */
+/*   and times are RFC 3339                       */
+/*                                                */
+/**************************************************/
+
+var csvColumns = []string{
+	"hash", "crc32", "size", "id", "title", "platform", "category", "tags",
+	"favorite", "play_count", "last_played", "path", "description", "cover_path",
+}
+
+func writeCSV(w io.Writer, games []GameInfo) error {
+	out := csv.NewWriter(w)
+	out.Write(csvColumns)
+	for _, g := range games {
+		lastPlayed := ""
+		if !g.LastPlayed.IsZero() {
+			lastPlayed = g.LastPlayed.Format(time.RFC3339)
+		}
+		out.Write([]string{
+			g.Hash, g.CRC32, strconv.FormatInt(g.Size, 10), g.ID, g.Title, g.Platform, g.Category,
+			strings.Join(g.Tags, ";"), strconv.FormatBool(g.Favorite), strconv.Itoa(g.PlayCount),
+			lastPlayed, g.Path, g.Description, g.CoverPath,
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+/*   Columns are found by header name, so any   */
+/*   subset in any order is accepted            */
+func readCSV(r io.Reader) ([]GameInfo, error) {
+	in := csv.NewReader(r)
+	in.FieldsPerRecord = -1
+	header, err := in.Read()
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := index["path"]; !ok {
+		if _, ok := index["hash"]; !ok {
+			return nil, fmt.Errorf("CSV needs a path or hash column")
+		}
+	}
+
+	games := make([]GameInfo, 0)
+	for line := 2; ; line++ {
+		row, err := in.Read()
+		if err == io.EOF {
+			return games, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := index[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		g := GameInfo{
+			Hash: strings.ToLower(field("hash")), CRC32: strings.ToUpper(field("crc32")),
+			ID: field("id"), Title: field("title"), Platform: field("platform"),
+			Category: field("category"), Path: field("path"),
+			Description: field("description"), CoverPath: field("cover_path"),
+		}
+		if tags := field("tags"); tags != "" {
+			for _, tag := range strings.Split(tags, ";") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					g.Tags = append(g.Tags, tag)
+				}
+			}
+		}
+		if v := field("size"); v != "" {
+			if g.Size, err = strconv.ParseInt(v, 10, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid size %q", line, v)
+			}
+		}
+		if v := field("favorite"); v != "" {
+			if g.Favorite, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("line %d: invalid favorite %q", line, v)
+			}
+		}
+		if v := field("play_count"); v != "" {
+			if g.PlayCount, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("line %d: invalid play_count %q", line, v)
+			}
+		}
+		if v := field("last_played"); v != "" {
+			if g.LastPlayed, err = time.Parse(time.RFC3339, v); err != nil {
+				return nil, fmt.Errorf("line %d: invalid last_played %q", line, v)
+			}
+		}
+		games = append(games, g)
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*         EMULATIONSTATION GAMELIST.XML          */
+/*   genre carries our category; tags, hashes     */
+/*   and IDs have no place in the format          */
+/*                                                */
+/**************************************************/
+
+const gamelistTime = "20060102T150405"
+
+type gamelistXML struct {
+	XMLName xml.Name          `xml:"gameList"`
+	Games   []gamelistGameXML `xml:"game"`
+}
+
+type gamelistGameXML struct {
+	Path       string `xml:"path"`
+	Name       string `xml:"name,omitempty"`
+	Desc       string `xml:"desc,omitempty"`
+	Image      string `xml:"image,omitempty"`
+	Genre      string `xml:"genre,omitempty"`
+	Favorite   string `xml:"favorite,omitempty"`
+	PlayCount  int    `xml:"playcount,omitempty"`
+	LastPlayed string `xml:"lastplayed,omitempty"`
+}
+
+func writeGamelist(w io.Writer, games []GameInfo, baseDir string) error {
+	doc := gamelistXML{Games: make([]gamelistGameXML, 0, len(games))}
+	for _, g := range games {
+		entry := gamelistGameXML{
+			Path: relativeTo(g.Path, baseDir), Name: g.Title, Desc: g.Description,
+			Image: relativeTo(g.CoverPath, baseDir), PlayCount: g.PlayCount,
+		}
+		if g.Category != "Uncategorized" {
+			entry.Genre = g.Category
+		}
+		if g.Favorite {
+			entry.Favorite = "true"
+		}
+		if !g.LastPlayed.IsZero() {
+			entry.LastPlayed = g.LastPlayed.UTC().Format(gamelistTime)
+		}
+		doc.Games = append(doc.Games, entry)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func readGamelist(r io.Reader) ([]GameInfo, error) {
+	var doc gamelistXML
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	games := make([]GameInfo, 0, len(doc.Games))
+	for _, entry := range doc.Games {
+		g := GameInfo{
+			Path: entry.Path, Title: entry.Name, Description: entry.Desc,
+			CoverPath: entry.Image, Category: entry.Genre, PlayCount: entry.PlayCount,
+			Favorite: strings.EqualFold(entry.Favorite, "true"),
+		}
+		if entry.LastPlayed != "" {
+			if t, err := time.Parse(gamelistTime, entry.LastPlayed); err == nil {
+				g.LastPlayed = t
+			}
+		}
+		games = append(games, g)
+	}
+	return games, nil
+}
+
+/**************************************************/
+/*                                                */
+/*            RETROARCH PLAYLIST (.lpl)           */
+/*   JSON playlist format 1.5; cores are left     */
+/*   for RetroArch to detect                      */
+/*                                                */
+/**************************************************/
+
+/*    Our platforms as libretro database names    */
+var retroarchDatabases = map[string]string{
+	"NES":   "Nintendo - Nintendo Entertainment System",
+	"SNES":  "Nintendo - Super Nintendo Entertainment System",
+	"N64":   "Nintendo - Nintendo 64",
+	"GBA":   "Nintendo - Game Boy Advance",
+	"GB":    "Nintendo - Game Boy",
+	"ATARI": "Atari - 2600",
+}
+
+type lplPlaylist struct {
+	Version         string    `json:"version"`
+	DefaultCorePath string    `json:"default_core_path"`
+	DefaultCoreName string    `json:"default_core_name"`
+	LabelMode       int       `json:"label_display_mode"`
+	RightThumbMode  int       `json:"right_thumbnail_mode"`
+	LeftThumbMode   int       `json:"left_thumbnail_mode"`
+	SortMode        int       `json:"sort_mode"`
+	Items           []lplItem `json:"items"`
+}
+
+type lplItem struct {
+	Path     string `json:"path"`
+	Label    string `json:"label"`
+	CorePath string `json:"core_path"`
+	CoreName string `json:"core_name"`
+	CRC32    string `json:"crc32"`
+	DBName   string `json:"db_name"`
+}
+
+func writeLPL(w io.Writer, games []GameInfo) error {
+	playlist := lplPlaylist{Version: "1.5", Items: make([]lplItem, 0, len(games))}
+	for _, g := range games {
+		item := lplItem{Path: g.Path, Label: g.Title, CorePath: "DETECT", CoreName: "DETECT", CRC32: "DETECT"}
+		if g.CRC32 != "" {
+			item.CRC32 = g.CRC32 + "|crc"
+		}
+		if db, ok := retroarchDatabases[g.Platform]; ok {
+			item.DBName = db + ".lpl"
+		}
+		playlist.Items = append(playlist.Items, item)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(playlist)
+}
+
+func readLPL(r io.Reader) ([]GameInfo, error) {
+	var playlist lplPlaylist
+	if err := json.NewDecoder(r).Decode(&playlist); err != nil {
+		return nil, err
+	}
+
+	games := make([]GameInfo, 0, len(playlist.Items))
+	for _, item := range playlist.Items {
+		/*   Entries inside archives use "zip#rom"   */
+		path, _, _ := strings.Cut(item.Path, "#")
+		g := GameInfo{Path: path, Title: item.Label}
+		if crc, _, ok := strings.Cut(item.CRC32, "|"); ok && crc != "00000000" {
+			g.CRC32 = strings.ToUpper(crc)
+		}
+		db := strings.TrimSuffix(item.DBName, ".lpl")
+		for platform, name := range retroarchDatabases {
+			if name == db {
+				g.Platform = platform
+			}
+		}
+		games = append(games, g)
+	}
+	return games, nil
+}
+
+/**************************************************/
+/*                                                */
+/*                  HELPERS                       */
+/*                                                */
+/**************************************************/
+
+/*   "./rel/path" when under base, as            */
+/*   EmulationStation writes them                */
+func relativeTo(path, base string) string {
+	if path == "" || base == "" {
+		return path
+	}
+	rel, err := filepath.Rel(base, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return "./" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,378 @@
+/**************************************/
+/*                                    */
+/*     Backend Request Handlers       */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"retro-gaming-ui/backend/launcher"
+	"retro-gaming-ui/backend/library"
+	"retro-gaming-ui/backend/logging"
+	"retro-gaming-ui/backend/server"
+)
+
+/**************************************************/
+/*                                                */
+/*             HANDLER SET / ROUTES               */
+/*                                                */
+/**************************************************/
+
+type handlers struct {
+	lib      *library.Library
+	ipc      *server.IPCServer
+	metrics  *hubMetrics
+	logs     *logging.Ring
+	config   *reloader
+	launcher *launcher.Launcher
+
+	scanMu     sync.Mutex
+	scanCancel context.CancelFunc
+	lastScan   *library.ScanReport
+}
+
+func newRouter(h *handlers) *server.Router {
+	router := server.NewRouter()
+	router.Handle(server.MsgTypeListGames, h.listGames)
+	router.Handle(server.MsgTypeGetGame, h.getGame)
+	router.Handle(server.MsgTypeGetFavorites, h.getFavorites)
+	router.Handle(server.MsgTypeGetMedia, h.getMedia)
+	router.Handle(server.MsgTypeToggleFavorite, h.toggleFavorite)
+	router.Handle(server.MsgTypeLaunchGame, h.launchGame)
+	router.Handle(server.MsgTypeSetProfile, h.setLaunchProfile)
+	router.Handle(server.MsgTypeClearProfile, h.clearLaunchProfile)
+	router.Handle(server.MsgTypeGetFirmware, h.getFirmwareStatus)
+	router.Handle(server.MsgTypeScan, h.scan)
+	router.Handle(server.MsgTypeCancelScan, h.cancelScan)
+	router.Handle(server.MsgTypeAddScanPath, h.addScanPath)
+	router.Handle(server.MsgTypeRemoveScanPath, h.removeScanPath)
+	router.Handle(server.MsgTypeListScanPaths, h.listScanPaths)
+	router.Handle(server.MsgTypeGetStats, h.getStats)
+	router.Handle(server.MsgTypeVerifyLibrary, h.verifyLibrary)
+	router.Handle(server.MsgTypeAnalyzeLibrary, h.analyzeLibrary)
+	router.Handle(server.MsgTypeExportLibrary, h.exportLibrary)
+	router.Handle(server.MsgTypeImportLibrary, h.importLibrary)
+	router.Handle(server.MsgTypeStatus, h.status)
+	router.Handle(server.MsgTypeGetLogs, h.getLogs)
+	router.Handle(server.MsgTypeReloadConfig, h.reloadConfig)
+	return router
+}
+
+/**************************************************/
+/*                                                */
+/*                LIBRARY QUERIES                 */
+/*                                                */
+/**************************************************/
+
+func (h *handlers) listGames(ctx context.Context, req server.Request) server.Response {
+	var payload server.GameListPayload
+	server.DecodePayload(req, &payload)
+
+	games := h.lib.GetGames(payload.Platform, payload.Category)
+	if payload.Query != "" {
+		matched := make([]library.GameInfo, 0)
+		for _, game := range games {
+			if game.Matches(payload.Query) {
+				matched = append(matched, game)
+			}
+		}
+		games = matched
+	}
+	if payload.Limit > 0 && payload.Limit < len(games) {
+		games = games[:payload.Limit]
+	}
+	return server.OK(req, games)
+}
+
+func (h *handlers) getGame(ctx context.Context, req server.Request) server.Response {
+	var id string
+	server.DecodePayload(req, &id)
+	game := h.lib.GetGameByID(id)
+	if game == nil {
+		return server.Fail(req, server.ErrCodeNotFound, "Game not found")
+	}
+	return server.OK(req, game)
+}
+
+func (h *handlers) getFavorites(ctx context.Context, req server.Request) server.Response {
+	return server.OK(req, h.lib.GetFavorites())
+}
+
+func (h *handlers) getMedia(ctx context.Context, req server.Request) server.Response {
+	var payload server.MediaPayload
+	if err := server.DecodePayload(req, &payload); err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+	items, err := h.lib.Media(payload.GameID, library.MediaKind(payload.Kind))
+	if errors.Is(err, library.ErrGameNotFound) {
+		return server.Fail(req, server.ErrCodeNotFound, "Game not found")
+	} else if err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+	return server.OK(req, items)
+}
+
+func (h *handlers) toggleFavorite(ctx context.Context, req server.Request) server.Response {
+	var id string
+	server.DecodePayload(req, &id)
+	if err := h.lib.ToggleFavorite(id); errors.Is(err, library.ErrGameNotFound) {
+		return server.Fail(req, server.ErrCodeNotFound, "Game not found")
+	} else if err != nil {
+		return server.Fail(req, server.ErrCodeInternal, err.Error())
+	}
+	return server.OK(req, h.lib.GetGameByID(id))
+}
+
+/**************************************************/
+/*                                                */
+/*              SCANNING / STATUS                 */
+/*                                                */
+/**************************************************/
+
+func (h *handlers) scan(ctx context.Context, req server.Request) server.Response {
+	h.scanMu.Lock()
+	if h.scanCancel != nil {
+		h.scanMu.Unlock()
+		return server.Fail(req, server.ErrCodeBadRequest, library.ErrScanInProgress.Error())
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	h.scanCancel = cancel
+	h.scanMu.Unlock()
+
+	start := time.Now()
+	err := h.lib.ScanContext(ctx, func(p library.ScanProgress) {
+		h.ipc.Broadcast(server.TopicScanProgress, p)
+	})
+	cancel()
+	if errors.Is(err, library.ErrScanInProgress) {
+		h.finishScan(nil)
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+
+	report := &library.ScanReport{Started: start, Finished: time.Now(), Games: len(h.lib.GetGames("", ""))}
+	switch {
+	case errors.Is(err, context.Canceled):
+		report.Canceled = true
+		h.finishScan(report)
+		h.metrics.scanDuration.Observe(time.Since(start).Seconds(), "canceled")
+		return server.Fail(req, server.ErrCodeCanceled, "Scan canceled")
+	case err != nil:
+		report.Error = err.Error()
+		h.finishScan(report)
+		h.metrics.scanDuration.Observe(time.Since(start).Seconds(), "error")
+		return server.Fail(req, server.ErrCodeInternal, err.Error())
+	}
+	h.finishScan(report)
+	h.metrics.scanDuration.Observe(time.Since(start).Seconds(), "success")
+	return server.OK(req, fmt.Sprintf("Found %d games", report.Games))
+}
+
+/*   A nil report keeps the previous result    */
+func (h *handlers) finishScan(report *library.ScanReport) {
+	h.scanMu.Lock()
+	defer h.scanMu.Unlock()
+	h.scanCancel = nil
+	if report != nil {
+		h.lastScan = report
+	}
+}
+
+func (h *handlers) cancelScan(ctx context.Context, req server.Request) server.Response {
+	h.scanMu.Lock()
+	cancel := h.scanCancel
+	h.scanMu.Unlock()
+	if cancel == nil {
+		return server.Fail(req, server.ErrCodeBadRequest, "No scan is running")
+	}
+	cancel()
+	return server.OK(req, "Scan canceled")
+}
+
+func (h *handlers) addScanPath(ctx context.Context, req server.Request) server.Response {
+	var payload server.ScanPathPayload
+	if err := server.DecodePayload(req, &payload); err != nil || payload.Path == "" {
+		return server.Fail(req, server.ErrCodeBadRequest, "Missing path")
+	}
+	sp := library.ScanPath{
+		Path: payload.Path, MaxDepth: payload.MaxDepth, FollowSymlinks: payload.FollowSymlinks,
+		Include: payload.Include, Exclude: payload.Exclude, NoDefaultExcludes: payload.NoDefaultExcludes,
+		Platform: payload.Platform, Category: payload.Category,
+	}
+	var err error
+	if sp.HasOptions() {
+		err = h.lib.SetScanPath(sp)
+	} else {
+		err = h.lib.AddScanPath(sp.Path)
+	}
+	if err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, fmt.Sprintf("Cannot add %s: %v", payload.Path, err))
+	}
+	if err := h.lib.Save(); err != nil {
+		return server.Fail(req, server.ErrCodeInternal, err.Error())
+	}
+	return server.OK(req, h.lib.GetScanPaths())
+}
+
+func (h *handlers) removeScanPath(ctx context.Context, req server.Request) server.Response {
+	var payload server.ScanPathPayload
+	if err := server.DecodePayload(req, &payload); err != nil || payload.Path == "" {
+		return server.Fail(req, server.ErrCodeBadRequest, "Missing path")
+	}
+	if err := h.lib.RemoveScanPath(payload.Path); err != nil {
+		return server.Fail(req, server.ErrCodeNotFound, err.Error())
+	}
+	if err := h.lib.Save(); err != nil {
+		return server.Fail(req, server.ErrCodeInternal, err.Error())
+	}
+	return server.OK(req, h.lib.GetScanPaths())
+}
+
+func (h *handlers) listScanPaths(ctx context.Context, req server.Request) server.Response {
+	return server.OK(req, h.lib.ScanPathStatuses())
+}
+
+/*   Status is public so clients can probe the  */
+/*   backend before auth; the session and scan  */
+/*   details need read scope                    */
+func (h *handlers) status(ctx context.Context, req server.Request) server.Response {
+	data := map[string]interface{}{
+		"status":           "ready",
+		"version":          server.ProtocolVersion,
+		"protocol_version": server.ProtocolVersion,
+	}
+	if client, ok := server.ClientFromContext(ctx); !ok || !client.HasScope(server.ScopeRead) {
+		return server.Response{Type: server.MsgTypeStatus, ID: req.ID, Success: true, Data: data}
+	}
+	data["clients"] = h.ipc.ClientCount()
+	data["scanning"] = h.lib.IsScanning()
+	h.scanMu.Lock()
+	if h.lastScan != nil {
+		data["last_scan"] = *h.lastScan
+	}
+	h.scanMu.Unlock()
+	if session, ok := h.launcher.Current(); ok {
+		data["playing"] = session
+	}
+	return server.Response{Type: server.MsgTypeStatus, ID: req.ID, Success: true, Data: data}
+}
+
+/**************************************************/
+/*                                                */
+/*           STATS / VERIFY / TRANSFER            */
+/*                                                */
+/**************************************************/
+
+func (h *handlers) getStats(ctx context.Context, req server.Request) server.Response {
+	return server.OK(req, h.lib.Stats())
+}
+
+func (h *handlers) verifyLibrary(ctx context.Context, req server.Request) server.Response {
+	return server.OK(req, h.lib.Verify())
+}
+
+func (h *handlers) analyzeLibrary(ctx context.Context, req server.Request) server.Response {
+	var payload server.AnalyzePayload
+	if err := server.DecodePayload(req, &payload); err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+	report, err := h.lib.Analyze(ctx, library.AnalysisOptions{PreferRegions: payload.PreferRegions})
+	if err != nil {
+		return server.Fail(req, server.ErrCodeInternal, err.Error())
+	}
+	return server.OK(req, report)
+}
+
+func (h *handlers) exportLibrary(ctx context.Context, req server.Request) server.Response {
+	var payload server.ExportPayload
+	if err := server.DecodePayload(req, &payload); err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+	if payload.Format == "" {
+		return server.OK(req, h.lib.Export())
+	}
+
+	format, err := library.ParseFormat(payload.Format)
+	if err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+	var buf bytes.Buffer
+	opts := library.ExportOptions{Platform: payload.Platform, Favorites: payload.Favorites, BaseDir: payload.BaseDir}
+	if err := h.lib.ExportTo(&buf, format, opts); err != nil {
+		return server.Fail(req, server.ErrCodeInternal, err.Error())
+	}
+	return server.OK(req, server.ExportResult{Format: string(format), Content: buf.String()})
+}
+
+func (h *handlers) importLibrary(ctx context.Context, req server.Request) server.Response {
+	var payload struct {
+		server.ImportPayload
+		Games json.RawMessage `json:"games"`
+	}
+	if err := server.DecodePayload(req, &payload); err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+
+	/*   A bare export document, as sent by 1.2    */
+	/*   clients                                   */
+	content, format := payload.Content, payload.Format
+	if payload.Games != nil {
+		content, format = string(req.Payload), string(library.FormatJSON)
+	}
+
+	parsed, err := library.ParseFormat(format)
+	if err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+	opts := library.ImportOptions{BaseDir: payload.BaseDir, Prefer: payload.Prefer, Launch: payload.Launch}
+	report, err := h.lib.ImportFrom(strings.NewReader(content), parsed, opts)
+	if err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+	return server.OK(req, report)
+}
+
+/**************************************************/
+/*                                                */
+/*                 DIAGNOSTICS                    */
+/*                                                */
+/**************************************************/
+
+func (h *handlers) getLogs(ctx context.Context, req server.Request) server.Response {
+	payload := server.GetLogsPayload{Limit: 100, Level: "debug"}
+	if err := server.DecodePayload(req, &payload); err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+	level, err := logging.ParseLevel(payload.Level)
+	if err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+	return server.OK(req, h.logs.Recent(payload.Limit, level))
+}
+
+/**************************************************/
+/*                                                */
+/*                CONFIGURATION                   */
+/*                                                */
+/**************************************************/
+
+func (h *handlers) reloadConfig(ctx context.Context, req server.Request) server.Response {
+	plan, err := h.config.Reload()
+	if err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+	return server.OK(req, plan)
+}
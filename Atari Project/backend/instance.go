@@ -0,0 +1,237 @@
+/**************************************/
+/*                                    */
+/*   Single Instance and Daemon Mode  */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*                 INSTANCE LOCK                  */
+/*   An flock on <config-dir>/backend.pid; the    */
+/*   file also names whoever holds it             */
+/*                                                */
+/**************************************************/
+
+const LockFile = "backend.pid"
+
+/*   Owner is empty for the backend itself; a    */
+/*   tool editing the library offline names      */
+/*   itself there                                */
+type Info struct {
+	PID     int       `json:"pid"`
+	Owner   string    `json:"owner,omitempty"`
+	Listen  string    `json:"listen,omitempty"`
+	Started time.Time `json:"started"`
+}
+
+type Lock struct {
+	file *os.File
+	path string
+}
+
+/*   Returned by Acquire when another backend    */
+/*   holds the lock for the same config dir      */
+type AlreadyRunningError struct {
+	Path string
+	Info Info
+}
+
+func (e *AlreadyRunningError) Error() string {
+	if e.Info.PID == 0 {
+		return fmt.Sprintf("another backend holds the lock %s", e.Path)
+	}
+	owner := "backend"
+	if e.Info.Owner != "" {
+		owner = e.Info.Owner
+	}
+	msg := fmt.Sprintf("%s already running (pid %d", owner, e.Info.PID)
+	if e.Info.Listen != "" {
+		msg += ", listening on " + e.Info.Listen
+	}
+	if !e.Info.Started.IsZero() {
+		msg += ", started " + e.Info.Started.Local().Format("2006-01-02 15:04:05")
+	}
+	return msg + ", lock " + e.Path + ")"
+}
+
+var errLocked = errors.New("lock is held")
+
+func Acquire(dir string, info Info) (*Lock, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, LockFile)
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lockFile(file); err != nil {
+		file.Close()
+		if errors.Is(err, errLocked) {
+			return nil, &AlreadyRunningError{Path: path, Info: ReadInfo(dir)}
+		}
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	lock := &Lock{file: file, path: path}
+	if info.PID == 0 {
+		info.PID = os.Getpid()
+	}
+	if info.Started.IsZero() {
+		info.Started = time.Now()
+	}
+	if err := lock.write(info); err != nil {
+		lock.Release()
+		return nil, err
+	}
+	return lock, nil
+}
+
+/*   Whatever the current holder wrote; zero     */
+/*   when the file is missing or unreadable      */
+func ReadInfo(dir string) Info {
+	var info Info
+	if data, err := os.ReadFile(filepath.Join(dir, LockFile)); err == nil {
+		json.Unmarshal(data, &info)
+	}
+	return info
+}
+
+func (l *Lock) Path() string {
+	return l.path
+}
+
+func (l *Lock) write(info Info) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	if err := l.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := l.file.WriteAt(append(data, '\n'), 0); err != nil {
+		return err
+	}
+	return l.file.Sync()
+}
+
+/*   The file is emptied but kept: unlinking a   */
+/*   locked file lets two processes each lock    */
+/*   a different inode                           */
+func (l *Lock) Release() error {
+	l.file.Truncate(0)
+	unlockFile(l.file)
+	return l.file.Close()
+}
+
+/**************************************************/
+/*                                                */
+/*                  DAEMON MODE                   */
+/*   Re-executes the backend detached, waits for  */
+/*   it to report READY=1 on a private notify     */
+/*   socket, then lets the caller exit            */
+/*                                                */
+/**************************************************/
+
+/*   Set in the detached child; holds the path   */
+/*   of the parent's notify socket               */
+const DaemonEnv = "RETROHUB_DAEMON_NOTIFY"
+
+var ErrDaemonUnsupported = errors.New("daemon mode is not supported on this platform")
+
+func IsDaemonChild() bool {
+	return os.Getenv(DaemonEnv) != ""
+}
+
+/*   Returns the child's PID once it is ready;   */
+/*   one still starting when ctx ends is killed. */
+/*   Its output goes to /dev/null, so configure  */
+/*   a log file when running detached            */
+func Daemonize(ctx context.Context, args []string) (int, error) {
+	if !daemonSupported {
+		return 0, ErrDaemonUnsupported
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+
+	dir, err := os.MkdirTemp("", "retrohub-daemon-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(dir)
+
+	listener, err := ListenNotify(filepath.Join(dir, "notify.sock"))
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer devNull.Close()
+
+	cmd := exec.Command(exe, args...)
+	cmd.Env = append(os.Environ(), DaemonEnv+"="+listener.Path())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = devNull, devNull, devNull
+	cmd.SysProcAttr = detachedAttr()
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	messages := listener.Messages()
+
+	status := ""
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				messages = nil
+				continue
+			}
+			if s, ok := msg["STATUS"]; ok {
+				status = s
+			}
+			if msg["READY"] == "1" {
+				return cmd.Process.Pid, nil
+			}
+		case err := <-exited:
+			if status != "" {
+				return 0, fmt.Errorf("backend exited during startup: %s", status)
+			}
+			return 0, fmt.Errorf("backend exited during startup: %v", err)
+		case <-ctx.Done():
+			/*   Not left running detached with nobody   */
+			/*   told its pid                            */
+			cmd.Process.Kill()
+			<-exited
+			if status != "" {
+				return 0, fmt.Errorf("backend did not report ready (last status: %s): %w", status, ctx.Err())
+			}
+			return 0, fmt.Errorf("backend did not report ready: %w", ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !unix
+
+/**************************************/
+/*                                    */
+/*   Instance Lock - Other Platforms  */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package instance
+
+import (
+	"os"
+	"syscall"
+)
+
+const daemonSupported = false
+
+/*   No portable advisory lock here: the PID     */
+/*   file is still written, but two backends     */
+/*   on the same config dir are not prevented    */
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
+
+func detachedAttr() *syscall.SysProcAttr {
+	return nil
+}
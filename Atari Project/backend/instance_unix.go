@@ -0,0 +1,39 @@
+//go:build unix
+
+/**************************************/
+/*                                    */
+/*   Instance Lock - Unix (flock)     */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package instance
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+const daemonSupported = true
+
+func lockFile(file *os.File) error {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return errLocked
+	}
+	return err
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
+
+/*   New session: no controlling terminal, and   */
+/*   no SIGHUP when the launching shell exits    */
+func detachedAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,62 @@
+/**************************************/
+/*                                    */
+/*    IPC Server Instrumentation      */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"retro-gaming-ui/backend/metrics"
+)
+
+/**************************************************/
+/*                                                */
+/*             REQUEST METRICS MIDDLEWARE         */
+/*                                                */
+/**************************************************/
+
+func Instrument(reg *metrics.Registry) Middleware {
+	requests := reg.NewCounterVec("retrohub_ipc_requests_total",
+		"IPC requests by message type and result.", "type", "result")
+	latency := reg.NewHistogramVec("retrohub_ipc_request_duration_seconds",
+		"IPC request handling latency by message type.", nil, "type")
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req Request) Response {
+			start := time.Now()
+			resp := next(ctx, req)
+
+			result := "success"
+			if !resp.Success {
+				result = resp.Code
+				if result == "" {
+					result = "error"
+				}
+			}
+			requests.Inc(req.Type, result)
+			latency.Observe(time.Since(start).Seconds(), req.Type)
+			return resp
+		}
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*               CONNECTION GAUGES                */
+/*                                                */
+/**************************************************/
+
+func (s *IPCServer) RegisterMetrics(reg *metrics.Registry) {
+	reg.NewGaugeFunc("retrohub_ipc_connected_clients",
+		"Currently connected IPC clients.", func() float64 {
+			return float64(s.ClientCount())
+		})
+}
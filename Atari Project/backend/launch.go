@@ -0,0 +1,216 @@
+/**************************************/
+/*                                    */
+/*     Game Launch Message Handlers   */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"retro-gaming-ui/backend/config"
+	"retro-gaming-ui/backend/firmware"
+	"retro-gaming-ui/backend/launcher"
+	"retro-gaming-ui/backend/library"
+	"retro-gaming-ui/backend/server"
+)
+
+/**************************************************/
+/*                                                */
+/*             PROFILE RESOLUTION                 */
+/*   Per-game profile first, platform emulator    */
+/*   from the config second                       */
+/*                                                */
+/**************************************************/
+
+func resolveLaunch(cfg *config.Config, game library.GameInfo) (launcher.Spec, error) {
+	base, hasBase := cfg.Emulators[game.Platform]
+	profile := library.LaunchProfile{}
+	if game.Launch != nil {
+		profile = *game.Launch
+	}
+
+	spec := launcher.Spec{
+		GameID: game.ID, Platform: game.Platform, ROM: game.Path,
+		Command: base.Command, Core: base.Core, Args: base.Args, WorkingDir: base.WorkingDir,
+		PreLaunch: base.PreLaunch, PostExit: base.PostExit,
+	}
+	/*   The platform's core and arguments belong  */
+	/*   to its emulator, so another one starts    */
+	/*   bare                                      */
+	if profile.Emulator != "" && profile.Emulator != base.Command {
+		spec.Command, spec.Core, spec.Args = profile.Emulator, "", nil
+	}
+	if profile.Args != nil {
+		spec.Args = profile.Args
+	}
+	if profile.Core != "" {
+		spec.Core = profile.Core
+	}
+	if profile.WorkingDir != "" {
+		spec.WorkingDir = profile.WorkingDir
+	}
+	if profile.PreLaunch != "" {
+		spec.PreLaunch = profile.PreLaunch
+	}
+	if profile.PostExit != "" {
+		spec.PostExit = profile.PostExit
+	}
+
+	spec.Env = make(map[string]string, len(base.Env)+len(profile.Env))
+	for key, value := range base.Env {
+		spec.Env[key] = value
+	}
+	for key, value := range profile.Env {
+		spec.Env[key] = value
+	}
+
+	if spec.Command == "" {
+		if !hasBase {
+			return spec, fmt.Errorf("no emulator configured for platform %s; add emulators.%s to the config or set a launch profile",
+				orNone(game.Platform), orNone(game.Platform))
+		}
+		return spec, fmt.Errorf("emulators.%s has no command", game.Platform)
+	}
+	return spec, nil
+}
+
+func orNone(platform string) string {
+	if platform == "" {
+		return "(none)"
+	}
+	return platform
+}
+
+/**************************************************/
+/*                                                */
+/*                LAUNCH GAME                     */
+/*                                                */
+/**************************************************/
+
+func (h *handlers) launchGame(ctx context.Context, req server.Request) server.Response {
+	var id string
+	server.DecodePayload(req, &id)
+	game := h.lib.GetGameByID(id)
+	if game == nil {
+		return server.Fail(req, server.ErrCodeNotFound, "Game not found")
+	}
+	if _, err := os.Stat(game.Path); err != nil {
+		return server.Fail(req, server.ErrCodeLaunchFailed, fmt.Sprintf("ROM is not available: %v", err))
+	}
+
+	cfg := h.config.Current()
+	spec, err := resolveLaunch(cfg, *game)
+	if err != nil {
+		return server.Fail(req, server.ErrCodeLaunchFailed, err.Error())
+	}
+	if err := firmwareReport(cfg, spec).Require(game.Platform); err != nil {
+		return server.Fail(req, server.ErrCodeFirmwareMissing, err.Error())
+	}
+	session, err := h.launcher.Start(spec)
+	if errors.Is(err, launcher.ErrBusy) {
+		current, _ := h.launcher.Current()
+		return server.Fail(req, server.ErrCodeBusy, fmt.Sprintf("%s is still running", current.GameID))
+	} else if err != nil {
+		return server.Fail(req, server.ErrCodeLaunchFailed, err.Error())
+	}
+
+	h.metrics.launches.Inc(game.Platform)
+	if _, err := h.lib.MarkPlayed(game.ID, session.Started); err != nil {
+		slog.Warn("cannot record play", "game", game.ID, "error", err)
+	}
+	h.ipc.Broadcast(server.TopicGameStarted, session)
+	return server.OK(req, session)
+}
+
+/*   Runs on the launcher's goroutine once the   */
+/*   emulator is gone                            */
+func (h *handlers) gameExited(session launcher.Session) {
+	if session.Crashed {
+		h.metrics.crashes.Inc(session.Platform)
+	}
+	game, err := h.lib.AddPlayTime(session.GameID, time.Duration(session.Seconds*float64(time.Second)))
+	if err != nil {
+		slog.Warn("cannot record play time", "game", session.GameID, "error", err)
+	}
+	h.ipc.Broadcast(server.TopicGameExited, map[string]interface{}{
+		"session": session, "game": game,
+	})
+}
+
+/**************************************************/
+/*                                                */
+/*                  FIRMWARE                      */
+/*                                                */
+/**************************************************/
+
+/*   Each emulator's firmware list applies only  */
+/*   when that emulator runs: a game launching   */
+/*   a different one through its profile skips  */
+/*   its platform's list                         */
+func firmwareReport(cfg *config.Config, launching launcher.Spec) firmware.Report {
+	required := cfg.RequiredFirmware()
+	if emu, ok := cfg.Emulators[launching.Platform]; ok && launching.Command != emu.Command {
+		delete(required, launching.Platform)
+	}
+	return firmware.Check(cfg.Firmware.Dirs, required)
+}
+
+func (h *handlers) getFirmwareStatus(ctx context.Context, req server.Request) server.Response {
+	var payload server.FirmwarePayload
+	if err := server.DecodePayload(req, &payload); err != nil {
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	}
+	report := firmwareReport(h.config.Current(), launcher.Spec{})
+	if payload.Platform != "" {
+		report = report.Platform(payload.Platform)
+	}
+	return server.OK(req, report)
+}
+
+/**************************************************/
+/*                                                */
+/*           SET / CLEAR LAUNCH PROFILE           */
+/*                                                */
+/**************************************************/
+
+func (h *handlers) setLaunchProfile(ctx context.Context, req server.Request) server.Response {
+	var payload server.LaunchProfilePayload
+	if err := server.DecodePayload(req, &payload); err != nil || payload.GameID == "" {
+		return server.Fail(req, server.ErrCodeBadRequest, "Missing game_id")
+	}
+	game, err := h.lib.SetLaunchProfile(payload.GameID, library.LaunchProfile{
+		Emulator: payload.Emulator, Core: payload.Core, Args: payload.Args, Env: payload.Env,
+		WorkingDir: payload.WorkingDir, PreLaunch: payload.PreLaunch, PostExit: payload.PostExit,
+	})
+	return profileResult(req, game, err)
+}
+
+func (h *handlers) clearLaunchProfile(ctx context.Context, req server.Request) server.Response {
+	var id string
+	server.DecodePayload(req, &id)
+	game, err := h.lib.ClearLaunchProfile(id)
+	return profileResult(req, game, err)
+}
+
+func profileResult(req server.Request, game *library.GameInfo, err error) server.Response {
+	switch {
+	case errors.Is(err, library.ErrGameNotFound):
+		return server.Fail(req, server.ErrCodeNotFound, "Game not found")
+	case game == nil && err != nil:
+		return server.Fail(req, server.ErrCodeBadRequest, err.Error())
+	case err != nil:
+		return server.Fail(req, server.ErrCodeInternal, err.Error())
+	}
+	return server.OK(req, game)
+}
@@ -0,0 +1,251 @@
+/**************************************/
+/*                                    */
+/*     Emulator Launcher & Sessions   */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package launcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*                 LAUNCH SPEC                    */
+/*   A fully resolved command: profile and        */
+/*   platform defaults are merged by the caller   */
+/*                                                */
+/**************************************************/
+
+type Spec struct {
+	GameID   string
+	Platform string
+	ROM      string
+
+	Command    string
+	Core       string
+	Args       []string /*   may use {rom} {core} {id} {platform} */
+	Env        map[string]string
+	WorkingDir string
+	PreLaunch  string
+	PostExit   string
+}
+
+/*   Placeholders filled in; the ROM goes last   */
+/*   when no argument asks for it                */
+func (s Spec) Argv() []string {
+	replacer := strings.NewReplacer("{rom}", s.ROM, "{core}", s.Core, "{id}", s.GameID, "{platform}", s.Platform)
+	argv := make([]string, 0, len(s.Args)+1)
+	hasROM := false
+	for _, arg := range s.Args {
+		hasROM = hasROM || strings.Contains(arg, "{rom}")
+		argv = append(argv, replacer.Replace(arg))
+	}
+	if !hasROM {
+		argv = append(argv, s.ROM)
+	}
+	return argv
+}
+
+/*   The process environment, then Env, then     */
+/*   RETROHUB_* variables describing the game    */
+func (s Spec) environ(extra ...string) []string {
+	env := os.Environ()
+	keys := make([]string, 0, len(s.Env))
+	for key := range s.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env = append(env, key+"="+s.Env[key])
+	}
+	env = append(env,
+		"RETROHUB_GAME_ID="+s.GameID, "RETROHUB_PLATFORM="+s.Platform, "RETROHUB_ROM="+s.ROM,
+		"RETROHUB_EMULATOR="+s.Command, "RETROHUB_CORE="+s.Core)
+	return append(env, extra...)
+}
+
+/**************************************************/
+/*                                                */
+/*                  SESSIONS                      */
+/*                                                */
+/**************************************************/
+
+type Session struct {
+	ID       string    `json:"id"`
+	GameID   string    `json:"game_id"`
+	Platform string    `json:"platform"`
+	Command  string    `json:"command"`
+	PID      int       `json:"pid"`
+	Started  time.Time `json:"started"`
+	Ended    time.Time `json:"ended,omitempty"`
+	Seconds  float64   `json:"seconds,omitempty"`
+	ExitCode int       `json:"exit_code"`
+	Crashed  bool      `json:"crashed,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+func (s Session) Running() bool {
+	return s.Ended.IsZero()
+}
+
+var ErrBusy = errors.New("a game is already running")
+
+/*   Hooks that take longer are killed           */
+const HookTimeout = 30 * time.Second
+
+/**************************************************/
+/*                                                */
+/*                 LAUNCHER                       */
+/*   One emulator at a time, as on a cabinet      */
+/*                                                */
+/**************************************************/
+
+type Launcher struct {
+	mu      sync.Mutex
+	current *Session
+	seq     int
+
+	/*   Set while the pre-launch hook runs, which  */
+	/*   is done without holding mu                 */
+	starting bool
+
+	onExit func(Session)
+	logger *slog.Logger
+}
+
+/*   onExit runs once per session after the     */
+/*   emulator and its post-exit hook are done   */
+func New(logger *slog.Logger, onExit func(Session)) *Launcher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Launcher{onExit: onExit, logger: logger}
+}
+
+func (l *Launcher) Current() (Session, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.current == nil {
+		return Session{}, false
+	}
+	return *l.current, true
+}
+
+func (l *Launcher) Start(spec Spec) (Session, error) {
+	if spec.Command == "" {
+		return Session{}, fmt.Errorf("no emulator configured for %s", spec.Platform)
+	}
+
+	/*   Reserve the slot, then let go of the lock  */
+	/*   for the hook so Current stays responsive   */
+	l.mu.Lock()
+	if l.current != nil || l.starting {
+		l.mu.Unlock()
+		return Session{}, ErrBusy
+	}
+	l.starting = true
+	l.mu.Unlock()
+
+	if spec.PreLaunch != "" {
+		if err := l.runHook(spec, "pre-launch", spec.PreLaunch); err != nil {
+			l.release()
+			return Session{}, fmt.Errorf("pre-launch hook failed: %w", err)
+		}
+	}
+
+	cmd := exec.Command(spec.Command, spec.Argv()...)
+	cmd.Dir = spec.WorkingDir
+	cmd.Env = spec.environ()
+	if err := cmd.Start(); err != nil {
+		l.release()
+		return Session{}, fmt.Errorf("cannot start %s: %w", spec.Command, err)
+	}
+
+	l.mu.Lock()
+	l.seq++
+	session := &Session{
+		ID: strconv.Itoa(l.seq), GameID: spec.GameID, Platform: spec.Platform,
+		Command: spec.Command, PID: cmd.Process.Pid, Started: time.Now(),
+	}
+	l.current, l.starting = session, false
+	l.mu.Unlock()
+	l.logger.Info("emulator started", "game", spec.GameID, "command", spec.Command, "pid", session.PID)
+
+	go l.wait(cmd, spec, *session)
+	return *session, nil
+}
+
+func (l *Launcher) release() {
+	l.mu.Lock()
+	l.starting = false
+	l.mu.Unlock()
+}
+
+func (l *Launcher) wait(cmd *exec.Cmd, spec Spec, session Session) {
+	err := cmd.Wait()
+	session.Ended = time.Now()
+	session.Seconds = session.Ended.Sub(session.Started).Seconds()
+	session.ExitCode = cmd.ProcessState.ExitCode() /*   -1 if killed by a signal */
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		session.Error = err.Error()
+	}
+	session.Crashed = session.ExitCode != 0
+	if session.Crashed {
+		l.logger.Warn("emulator exited abnormally", "game", session.GameID, "exit_code", session.ExitCode,
+			"state", cmd.ProcessState.String())
+	} else {
+		l.logger.Info("emulator exited", "game", session.GameID, "seconds", int(session.Seconds))
+	}
+
+	if spec.PostExit != "" {
+		if err := l.runHook(spec, "post-exit", spec.PostExit,
+			"RETROHUB_EXIT_CODE="+strconv.Itoa(session.ExitCode),
+			"RETROHUB_PLAY_SECONDS="+strconv.Itoa(int(session.Seconds))); err != nil {
+			l.logger.Warn("post-exit hook failed", "game", session.GameID, "error", err)
+		}
+	}
+
+	l.mu.Lock()
+	l.current = nil
+	l.mu.Unlock()
+	if l.onExit != nil {
+		l.onExit(session)
+	}
+}
+
+/*   Hooks run directly, not through a shell,    */
+/*   with the game described in RETROHUB_*      */
+func (l *Launcher) runHook(spec Spec, name, script string, extra ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), HookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, script)
+	cmd.Dir = spec.WorkingDir
+	cmd.Env = spec.environ(append([]string{"RETROHUB_HOOK=" + name}, extra...)...)
+	output, err := cmd.CombinedOutput()
+	if len(output) > 0 {
+		l.logger.Debug("hook output", "hook", name, "game", spec.GameID, "output", strings.TrimSpace(string(output)))
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s timed out after %s", script, HookTimeout)
+	}
+	return err
+}
@@ -11,11 +11,20 @@
 package library
 
 import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -37,6 +46,18 @@ type GameInfo struct {
 	PlayCount   int       `json:"play_count"`
 	Favorite    bool      `json:"favorite"`
 	Category    string    `json:"category"`
+	Tags        []string  `json:"tags,omitempty"`
+
+	/*   Content identity, filled in by Scan       */
+	Hash  string `json:"hash,omitempty"`
+	CRC32 string `json:"crc32,omitempty"`
+	Size  int64  `json:"size,omitempty"`
+
+	/*   Total seconds spent in the emulator       */
+	PlaySeconds int64 `json:"play_seconds,omitempty"`
+
+	/*   Overrides the platform's emulator         */
+	Launch *LaunchProfile `json:"launch,omitempty"`
 }
 
 /**************************************************/
@@ -48,14 +69,21 @@ type GameInfo struct {
 
 type Library struct {
 	Games      []GameInfo     `json:"games"`
-	ScanPaths  []string       `json:"scan_paths"`
+	ScanPaths  []ScanPath     `json:"scan_paths"`
 	Categories map[string]int `json:"categories"`
 	Platforms  map[string]int `json:"platforms"`
 	LastScan   time.Time      `json:"last_scan"`
 	mu         sync.RWMutex
 	configPath string
+	loaded     bool
+	loadErr    error
+	logger     *slog.Logger
+	scanning   atomic.Bool
+	listeners  []func()
 }
 
+var ErrGameNotFound = errors.New("game not found")
+
 /*    Supported ROM extensions by platform       */
 var platformExtensions = map[string][]string{
 	"NES":   {".nes", ".unf", ".unif"},
@@ -75,84 +103,301 @@ var platformExtensions = map[string][]string{
 func NewLibrary(configPath string) *Library {
 	lib := &Library{
 		Games:      make([]GameInfo, 0),
-		ScanPaths:  make([]string, 0),
+		ScanPaths:  make([]ScanPath, 0),
 		Categories: make(map[string]int),
 		Platforms:  make(map[string]int),
 		configPath: configPath,
+		logger:     slog.Default(),
+	}
+	if err := lib.Load(); err != nil {
+		lib.logger.Error("failed to load library", "path", configPath, "error", err)
 	}
-	lib.Load()
 	return lib
 }
 
+func (lib *Library) SetLogger(logger *slog.Logger) {
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+	lib.logger = logger
+}
+
+/*   Called after every successful save, on its  */
+/*   own goroutine so the library stays unlocked */
+func (lib *Library) OnChange(fn func()) {
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+	lib.listeners = append(lib.listeners, fn)
+}
+
 /**************************************************/
 /*                                                */
 /*              ADD SCAN PATH                     */
 /*                                                */
 /**************************************************/
 
+/*   Adds path with default options; an existing */
+/*   entry keeps the options it has              */
 func (lib *Library) AddScanPath(path string) error {
+	sp := ScanPath{Path: path}.normalized()
+	if err := checkScanDir(sp.Path); err != nil {
+		return err
+	}
+
 	lib.mu.Lock()
 	defer lib.mu.Unlock()
+	if lib.scanPathIndex(sp.Path) < 0 {
+		lib.ScanPaths = append(lib.ScanPaths, sp)
+	}
+	return nil
+}
 
+/*   Adds the path or replaces its options       */
+func (lib *Library) SetScanPath(sp ScanPath) error {
+	sp = sp.normalized()
+	if err := sp.Validate(); err != nil {
+		return err
+	}
+	if err := checkScanDir(sp.Path); err != nil {
+		return err
+	}
+
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+	if i := lib.scanPathIndex(sp.Path); i >= 0 {
+		lib.ScanPaths[i] = sp
+	} else {
+		lib.ScanPaths = append(lib.ScanPaths, sp)
+	}
+	return nil
+}
+
+func checkScanDir(path string) error {
 	info, err := os.Stat(path)
 	if err != nil {
 		return err
 	}
 	if !info.IsDir() {
-		return os.ErrNotExist
+		return fmt.Errorf("%s is not a directory", path)
 	}
+	return nil
+}
 
-	for _, p := range lib.ScanPaths {
-		if p == path {
-			return nil
+func (lib *Library) scanPathIndex(path string) int {
+	for i, sp := range lib.ScanPaths {
+		if sp.Path == path {
+			return i
 		}
 	}
+	return -1
+}
 
-	lib.ScanPaths = append(lib.ScanPaths, path)
-	return nil
+/**************************************************/
+/*                                                */
+/*             REMOVE SCAN PATH                   */
+/*                                                */
+/**************************************************/
+
+func (lib *Library) RemoveScanPath(path string) error {
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+
+	i := lib.scanPathIndex(path)
+	if i < 0 {
+		i = lib.scanPathIndex(filepath.Clean(path))
+	}
+	if i >= 0 {
+		lib.ScanPaths = append(lib.ScanPaths[:i], lib.ScanPaths[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("%s is not a scan path", path)
+}
+
+func (lib *Library) GetScanPaths() []ScanPath {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
+	return append([]ScanPath{}, lib.ScanPaths...)
 }
 
 /**************************************************/
 /*                                                */
 /*         SCAN FOR GAMES IN PATHS                */
+/*   Walks without holding the lock, then swaps   */
+/*   the result in, keeping favorites and stats   */
 /*                                                */
 /**************************************************/
 
+type ScanProgress struct {
+	Path         string `json:"path"`
+	FilesScanned int    `json:"files_scanned"`
+	FilesTotal   int    `json:"files_total"`
+	GamesFound   int    `json:"games_found"`
+	Done         bool   `json:"done"`
+}
+
+/*   Outcome of a finished scan, kept by the    */
+/*   backend for status                         */
+type ScanReport struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Games    int       `json:"games"`
+	Canceled bool      `json:"canceled,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+var ErrScanInProgress = errors.New("a scan is already in progress")
+
+/*   Report progress every this many files      */
+const progressEvery = 25
+
 func (lib *Library) Scan() error {
-	lib.mu.Lock()
-	defer lib.mu.Unlock()
+	return lib.ScanContext(context.Background(), nil)
+}
 
-	lib.Games = make([]GameInfo, 0)
-	lib.Categories = make(map[string]int)
-	lib.Platforms = make(map[string]int)
+func (lib *Library) IsScanning() bool {
+	return lib.scanning.Load()
+}
+
+func (lib *Library) ScanContext(ctx context.Context, progress func(ScanProgress)) error {
+	if !lib.scanning.CompareAndSwap(false, true) {
+		return ErrScanInProgress
+	}
+	defer lib.scanning.Store(false)
+
+	scanPaths := lib.GetScanPaths()
+	state := ScanProgress{}
 
-	for _, scanPath := range lib.ScanPaths {
-		filepath.Walk(scanPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil || info.IsDir() {
+	/*   Unchanged files keep their hash so a      */
+	/*   rescan only reads new or resized ROMs     */
+	lib.mu.RLock()
+	known := make(map[string]GameInfo, len(lib.Games))
+	for _, game := range lib.Games {
+		known[game.Path] = game
+	}
+	lib.mu.RUnlock()
+
+	/*   Counting pass so progress has a total   */
+	if progress != nil {
+		for _, sp := range scanPaths {
+			err := lib.walkScanPath(ctx, sp, func(path string, info os.FileInfo) error {
+				state.FilesTotal++
 				return nil
+			})
+			if err != nil {
+				return err
 			}
+		}
+		progress(state)
+	}
+
+	/*   Nested or symlinked scan paths must not   */
+	/*   list the same ROM twice                   */
+	found := make([]GameInfo, 0)
+	seen := make(map[string]bool)
+	for _, sp := range scanPaths {
+		category := sp.Category
+		if category == "" {
+			category = "Uncategorized"
+		}
 
-			ext := strings.ToLower(filepath.Ext(path))
-			platform := lib.detectPlatform(ext)
+		err := lib.walkScanPath(ctx, sp, func(path string, info os.FileInfo) error {
+			key := path
+			if real, err := filepath.EvalSymlinks(path); err == nil {
+				key = real
+			}
+			if seen[key] {
+				return nil
+			}
+			seen[key] = true
 
-			if platform != "" {
+			if platform := sp.platformFor(path); platform != "" {
 				game := GameInfo{
 					ID:       generateID(path),
 					Title:    cleanGameTitle(filepath.Base(path)),
 					Platform: platform,
 					Path:     path,
-					Category: "Uncategorized",
+					Category: category,
+					Size:     info.Size(),
+				}
+				if old, ok := known[path]; ok && old.Hash != "" && old.Size == info.Size() {
+					game.Hash, game.CRC32 = old.Hash, old.CRC32
+				} else if sum, err := hashFile(path); err == nil {
+					game.Hash, game.CRC32 = sum.SHA1, sum.CRC32
+				} else {
+					lib.logger.Debug("failed to hash ROM", "path", path, "error", err)
 				}
-				lib.Games = append(lib.Games, game)
-				lib.Platforms[platform]++
-				lib.Categories[game.Category]++
+				found = append(found, game)
+				state.GamesFound++
+			}
+
+			state.FilesScanned++
+			if progress != nil && state.FilesScanned%progressEvery == 0 {
+				state.Path = path
+				progress(state)
 			}
 			return nil
 		})
+		if err != nil {
+			lib.logger.Info("library scan cancelled", "files", state.FilesScanned)
+			return err
+		}
 	}
 
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+
+	previous := make(map[string]GameInfo, len(lib.Games))
+	for _, game := range lib.Games {
+		previous[game.ID] = game
+	}
+	for i, game := range found {
+		if old, ok := previous[game.ID]; ok {
+			found[i] = mergeUserData(game, old)
+		}
+	}
+	lib.Games = found
+	lib.recountUnlocked()
+
 	lib.LastScan = time.Now()
-	return lib.Save()
+	lib.logger.Info("library scan complete", "games", len(lib.Games), "paths", len(scanPaths))
+	err := lib.saveUnlocked()
+
+	if progress != nil {
+		state.Path = ""
+		state.Done = true
+		progress(state)
+	}
+	return err
+}
+
+/*   Carries curated fields of an existing      */
+/*   entry over to a freshly scanned one         */
+func mergeUserData(scanned, old GameInfo) GameInfo {
+	scanned.Favorite = old.Favorite
+	scanned.PlayCount = old.PlayCount
+	scanned.LastPlayed = old.LastPlayed
+	scanned.CoverPath = old.CoverPath
+	scanned.Tags = old.Tags
+	scanned.PlaySeconds = old.PlaySeconds
+	scanned.Launch = old.Launch
+	if old.Description != "" {
+		scanned.Description = old.Description
+	}
+	if old.Category != "" && old.Category != "Uncategorized" {
+		scanned.Category = old.Category
+	}
+	if old.Title != "" {
+		scanned.Title = old.Title
+	}
+	return scanned
+}
+
+func (lib *Library) recountUnlocked() {
+	lib.Categories = make(map[string]int)
+	lib.Platforms = make(map[string]int)
+	for _, game := range lib.Games {
+		lib.Platforms[game.Platform]++
+		lib.Categories[game.Category]++
+	}
 }
 
 /**************************************************/
@@ -162,6 +407,10 @@ func (lib *Library) Scan() error {
 /**************************************************/
 
 func (lib *Library) detectPlatform(ext string) string {
+	return detectPlatform(ext)
+}
+
+func detectPlatform(ext string) string {
 	for platform, extensions := range platformExtensions {
 		for _, e := range extensions {
 			if ext == e {
@@ -199,6 +448,36 @@ func (lib *Library) GetGames(platform, category string) []GameInfo {
 	return filtered
 }
 
+/*   Case-insensitive match on title or ID;      */
+/*   every word of the query must appear         */
+func (game GameInfo) Matches(query string) bool {
+	haystack := strings.ToLower(game.Title + " " + game.ID)
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
+
+/**************************************************/
+/*                                                */
+/*            PLATFORM / CATEGORY COUNTS          */
+/*                                                */
+/**************************************************/
+
+func (lib *Library) PlatformCounts() map[string]int {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
+	return copyCounts(lib.Platforms)
+}
+
+func (lib *Library) CategoryCounts() map[string]int {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
+	return copyCounts(lib.Categories)
+}
+
 /**************************************************/
 /*                                                */
 /*             GET GAME BY ID                     */
@@ -233,7 +512,7 @@ func (lib *Library) ToggleFavorite(id string) error {
 			return lib.saveUnlocked()
 		}
 	}
-	return nil
+	return ErrGameNotFound
 }
 
 /**************************************************/
@@ -301,21 +580,49 @@ func (lib *Library) saveUnlocked() error {
 	}
 	dir := filepath.Dir(lib.configPath)
 	os.MkdirAll(dir, 0755)
-	return os.WriteFile(lib.configPath, data, 0644)
+	if err := os.WriteFile(lib.configPath, data, 0644); err != nil {
+		return err
+	}
+	for _, fn := range lib.listeners {
+		go fn()
+	}
+	return nil
 }
 
 func (lib *Library) Load() error {
 	lib.mu.Lock()
 	defer lib.mu.Unlock()
 
+	lib.loaded, lib.loadErr = false, nil
 	data, err := os.ReadFile(lib.configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
+			lib.loaded = true
 			return nil
 		}
+		lib.loadErr = err
 		return err
 	}
-	return json.Unmarshal(data, lib)
+	if err := json.Unmarshal(data, lib); err != nil {
+		lib.loadErr = err
+		return err
+	}
+	lib.loaded = true
+	return nil
+}
+
+/*   Nil once library.json has been read (or    */
+/*   found absent) without error                 */
+func (lib *Library) LoadState() error {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
+	if lib.loadErr != nil {
+		return lib.loadErr
+	}
+	if !lib.loaded {
+		return errors.New("library not loaded")
+	}
+	return nil
 }
 
 /**************************************************/
@@ -336,6 +643,42 @@ func generateID(path string) string {
 	return strings.ToUpper(base) + string(rune('A'+hash%26))
 }
 
+/*   SHA-1 identifies content; CRC32 is what     */
+/*   RetroArch playlists and DAT files carry     */
+type fileSum struct {
+	SHA1  string
+	CRC32 string
+	Size  int64
+}
+
+func hashFile(path string) (fileSum, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return fileSum{}, err
+	}
+	defer file.Close()
+
+	sha := sha1.New()
+	crc := crc32.NewIEEE()
+	size, err := io.Copy(io.MultiWriter(sha, crc), file)
+	if err != nil {
+		return fileSum{}, err
+	}
+	return fileSum{
+		SHA1:  hex.EncodeToString(sha.Sum(nil)),
+		CRC32: fmt.Sprintf("%08X", crc.Sum32()),
+		Size:  size,
+	}, nil
+}
+
+func copyCounts(counts map[string]int) map[string]int {
+	out := make(map[string]int, len(counts))
+	for k, v := range counts {
+		out[k] = v
+	}
+	return out
+}
+
 func cleanGameTitle(filename string) string {
 	title := strings.TrimSuffix(filename, filepath.Ext(filename))
 	title = strings.ReplaceAll(title, "_", " ")
@@ -0,0 +1,136 @@
+/**************************************/
+/*                                    */
+/*     IPC Connection Limits - Go     */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package server
+
+import (
+	"bufio"
+	"errors"
+	"sync"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*              LIMITS CONFIGURATION              */
+/*        Zero disables the individual limit      */
+/*                                                */
+/**************************************************/
+
+type Limits struct {
+	MaxMessageSize int           `json:"max_message_size"`
+	IdleTimeout    time.Duration `json:"idle_timeout"`
+	ReadTimeout    time.Duration `json:"read_timeout"`
+	WriteTimeout   time.Duration `json:"write_timeout"`
+	MaxConnections int           `json:"max_connections"`
+	RateLimit      float64       `json:"rate_limit"`
+	RateBurst      int           `json:"rate_burst"`
+}
+
+func DefaultLimits() Limits {
+	return Limits{
+		MaxMessageSize: 1 << 20,
+		IdleTimeout:    5 * time.Minute,
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   10 * time.Second,
+		MaxConnections: 32,
+		RateLimit:      50,
+		RateBurst:      100,
+	}
+}
+
+func (s *IPCServer) SetLimits(limits Limits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = limits
+}
+
+func (s *IPCServer) Limits() Limits {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.limits
+}
+
+/**************************************************/
+/*                                                */
+/*             SIZE-LIMITED FRAMING               */
+/*                                                */
+/**************************************************/
+
+var errMessageTooLarge = errors.New("message too large")
+
+/*   Reads one newline-terminated message while  */
+/*   never buffering more than max bytes         */
+func readMessage(reader *bufio.Reader, max int) ([]byte, error) {
+	var buf []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if max > 0 && len(buf)+len(chunk) > max+1 {
+			return nil, errMessageTooLarge
+		}
+		buf = append(buf, chunk...)
+		if err == nil {
+			return buf, nil
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return nil, err
+		}
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*           TOKEN BUCKET RATE LIMITER            */
+/*                                                */
+/**************************************************/
+
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (r *rateLimiter) Allow() bool {
+	if r == nil {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.tokens += now.Sub(r.last).Seconds() * r.rate
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.last = now
+
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
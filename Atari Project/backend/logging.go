@@ -0,0 +1,386 @@
+/**************************************/
+/*                                    */
+/*   Structured Logging - Go Backend  */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*               LOGGER OPTIONS                   */
+/*                                                */
+/**************************************************/
+
+type Options struct {
+	Level      string `json:"level"`
+	Format     string `json:"format"`
+	File       string `json:"file"`
+	MaxSizeMB  int    `json:"max_size_mb"`
+	MaxBackups int    `json:"max_backups"`
+	RingSize   int    `json:"-"`
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Level:      "info",
+		Format:     "text",
+		MaxSizeMB:  10,
+		MaxBackups: 3,
+		RingSize:   500,
+	}
+}
+
+/*   Everything a running backend needs to keep  */
+/*   hold of after Setup                         */
+type Logging struct {
+	Logger *slog.Logger
+	Level  *slog.LevelVar
+	Ring   *Ring
+	closer io.Closer
+}
+
+func (l *Logging) Close() error {
+	if l.closer != nil {
+		return l.closer.Close()
+	}
+	return nil
+}
+
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q", name)
+	}
+	return level, nil
+}
+
+/**************************************************/
+/*                                                */
+/*                    SETUP                       */
+/*                                                */
+/**************************************************/
+
+func Setup(opts Options) (*Logging, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(level)
+
+	var out io.Writer = os.Stderr
+	var closer io.Closer
+	if opts.File != "" {
+		file, err := OpenRotatingFile(opts.File, int64(opts.MaxSizeMB)<<20, opts.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		out, closer = file, file
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: levelVar}
+	var primary slog.Handler
+	switch strings.ToLower(opts.Format) {
+	case "", "text":
+		primary = slog.NewTextHandler(out, handlerOpts)
+	case "json":
+		primary = slog.NewJSONHandler(out, handlerOpts)
+	default:
+		if closer != nil {
+			closer.Close()
+		}
+		return nil, fmt.Errorf("invalid log format %q (want text or json)", opts.Format)
+	}
+
+	ring := NewRing(opts.RingSize)
+	logger := slog.New(&fanout{handlers: []slog.Handler{primary, ring.handler(levelVar)}})
+	return &Logging{Logger: logger, Level: levelVar, Ring: ring, closer: closer}, nil
+}
+
+/**************************************************/
+/*                                                */
+/*               FAN-OUT HANDLER                  */
+/*                                                */
+/**************************************************/
+
+type fanout struct {
+	handlers []slog.Handler
+}
+
+func (f *fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fanout) Handle(ctx context.Context, record slog.Record) error {
+	var firstErr error
+	for _, h := range f.handlers {
+		if !h.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, record.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f *fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make([]slog.Handler, len(f.handlers))
+	for i, h := range f.handlers {
+		next[i] = h.WithAttrs(attrs)
+	}
+	return &fanout{handlers: next}
+}
+
+func (f *fanout) WithGroup(name string) slog.Handler {
+	next := make([]slog.Handler, len(f.handlers))
+	for i, h := range f.handlers {
+		next[i] = h.WithGroup(name)
+	}
+	return &fanout{handlers: next}
+}
+
+/**************************************************/
+/*                                                */
+/*           RECENT RECORDS RING BUFFER           */
+/*                                                */
+/**************************************************/
+
+type Record struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level"`
+	Message string                 `json:"message"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	level   slog.Level
+}
+
+type Ring struct {
+	mu        sync.Mutex
+	records   []Record
+	next      int
+	full      bool
+	listeners []func(Record)
+}
+
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		size = 500
+	}
+	return &Ring{records: make([]Record, size)}
+}
+
+/*   Called for every record after it is stored; */
+/*   must not log through the same logger        */
+func (r *Ring) OnRecord(fn func(Record)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+func (r *Ring) add(rec Record) {
+	r.mu.Lock()
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+	listeners := append([]func(Record){}, r.listeners...)
+	r.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(rec)
+	}
+}
+
+/*   Oldest first; limit <= 0 returns everything */
+/*   held at or above minLevel                   */
+func (r *Ring) Recent(limit int, minLevel slog.Level) []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ordered := make([]Record, 0, len(r.records))
+	if r.full {
+		ordered = append(ordered, r.records[r.next:]...)
+	}
+	ordered = append(ordered, r.records[:r.next]...)
+
+	out := make([]Record, 0, len(ordered))
+	for _, rec := range ordered {
+		if rec.level >= minLevel {
+			out = append(out, rec)
+		}
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out
+}
+
+func (r *Ring) handler(level slog.Leveler) slog.Handler {
+	return &ringHandler{ring: r, level: level}
+}
+
+type ringHandler struct {
+	ring   *Ring
+	level  slog.Leveler
+	attrs  []slog.Attr
+	groups []string
+}
+
+func (h *ringHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *ringHandler) Handle(_ context.Context, record slog.Record) error {
+	attrs := make(map[string]interface{})
+	prefix := ""
+	if len(h.groups) > 0 {
+		prefix = strings.Join(h.groups, ".") + "."
+	}
+	for _, a := range h.attrs {
+		addAttr(attrs, prefix, a)
+	}
+	record.Attrs(func(a slog.Attr) bool {
+		addAttr(attrs, prefix, a)
+		return true
+	})
+
+	h.ring.add(Record{
+		Time:    record.Time,
+		Level:   record.Level.String(),
+		Message: record.Message,
+		Attrs:   attrs,
+		level:   record.Level,
+	})
+	return nil
+}
+
+func (h *ringHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := *h
+	next.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	return &next
+}
+
+func (h *ringHandler) WithGroup(name string) slog.Handler {
+	next := *h
+	next.groups = append(append([]string(nil), h.groups...), name)
+	return &next
+}
+
+func addAttr(out map[string]interface{}, prefix string, a slog.Attr) {
+	value := a.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		for _, inner := range value.Group() {
+			addAttr(out, prefix+a.Key+".", inner)
+		}
+		return
+	}
+	switch value.Kind() {
+	case slog.KindDuration:
+		out[prefix+a.Key] = value.Duration().String()
+	case slog.KindTime:
+		out[prefix+a.Key] = value.Time()
+	default:
+		out[prefix+a.Key] = value.Any()
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*              ROTATING LOG FILE                 */
+/*   name.log -> name.log.1 -> ... name.log.N     */
+/*                                                */
+/**************************************************/
+
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size = file, info.Size()
+	return nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxSize > 0 && r.size+int64(len(p)) > r.maxSize && r.size > 0 {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	if r.maxBackups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxBackups))
+		for i := r.maxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		os.Rename(r.path, r.path+".1")
+	} else {
+		os.Remove(r.path)
+	}
+	return r.open()
+}
+
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
@@ -11,27 +11,31 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
+	"time"
 
+	"retro-gaming-ui/backend/config"
+	"retro-gaming-ui/backend/instance"
+	"retro-gaming-ui/backend/launcher"
 	"retro-gaming-ui/backend/library"
+	"retro-gaming-ui/backend/logging"
 	"retro-gaming-ui/backend/server"
 )
 
-/**************************************************/
-/*                                                */
-/*                   CONSTANTS                    */
-/*                                                */
-/**************************************************/
+/*   How long --daemon waits for the detached    */
+/*   backend to report ready                     */
+const daemonStartTimeout = 30 * time.Second
 
-const (
-	IPC_PORT    = 9847
-	CONFIG_FILE = "library.json"
-)
+/*   sd_notify target(s); a no-op unless run by  */
+/*   systemd or as a --daemon child              */
+var notifier = instance.NotifierFromEnv()
 
 /**************************************************/
 /*                                                */
@@ -40,128 +44,170 @@ const (
 /**************************************************/
 
 func main() {
-	fmt.Println("╔════════════════════════════════════════╗")
-	fmt.Println("║    RETRO GAMING HUB - BACKEND          ║")
-	fmt.Println("║    Frutiger Aero • Y2K Edition         ║")
-	fmt.Println("╚════════════════════════════════════════╝")
+	daemon := flag.Bool("daemon", false, "detach and run in the background once ready")
 
-	/*          Get config directory              */
-	configDir, err := os.UserConfigDir()
+	/*     Config: defaults < file < env < flags   */
+	loader, err := config.NewLoader(flag.CommandLine, os.Args[1:])
 	if err != nil {
-		configDir = "."
+		os.Exit(2)
 	}
-	configPath := filepath.Join(configDir, "retro-gaming-hub", CONFIG_FILE)
 
-	/*           Initialize library               */
-	lib := library.NewLibrary(configPath)
-	fmt.Printf("Library loaded from: %s\n", configPath)
+	if *daemon && !instance.IsDaemonChild() {
+		ctx, cancel := context.WithTimeout(context.Background(), daemonStartTimeout)
+		pid, err := instance.Daemonize(ctx, os.Args[1:])
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to start backend: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Backend running in the background (pid %d)\n", pid)
+		return
+	}
 
-	/*           Create IPC server                */
-	ipcServer := server.NewIPCServer(IPC_PORT)
+	cfg, err := loader.Load()
+	if err != nil {
+		exitStartup(2, "Invalid configuration: %v", err)
+	}
 
-	/*          Set up message handler            */
-	ipcServer.SetHandler(func(req server.Request) server.Response {
-		return handleRequest(lib, req)
-	})
+	/*    One backend per config dir, so two never  */
+	/*    write library.json at the same time       */
+	lock, err := instance.Acquire(cfg.Dir, instance.Info{Listen: cfg.IPC.Listen})
+	if err != nil {
+		exitStartup(1, "Cannot start: %v", err)
+	}
+	defer lock.Release()
 
-	/*              Start server                  */
-	if err := ipcServer.Start(); err != nil {
-		fmt.Printf("Failed to start server: %v\n", err)
-		os.Exit(1)
+	/*              Set up logging                */
+	logs, err := logging.Setup(cfg.Log)
+	if err != nil {
+		exitStartup(2, "Invalid logging options: %v", err)
 	}
+	defer logs.Close()
+	logger := logs.Logger
+	slog.SetDefault(logger)
 
-	/*        Wait for shutdown signal            */
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	logger.Info("retro gaming hub backend starting",
+		"edition", "Frutiger Aero Y2K", "protocol", server.ProtocolVersion, "config", cfg.File)
 
-	fmt.Println("\nBackend running. Press Ctrl+C to stop.")
-	<-sigChan
+	/*           Initialize library               */
+	lib := library.NewLibrary(cfg.Library.Path)
+	logger.Info("library loaded", "path", cfg.Library.Path)
 
-	fmt.Println("\nShutting down...")
-	ipcServer.Stop()
-	lib.Save()
-	fmt.Println("Goodbye!")
-}
+	/*           Create IPC server                */
+	ipcServer := server.NewIPCServerAddr(cfg.IPC.Listen)
+	ipcServer.SetLogger(logger)
+	applyLiveConfig(nil, cfg, logs, lib, ipcServer, logger)
 
-/**************************************************/
-/*                                                */
-/*               REQUEST HANDLER                  */
-/*                                                */
-/**************************************************/
+	/*      Load auth tokens (created if missing)    */
+	auth, err := server.EnsureTokenFile(cfg.IPC.TokensFile)
+	if err != nil {
+		fatal(logger, "failed to load auth tokens", "path", cfg.IPC.TokensFile, "error", err)
+	}
+	ipcServer.SetAuthenticator(auth)
+	logger.Info("auth tokens loaded", "path", cfg.IPC.TokensFile)
+
+	reload := &reloader{loader: loader, cfg: cfg, logs: logs, lib: lib, ipc: ipcServer, notify: notifier, logger: logger}
+
+	/*       Set up middleware and routes         */
+	hubMetrics := newHubMetrics(lib)
+	ipcServer.RegisterMetrics(hubMetrics.registry)
+	ipcServer.Use(
+		server.RequestID(),
+		server.Logging(logger),
+		server.Instrument(hubMetrics.registry),
+		server.Recover(logger),
+	)
+	h := &handlers{lib: lib, ipc: ipcServer, metrics: hubMetrics, logs: logs.Ring, config: reload}
+	h.launcher = launcher.New(logger, h.gameExited)
+	ipcServer.SetRouter(newRouter(h))
+
+	/*      Stream new log records to subscribers    */
+	logs.Ring.OnRecord(func(rec logging.Record) {
+		ipcServer.Broadcast(server.TopicLogRecord, rec)
+	})
 
-func handleRequest(lib *library.Library, req server.Request) server.Response {
-	switch req.Type {
+	/*   Tell clients to reload after any save     */
+	lib.OnChange(func() {
+		stats := lib.Stats()
+		ipcServer.Broadcast(server.TopicLibraryChanged, map[string]interface{}{
+			"games": stats.Games, "last_scan": stats.LastScan,
+		})
+	})
 
-	case server.MsgTypeListGames:
-		var payload server.GameListPayload
-		if req.Payload != nil {
-			json.Unmarshal(req.Payload, &payload)
-		}
-		games := lib.GetGames(payload.Platform, payload.Category)
-		return server.Response{
-			Type: server.MsgTypeSuccess, ID: req.ID,
-			Success: true, Data: games,
+	/*              Start server                  */
+	if err := ipcServer.Start(); err != nil {
+		fatal(logger, "failed to start server", "error", err)
+	}
+
+	/*      Optional metrics / health endpoint    */
+	if cfg.Metrics.Listen != "" {
+		metricsServer, err := startMetricsEndpoint(cfg.Metrics.Listen, hubMetrics, lib, ipcServer)
+		if err != nil {
+			fatal(logger, "failed to start metrics endpoint", "error", err)
 		}
+		defer metricsServer.Close()
+		logger.Info("metrics endpoint started", "url", "http://"+cfg.Metrics.Listen+"/metrics")
+	}
 
-	case server.MsgTypeGetGame:
-		var id string
-		json.Unmarshal(req.Payload, &id)
-		game := lib.GetGameByID(id)
-		if game == nil {
-			return server.Response{
-				Type: server.MsgTypeError, ID: req.ID,
-				Success: false, Error: "Game not found",
+	/*    Tell systemd / the daemon parent we are  */
+	/*    up, and keep the watchdog fed            */
+	notifier.Status(fmt.Sprintf("Serving %d games on %s", len(lib.GetGames("", "")), cfg.IPC.Listen))
+	notifier.Ready()
+	watchdogCtx, stopWatchdog := context.WithCancel(context.Background())
+	defer stopWatchdog()
+	if interval, ok := instance.WatchdogInterval(); ok {
+		go notifier.RunWatchdog(watchdogCtx, interval, func() error {
+			if !ipcServer.IsRunning() {
+				return errors.New("IPC server not running")
 			}
-		}
-		return server.Response{
-			Type: server.MsgTypeSuccess, ID: req.ID,
-			Success: true, Data: game,
-		}
+			return nil
+		})
+		logger.Info("systemd watchdog enabled", "interval", interval)
+	}
 
-	case server.MsgTypeGetFavorites:
-		return server.Response{
-			Type: server.MsgTypeSuccess, ID: req.ID,
-			Success: true, Data: lib.GetFavorites(),
-		}
+	/*   Wait for shutdown, reloading on SIGHUP    */
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
-	case server.MsgTypeToggleFavorite:
-		var id string
-		json.Unmarshal(req.Payload, &id)
-		if err := lib.ToggleFavorite(id); err != nil {
-			return server.Response{
-				Type: server.MsgTypeError, ID: req.ID,
-				Success: false, Error: err.Error(),
-			}
-		}
-		return server.Response{
-			Type: server.MsgTypeSuccess, ID: req.ID, Success: true,
-		}
+	logger.Info("backend running, press Ctrl+C to stop")
+	sig := <-sigChan
+	for sig == syscall.SIGHUP {
+		reload.Reload()
+		sig = <-sigChan
+	}
 
-	case server.MsgTypeScan:
-		if err := lib.Scan(); err != nil {
-			return server.Response{
-				Type: server.MsgTypeError, ID: req.ID,
-				Success: false, Error: err.Error(),
-			}
-		}
-		return server.Response{
-			Type: server.MsgTypeSuccess, ID: req.ID,
-			Success: true, Data: fmt.Sprintf("Found %d games", len(lib.Games)),
-		}
+	logger.Info("shutting down", "signal", sig.String())
+	notifier.Stopping()
+	stopWatchdog()
 
-	case server.MsgTypeStatus:
-		return server.Response{
-			Type: server.MsgTypeStatus, ID: req.ID,
-			Success: true, Data: map[string]interface{}{
-				"status":  "ready",
-				"version": "1.0.0",
-			},
-		}
+	/*   Drain handlers before saving so Save      */
+	/*   never races a request                     */
+	ctx, cancel := context.WithTimeout(context.Background(), server.DefaultShutdownTimeout)
+	if err := ipcServer.Shutdown(ctx); err != nil {
+		logger.Warn("shutdown incomplete", "error", err)
+	}
+	cancel()
+	if err := lib.Save(); err != nil {
+		logger.Error("failed to save library", "error", err)
+	}
+	logger.Info("goodbye")
+}
 
-	default:
-		return server.Response{
-			Type: server.MsgTypeError, ID: req.ID,
-			Success: false, Error: "Unknown message type",
-		}
+func fatal(logger *slog.Logger, msg string, args ...any) {
+	logger.Error(msg, args...)
+	status := msg
+	for i := 0; i+1 < len(args); i += 2 {
+		status += fmt.Sprintf(" %v=%v", args[i], args[i+1])
 	}
+	notifier.Status(status)
+	os.Exit(1)
+}
+
+/*   Before logging is set up; the status lets   */
+/*   a waiting --daemon parent show the reason   */
+func exitStartup(code int, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	fmt.Fprintln(os.Stderr, msg)
+	notifier.Status(msg)
+	os.Exit(code)
 }
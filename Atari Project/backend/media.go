@@ -0,0 +1,161 @@
+/**************************************/
+/*                                    */
+/*     Per-Game Media Discovery       */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package library
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*                 MEDIA TYPES                    */
+/*                                                */
+/**************************************************/
+
+type MediaKind string
+
+const (
+	MediaCover      MediaKind = "cover"
+	MediaScreenshot MediaKind = "screenshot"
+	MediaClip       MediaKind = "clip"
+	MediaTrack      MediaKind = "track"
+)
+
+type MediaItem struct {
+	GameID   string    `json:"game_id"`
+	Kind     MediaKind `json:"kind"`
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+}
+
+/*   Folders beside the ROM, by kind; a file    */
+/*   belongs to a game when its name starts     */
+/*   with the ROM's name                        */
+var MediaDirs = map[MediaKind][]string{
+	MediaScreenshot: {"screenshots", "snaps", "media/screenshots"},
+	MediaClip:       {"videos", "clips", "media/videos"},
+	MediaTrack:      {"music", "soundtrack", "soundtracks", "media/music"},
+}
+
+/*   media/<rom name>/ may hold any kind; the   */
+/*   extension decides                          */
+const gameMediaDir = "media"
+
+var mediaExtensions = map[string]MediaKind{
+	".png": MediaScreenshot, ".jpg": MediaScreenshot, ".jpeg": MediaScreenshot,
+	".mp4": MediaClip, ".mkv": MediaClip, ".webm": MediaClip, ".avi": MediaClip,
+	".ogg": MediaTrack, ".mp3": MediaTrack, ".wav": MediaTrack, ".flac": MediaTrack,
+	".qoa": MediaTrack, ".xm": MediaTrack, ".mod": MediaTrack,
+}
+
+/**************************************************/
+/*                                                */
+/*                 MEDIA LOOKUP                   */
+/*                                                */
+/**************************************************/
+
+/*   An empty id lists media for every game;    */
+/*   an empty kind lists every kind             */
+func (lib *Library) Media(id string, kind MediaKind) ([]MediaItem, error) {
+	switch kind {
+	case "", MediaCover, MediaScreenshot, MediaClip, MediaTrack:
+	default:
+		return nil, fmt.Errorf("unknown media kind %q", kind)
+	}
+
+	games := lib.GetGames("", "")
+	if id != "" {
+		game := lib.GetGameByID(id)
+		if game == nil {
+			return nil, ErrGameNotFound
+		}
+		games = []GameInfo{*game}
+	}
+
+	/*   Many games share a folder, so each media  */
+	/*   folder is listed once                     */
+	listings := make(map[string][]os.DirEntry)
+	list := func(dir string) []os.DirEntry {
+		entries, ok := listings[dir]
+		if !ok {
+			entries, _ = os.ReadDir(dir)
+			listings[dir] = entries
+		}
+		return entries
+	}
+
+	items := make([]MediaItem, 0)
+	add := func(game GameInfo, k MediaKind, path string) {
+		if kind != "" && k != kind {
+			return
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			return
+		}
+		items = append(items, MediaItem{GameID: game.ID, Kind: k, Path: path, Size: info.Size(), Modified: info.ModTime()})
+	}
+
+	for _, game := range games {
+		if game.CoverPath != "" {
+			add(game, MediaCover, game.CoverPath)
+		}
+
+		romDir := filepath.Dir(game.Path)
+		name := strings.TrimSuffix(filepath.Base(game.Path), filepath.Ext(game.Path))
+		for k, dirs := range MediaDirs {
+			for _, dir := range dirs {
+				dir = filepath.Join(romDir, filepath.FromSlash(dir))
+				for _, entry := range list(dir) {
+					if mediaExtensions[strings.ToLower(filepath.Ext(entry.Name()))] == k && mediaNameMatches(entry.Name(), name) {
+						add(game, k, filepath.Join(dir, entry.Name()))
+					}
+				}
+			}
+		}
+
+		own := filepath.Join(romDir, gameMediaDir, name)
+		for _, entry := range list(own) {
+			if k, ok := mediaExtensions[strings.ToLower(filepath.Ext(entry.Name()))]; ok {
+				add(game, k, filepath.Join(own, entry.Name()))
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].GameID != items[j].GameID {
+			return items[i].GameID < items[j].GameID
+		}
+		if items[i].Kind != items[j].Kind {
+			return items[i].Kind < items[j].Kind
+		}
+		return items[i].Path < items[j].Path
+	})
+	return items, nil
+}
+
+/*   "Metroid - 01.png" and "Metroid (Map).png" */
+/*   belong to "Metroid", "Metroid II.png" does  */
+/*   not                                         */
+func mediaNameMatches(file, rom string) bool {
+	base := strings.TrimSuffix(file, filepath.Ext(file))
+	if len(base) < len(rom) || !strings.EqualFold(base[:len(rom)], rom) {
+		return false
+	}
+	rest := strings.TrimLeft(base[len(rom):], " ")
+	return rest == "" || strings.ContainsRune("-_.([", rune(rest[0]))
+}
@@ -0,0 +1,178 @@
+/**************************************/
+/*                                    */
+/*   IPC Handler Middleware - Go      */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*            HANDLER / MIDDLEWARE TYPES          */
+/*                                                */
+/**************************************************/
+
+type Handler func(ctx context.Context, req Request) Response
+
+/*   Middlewares wrap a handler; the first one   */
+/*   passed to Use is the outermost              */
+type Middleware func(next Handler) Handler
+
+func (s *IPCServer) Use(middlewares ...Middleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.middlewares = append(s.middlewares, middlewares...)
+}
+
+func chain(h Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+/**************************************************/
+/*                                                */
+/*              REQUEST CONTEXT VALUES            */
+/*                                                */
+/**************************************************/
+
+type contextKey int
+
+const (
+	clientKey contextKey = iota
+	requestIDKey
+)
+
+type ClientInfo struct {
+	Name       string
+	RemoteAddr string
+	Scopes     []Scope
+}
+
+func withClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientKey, ClientInfo{
+		Name:       client.name,
+		RemoteAddr: client.conn.RemoteAddr().String(),
+		Scopes:     client.scopes,
+	})
+}
+
+func ClientFromContext(ctx context.Context) (ClientInfo, bool) {
+	info, ok := ctx.Value(clientKey).(ClientInfo)
+	return info, ok
+}
+
+/*   False before auth, when a client holds no  */
+/*   scopes at all                              */
+func (c ClientInfo) HasScope(required Scope) bool {
+	return hasScope(c.Scopes, required)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+/**************************************************/
+/*                                                */
+/*               PANIC RECOVERY                   */
+/*                                                */
+/**************************************************/
+
+func Recover(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req Request) (resp Response) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error("handler panic",
+						"type", req.Type, "id", req.ID,
+						"panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+					resp = Fail(req, ErrCodeInternal, "Internal error while handling "+req.Type)
+				}
+			}()
+			return next(ctx, req)
+		}
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*              REQUEST ID INJECTION              */
+/*                                                */
+/**************************************************/
+
+var requestCounter atomic.Uint64
+
+/*   Random per-process prefix keeps IDs unique  */
+/*   across backend restarts                     */
+var requestPrefix = func() string {
+	buf := make([]byte, 4)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}()
+
+func RequestID() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req Request) Response {
+			if req.ID == "" {
+				req.ID = fmt.Sprintf("%s-%d", requestPrefix, requestCounter.Add(1))
+			}
+			ctx = context.WithValue(ctx, requestIDKey, req.ID)
+
+			resp := next(ctx, req)
+			if resp.ID == "" {
+				resp.ID = req.ID
+			}
+			return resp
+		}
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*               REQUEST LOGGING                  */
+/*                                                */
+/**************************************************/
+
+func Logging(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req Request) Response {
+			start := time.Now()
+			resp := next(ctx, req)
+
+			attrs := []any{
+				"type", req.Type,
+				"id", resp.ID,
+				"success", resp.Success,
+				"latency", time.Since(start),
+			}
+			if client, ok := ClientFromContext(ctx); ok {
+				attrs = append(attrs, "client", client.Name, "remote", client.RemoteAddr)
+			}
+
+			if resp.Success {
+				logger.Info("request", attrs...)
+			} else {
+				attrs = append(attrs, "code", resp.Code, "error", resp.Error)
+				logger.Warn("request failed", attrs...)
+			}
+			return resp
+		}
+	}
+}
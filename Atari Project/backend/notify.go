@@ -0,0 +1,236 @@
+/**************************************/
+/*                                    */
+/*    Service Manager Notification    */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package instance
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*                  NOTIFIER                      */
+/*   sd_notify: newline separated KEY=VALUE       */
+/*   datagrams on a unix socket                   */
+/*                                                */
+/**************************************************/
+
+type Notifier struct {
+	sockets []string
+
+	/*   The daemon parent's socket; only wanted    */
+	/*   until READY, so guarded by mu and cleared  */
+	/*   once that is sent                          */
+	mu     sync.Mutex
+	daemon string
+}
+
+/*   Empty paths are skipped, so a Notifier with */
+/*   nowhere to send is a harmless no-op         */
+func NewNotifier(sockets ...string) *Notifier {
+	n := &Notifier{}
+	for _, socket := range sockets {
+		if socket != "" {
+			n.sockets = append(n.sockets, socket)
+		}
+	}
+	return n
+}
+
+/*   systemd's NOTIFY_SOCKET plus the daemon     */
+/*   parent's socket when running detached       */
+func NotifierFromEnv() *Notifier {
+	n := NewNotifier(os.Getenv("NOTIFY_SOCKET"))
+	n.daemon = os.Getenv(DaemonEnv)
+	return n
+}
+
+func (n *Notifier) Enabled() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.sockets) > 0 || n.daemon != ""
+}
+
+/*   Sends to every socket; returns the first    */
+/*   error but still tries the rest              */
+func (n *Notifier) Notify(state ...string) error {
+	sockets := n.targets(state)
+	if len(sockets) == 0 {
+		return nil
+	}
+	msg := []byte(strings.Join(state, "\n"))
+
+	var firstErr error
+	for _, socket := range sockets {
+		/*   Go maps a leading "@" to the abstract   */
+		/*   namespace, as systemd expects           */
+		conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+		if err == nil {
+			_, err = conn.Write(msg)
+			conn.Close()
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("notify %s: %w", socket, err)
+		}
+	}
+	return firstErr
+}
+
+/*   The parent stops listening after READY and  */
+/*   removes its socket, and processes we launch */
+/*   must not take themselves for daemon         */
+/*   children, so the handshake socket and its   */
+/*   variable go with the first READY            */
+func (n *Notifier) targets(state []string) []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.daemon == "" {
+		return n.sockets
+	}
+	sockets := append(append([]string{}, n.sockets...), n.daemon)
+	for _, line := range state {
+		if line == "READY=1" {
+			n.daemon = ""
+			os.Unsetenv(DaemonEnv)
+			break
+		}
+	}
+	return sockets
+}
+
+func (n *Notifier) Ready() error {
+	return n.Notify("READY=1", "MAINPID="+strconv.Itoa(os.Getpid()))
+}
+
+func (n *Notifier) Reloading() error {
+	return n.Notify("RELOADING=1")
+}
+
+func (n *Notifier) Stopping() error {
+	return n.Notify("STOPPING=1")
+}
+
+func (n *Notifier) Status(status string) error {
+	return n.Notify("STATUS=" + status)
+}
+
+/**************************************************/
+/*                                                */
+/*                  WATCHDOG                      */
+/*                                                */
+/**************************************************/
+
+/*   From WATCHDOG_USEC, if it is meant for this */
+/*   process (WATCHDOG_PID unset or ours)        */
+func WatchdogInterval() (time.Duration, bool) {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false
+	}
+	return time.Duration(usec) * time.Microsecond, true
+}
+
+/*   Pings at half the interval while healthy    */
+/*   reports no error; a stuck or unhealthy      */
+/*   backend stops pinging and gets restarted    */
+func (n *Notifier) RunWatchdog(ctx context.Context, interval time.Duration, healthy func() error) {
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if healthy != nil && healthy() != nil {
+				continue
+			}
+			n.Notify("WATCHDOG=1")
+		}
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*               NOTIFY LISTENER                  */
+/*   The receiving end: used by daemon mode and   */
+/*   as a stand-in for systemd when testing       */
+/*                                                */
+/**************************************************/
+
+type NotifyMessage map[string]string
+
+type NotifyListener struct {
+	conn *net.UnixConn
+	path string
+}
+
+func ListenNotify(path string) (*NotifyListener, error) {
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &NotifyListener{conn: conn, path: path}, nil
+}
+
+func (l *NotifyListener) Path() string {
+	return l.path
+}
+
+func (l *NotifyListener) Receive() (NotifyMessage, error) {
+	buf := make([]byte, 4096)
+	n, err := l.conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return ParseNotify(buf[:n]), nil
+}
+
+/*   Delivers messages until the listener is     */
+/*   closed                                      */
+func (l *NotifyListener) Messages() <-chan NotifyMessage {
+	out := make(chan NotifyMessage, 16)
+	go func() {
+		defer close(out)
+		for {
+			msg, err := l.Receive()
+			if err != nil {
+				return
+			}
+			out <- msg
+		}
+	}()
+	return out
+}
+
+func (l *NotifyListener) Close() error {
+	err := l.conn.Close()
+	os.Remove(l.path)
+	return err
+}
+
+func ParseNotify(data []byte) NotifyMessage {
+	msg := make(NotifyMessage)
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			msg[key] = value
+		}
+	}
+	return msg
+}
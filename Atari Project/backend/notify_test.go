@@ -0,0 +1,204 @@
+//go:build unix
+
+/**************************************/
+/*                                    */
+/*       Notify Handshake Tests       */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package instance
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+/*   Tells the re-executed test binary which     */
+/*   daemon child to play                        */
+const testChildEnv = "RETROHUB_TEST_DAEMON_CHILD"
+
+/*   A stand-in for systemd's notify socket      */
+func listenTestNotify(t *testing.T) *NotifyListener {
+	t.Helper()
+	listener, err := ListenNotify(filepath.Join(t.TempDir(), "notify.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return listener
+}
+
+func receive(t *testing.T, listener *NotifyListener) NotifyMessage {
+	t.Helper()
+	listener.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	msg, err := listener.Receive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+/**************************************************/
+/*                                                */
+/*                  NOTIFIER                      */
+/*                                                */
+/**************************************************/
+
+func TestNotifierReady(t *testing.T) {
+	listener := listenTestNotify(t)
+	if err := NewNotifier(listener.Path()).Ready(); err != nil {
+		t.Fatal(err)
+	}
+	msg := receive(t, listener)
+	if msg["READY"] != "1" {
+		t.Errorf("READY = %q, want 1", msg["READY"])
+	}
+	if msg["MAINPID"] != strconv.Itoa(os.Getpid()) {
+		t.Errorf("MAINPID = %q, want %d", msg["MAINPID"], os.Getpid())
+	}
+}
+
+func TestNotifierStopping(t *testing.T) {
+	listener := listenTestNotify(t)
+	notifier := NewNotifier(listener.Path())
+	notifier.Status("shutting down")
+	notifier.Stopping()
+
+	if msg := receive(t, listener); msg["STATUS"] != "shutting down" {
+		t.Errorf("STATUS = %q, want %q", msg["STATUS"], "shutting down")
+	}
+	if msg := receive(t, listener); msg["STOPPING"] != "1" {
+		t.Errorf("STOPPING = %q, want 1", msg["STOPPING"])
+	}
+}
+
+func TestNotifierWithoutSocketsIsNoop(t *testing.T) {
+	notifier := NewNotifier("", "")
+	if notifier.Enabled() {
+		t.Error("Enabled with no sockets")
+	}
+	if err := notifier.Ready(); err != nil {
+		t.Errorf("Ready = %v, want nil", err)
+	}
+}
+
+/*   The daemon socket is dropped with READY,    */
+/*   systemd's is kept                           */
+func TestNotifierForgetsDaemonAfterReady(t *testing.T) {
+	systemd := listenTestNotify(t)
+	parent := listenTestNotify(t)
+	t.Setenv("NOTIFY_SOCKET", systemd.Path())
+	t.Setenv(DaemonEnv, parent.Path())
+
+	notifier := NotifierFromEnv()
+	notifier.Status("loading")
+	if msg := receive(t, parent); msg["STATUS"] != "loading" {
+		t.Errorf("parent STATUS = %q, want loading", msg["STATUS"])
+	}
+	if !IsDaemonChild() {
+		t.Error("daemon variable unset before READY")
+	}
+
+	notifier.Ready()
+	if msg := receive(t, parent); msg["READY"] != "1" {
+		t.Errorf("parent READY = %q, want 1", msg["READY"])
+	}
+	if IsDaemonChild() {
+		t.Errorf("%s still set after READY", DaemonEnv)
+	}
+
+	/*   The parent has gone; sending there now     */
+	/*   would fail                                 */
+	parent.Close()
+	if err := notifier.Stopping(); err != nil {
+		t.Errorf("Stopping after READY = %v, want nil", err)
+	}
+	receive(t, systemd)
+	receive(t, systemd)
+	if msg := receive(t, systemd); msg["STOPPING"] != "1" {
+		t.Errorf("systemd STOPPING = %q, want 1", msg["STOPPING"])
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*               DAEMON HANDSHAKE                 */
+/*   Daemonize re-executes the test binary, which */
+/*   runs only TestDaemonChild                    */
+/*                                                */
+/**************************************************/
+
+func TestDaemonChild(t *testing.T) {
+	role := os.Getenv(testChildEnv)
+	if role == "" {
+		t.Skip("only run as a daemon child")
+	}
+	notifier := NotifierFromEnv()
+	switch role {
+	case "ready":
+		notifier.Status("warming up")
+		notifier.Ready()
+	case "fail":
+		notifier.Status("bad config")
+		os.Exit(2)
+	case "hang":
+		os.WriteFile(os.Getenv(testChildEnv+"_PID"), []byte(strconv.Itoa(os.Getpid())), 0644)
+		time.Sleep(time.Minute)
+	}
+	os.Exit(0)
+}
+
+func daemonizeTestChild(t *testing.T, role string, timeout time.Duration) (int, error) {
+	t.Helper()
+	t.Setenv(testChildEnv, role)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return Daemonize(ctx, []string{"-test.run=^TestDaemonChild$"})
+}
+
+func TestDaemonizeReady(t *testing.T) {
+	pid, err := daemonizeTestChild(t, "ready", 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pid <= 0 || pid == os.Getpid() {
+		t.Errorf("pid = %d, want the child's", pid)
+	}
+}
+
+func TestDaemonizeReportsStartupFailure(t *testing.T) {
+	_, err := daemonizeTestChild(t, "fail", 10*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "bad config") {
+		t.Errorf("err = %v, want the child's last status", err)
+	}
+}
+
+func TestDaemonizeKillsChildOnTimeout(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	t.Setenv(testChildEnv+"_PID", pidFile)
+
+	_, err := daemonizeTestChild(t, "hang", 2*time.Second)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	data, readErr := os.ReadFile(pidFile)
+	if readErr != nil {
+		t.Fatalf("child never started: %v", readErr)
+	}
+	pid, _ := strconv.Atoi(string(data))
+	if err := syscall.Kill(pid, 0); !errors.Is(err, syscall.ESRCH) {
+		t.Errorf("child %d still running after timeout (kill 0: %v)", pid, err)
+	}
+}
@@ -0,0 +1,83 @@
+/**************************************/
+/*                                    */
+/*    Backend Metrics and Health      */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"retro-gaming-ui/backend/library"
+	"retro-gaming-ui/backend/metrics"
+	"retro-gaming-ui/backend/server"
+)
+
+/**************************************************/
+/*                                                */
+/*              BACKEND METRICS                   */
+/*                                                */
+/**************************************************/
+
+var scanBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300}
+
+type hubMetrics struct {
+	registry     *metrics.Registry
+	scanDuration *metrics.HistogramVec
+	launches     *metrics.CounterVec
+	crashes      *metrics.CounterVec
+}
+
+func newHubMetrics(lib *library.Library) *hubMetrics {
+	reg := metrics.NewRegistry()
+
+	reg.NewGaugeVecFunc("retrohub_library_games",
+		"Games in the library per platform.", "platform", func() map[string]float64 {
+			out := make(map[string]float64)
+			for platform, count := range lib.PlatformCounts() {
+				out[platform] = float64(count)
+			}
+			return out
+		})
+
+	return &hubMetrics{
+		registry: reg,
+		scanDuration: reg.NewHistogramVec("retrohub_scan_duration_seconds",
+			"Duration of library scans by result.", scanBuckets, "result"),
+		launches: reg.NewCounterVec("retrohub_launches_total",
+			"Game launches by platform.", "platform"),
+		crashes: reg.NewCounterVec("retrohub_emulator_crashes_total",
+			"Emulator processes that exited abnormally, by platform.", "platform"),
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*          METRICS / HEALTH HTTP ENDPOINT        */
+/*                                                */
+/**************************************************/
+
+func startMetricsEndpoint(addr string, m *hubMetrics, lib *library.Library, ipc *server.IPCServer) (*http.Server, error) {
+	health := metrics.NewHealth()
+	health.AddLiveness("ipc", func() error {
+		if !ipc.IsRunning() {
+			return errors.New("IPC server not running")
+		}
+		return nil
+	})
+	health.AddReadiness("ipc", func() error {
+		if !ipc.IsRunning() {
+			return errors.New("IPC server not running")
+		}
+		return nil
+	})
+	health.AddReadiness("library", lib.LoadState)
+
+	return metrics.Serve(addr, m.registry, health)
+}
@@ -0,0 +1,104 @@
+/**************************************/
+/*                                    */
+/*   Per-Game Launch Profiles & Play  */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package library
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*               LAUNCH PROFILE                   */
+/*   Every field is optional; an empty one falls  */
+/*   back to the platform's emulator config       */
+/*                                                */
+/**************************************************/
+
+type LaunchProfile struct {
+	Emulator   string            `json:"emulator,omitempty"` /*   command to run    */
+	Core       string            `json:"core,omitempty"`     /*   fills {core}      */
+	Args       []string          `json:"args,omitempty"`
+	Env        map[string]string `json:"env,omitempty"` /*   merged over the platform's */
+	WorkingDir string            `json:"working_dir,omitempty"`
+	PreLaunch  string            `json:"pre_launch,omitempty"` /*   hook scripts      */
+	PostExit   string            `json:"post_exit,omitempty"`
+}
+
+func (p LaunchProfile) IsEmpty() bool {
+	return p.Emulator == "" && p.Core == "" && len(p.Args) == 0 && len(p.Env) == 0 &&
+		p.WorkingDir == "" && p.PreLaunch == "" && p.PostExit == ""
+}
+
+func (p LaunchProfile) Validate() error {
+	for key := range p.Env {
+		if key == "" || strings.ContainsAny(key, "=\x00") {
+			return fmt.Errorf("invalid environment variable name %q", key)
+		}
+	}
+	return nil
+}
+
+/*   An empty profile clears the override        */
+func (lib *Library) SetLaunchProfile(id string, profile LaunchProfile) (*GameInfo, error) {
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	if profile.IsEmpty() {
+		return lib.ClearLaunchProfile(id)
+	}
+	return lib.updateGame(id, func(game *GameInfo) {
+		game.Launch = &profile
+	})
+}
+
+func (lib *Library) ClearLaunchProfile(id string) (*GameInfo, error) {
+	return lib.updateGame(id, func(game *GameInfo) {
+		game.Launch = nil
+	})
+}
+
+/**************************************************/
+/*                                                */
+/*                PLAY RECORDS                    */
+/*   A launch counts as a play; the time is       */
+/*   added once the emulator exits                */
+/*                                                */
+/**************************************************/
+
+func (lib *Library) MarkPlayed(id string, at time.Time) (*GameInfo, error) {
+	return lib.updateGame(id, func(game *GameInfo) {
+		game.PlayCount++
+		game.LastPlayed = at
+	})
+}
+
+func (lib *Library) AddPlayTime(id string, played time.Duration) (*GameInfo, error) {
+	return lib.updateGame(id, func(game *GameInfo) {
+		game.PlaySeconds += int64(played.Round(time.Second) / time.Second)
+	})
+}
+
+/*   Applies change, saves, and returns a copy   */
+func (lib *Library) updateGame(id string, change func(*GameInfo)) (*GameInfo, error) {
+	lib.mu.Lock()
+	defer lib.mu.Unlock()
+
+	for i := range lib.Games {
+		if lib.Games[i].ID == id {
+			change(&lib.Games[i])
+			game := lib.Games[i]
+			return &game, lib.saveUnlocked()
+		}
+	}
+	return nil, ErrGameNotFound
+}
@@ -0,0 +1,336 @@
+/**************************************/
+/*                                    */
+/*   IPC Protocol Versioning - Go     */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/**************************************************/
+/*                                                */
+/*              PROTOCOL VERSION                  */
+/*    Bump minor for additions, major for breaks  */
+/*                                                */
+/**************************************************/
+
+const ProtocolVersion = "1.10.0"
+
+type HelloPayload struct {
+	ProtocolVersion string `json:"protocol_version"`
+	Client          string `json:"client,omitempty"`
+	Token           string `json:"token,omitempty"`
+}
+
+type Capabilities struct {
+	ProtocolVersion string          `json:"protocol_version"`
+	MessageTypes    []MessageSpec   `json:"message_types"`
+	Features        map[string]bool `json:"features"`
+}
+
+/**************************************************/
+/*                                                */
+/*             MESSAGE SPECIFICATIONS             */
+/*                                                */
+/**************************************************/
+
+/*   Payload is either a JSON type name for bare */
+/*   payloads or a map of field name to type.    */
+/*   A trailing "?" marks optional fields.       */
+type MessageSpec struct {
+	Type        string      `json:"type"`
+	Scope       Scope       `json:"scope"`
+	Payload     interface{} `json:"payload,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+var messageSpecs = map[string]MessageSpec{
+	MsgTypeHello: {
+		Type: MsgTypeHello, Scope: ScopeRead,
+		Payload:     map[string]string{"protocol_version": "string", "client": "string?", "token": "string?"},
+		Description: "Negotiate protocol version and list capabilities",
+	},
+	MsgTypeAuth: {
+		Type: MsgTypeAuth, Scope: ScopeRead,
+		Payload:     map[string]string{"token": "string"},
+		Description: "Authenticate with a token from the secrets file",
+	},
+	MsgTypeStatus: {
+		Type: MsgTypeStatus, Scope: ScopeRead,
+		Description: "Backend status and version; with read scope also connected clients, the running game and the last scan result",
+	},
+	MsgTypeListGames: {
+		Type: MsgTypeListGames, Scope: ScopeRead,
+		Payload:     map[string]string{"platform": "string?", "category": "string?", "query": "string?", "limit": "int?"},
+		Description: "List games, optionally filtered; query matches titles",
+	},
+	MsgTypeGetGame: {
+		Type: MsgTypeGetGame, Scope: ScopeRead,
+		Payload: "string", Description: "Get a game by ID",
+	},
+	MsgTypeGetCategories: {
+		Type: MsgTypeGetCategories, Scope: ScopeRead,
+		Description: "Game counts per category",
+	},
+	MsgTypeGetPlatforms: {
+		Type: MsgTypeGetPlatforms, Scope: ScopeRead,
+		Description: "Game counts per platform",
+	},
+	MsgTypeGetFavorites: {
+		Type: MsgTypeGetFavorites, Scope: ScopeRead,
+		Description: "List favorite games",
+	},
+	MsgTypeGetRecent: {
+		Type: MsgTypeGetRecent, Scope: ScopeRead,
+		Payload: "int?", Description: "Recently played games, newest first",
+	},
+	MsgTypeLaunchGame: {
+		Type: MsgTypeLaunchGame, Scope: ScopeLaunch,
+		Payload:     "string",
+		Description: "Launch a game by ID with its profile or the platform emulator; game.exited follows. Fails with firmware_missing when required firmware is absent",
+	},
+	MsgTypeSetProfile: {
+		Type: MsgTypeSetProfile, Scope: ScopeAdmin,
+		Payload: map[string]string{
+			"game_id": "string", "emulator": "string?", "core": "string?", "args": "[]string?",
+			"env": "object?", "working_dir": "string?", "pre_launch": "string?", "post_exit": "string?",
+		},
+		Description: "Set a game's launch profile; empty fields fall back to the platform emulator",
+	},
+	MsgTypeClearProfile: {
+		Type: MsgTypeClearProfile, Scope: ScopeAdmin,
+		Payload: "string", Description: "Remove a game's launch profile",
+	},
+	MsgTypeGetFirmware: {
+		Type: MsgTypeGetFirmware, Scope: ScopeRead,
+		Payload:     map[string]string{"platform": "string?"},
+		Description: "Known BIOS and firmware files with their status in the configured firmware dirs",
+	},
+	MsgTypeGetMedia: {
+		Type: MsgTypeGetMedia, Scope: ScopeRead,
+		Payload:     map[string]string{"game_id": "string?", "kind": "string?"},
+		Description: "Covers, screenshots, clips and soundtrack files found beside each game's ROM",
+	},
+	MsgTypeToggleFavorite: {
+		Type: MsgTypeToggleFavorite, Scope: ScopePlayer,
+		Payload: "string", Description: "Toggle a game's favorite flag",
+	},
+	MsgTypeScan: {
+		Type: MsgTypeScan, Scope: ScopeAdmin,
+		Description: "Rescan all scan paths; progress is published on scan.progress, the result on library.changed",
+	},
+	MsgTypeCancelScan: {
+		Type: MsgTypeCancelScan, Scope: ScopeAdmin,
+		Description: "Stop the running scan; the scan request then fails with canceled and the library is unchanged",
+	},
+	MsgTypeSubscribe: {
+		Type: MsgTypeSubscribe, Scope: ScopeRead,
+		Payload:     map[string]string{"topics": "[]string?"},
+		Description: "Receive event frames for the given topics (default all); logs topics need admin",
+	},
+	MsgTypeUnsubscribe: {
+		Type: MsgTypeUnsubscribe, Scope: ScopeRead,
+		Payload:     map[string]string{"topics": "[]string"},
+		Description: "Stop receiving event frames for the given topics",
+	},
+	MsgTypeGetLogs: {
+		Type: MsgTypeGetLogs, Scope: ScopeAdmin,
+		Payload:     map[string]string{"limit": "int?", "level": "string?"},
+		Description: "Recent backend log records, oldest first; subscribe to logs for new ones",
+	},
+	MsgTypeReloadConfig: {
+		Type: MsgTypeReloadConfig, Scope: ScopeAdmin,
+		Description: "Re-read the config file and apply hot-reloadable keys",
+	},
+	MsgTypeAddScanPath: {
+		Type: MsgTypeAddScanPath, Scope: ScopeAdmin,
+		Payload: map[string]string{
+			"path": "string", "max_depth": "int?", "follow_symlinks": "bool?",
+			"include": "[]string?", "exclude": "[]string?", "no_default_excludes": "bool?",
+			"platform": "string?", "category": "string?",
+		},
+		Description: "Add a scan path, or replace the options of one already added",
+	},
+	MsgTypeRemoveScanPath: {
+		Type: MsgTypeRemoveScanPath, Scope: ScopeAdmin,
+		Payload:     map[string]string{"path": "string"},
+		Description: "Remove a directory from the scan paths",
+	},
+	MsgTypeListScanPaths: {
+		Type: MsgTypeListScanPaths, Scope: ScopeRead,
+		Description: "Scan paths with their options, status (ok, missing, unreadable, not_a_directory) and game count",
+	},
+	MsgTypeGetStats: {
+		Type: MsgTypeGetStats, Scope: ScopeRead,
+		Description: "Library totals, per-platform counts and most played games",
+	},
+	MsgTypeVerifyLibrary: {
+		Type: MsgTypeVerifyLibrary, Scope: ScopeAdmin,
+		Description: "Check every game against the filesystem and list problems",
+	},
+	MsgTypeAnalyzeLibrary: {
+		Type: MsgTypeAnalyzeLibrary, Scope: ScopeAdmin,
+		Payload:     map[string]string{"prefer_regions": "[]string?"},
+		Description: "Group duplicates and alternates, check headers, sizes and checksums, and suggest what to keep",
+	},
+	MsgTypeExportLibrary: {
+		Type: MsgTypeExportLibrary, Scope: ScopeAdmin,
+		Payload: map[string]string{
+			"format": "string?", "platform": "string?", "favorites": "bool?", "base_dir": "string?",
+		},
+		Description: "Export as json, csv, gamelist or lpl content; no format returns the JSON document",
+	},
+	MsgTypeImportLibrary: {
+		Type: MsgTypeImportLibrary, Scope: ScopeAdmin,
+		Payload: map[string]string{
+			"format": "string", "content": "string", "base_dir": "string?", "prefer": "string?", "launch": "bool?",
+		},
+		Description: "Merge json, csv, gamelist or lpl content by content hash and report conflicts; launch profiles only with launch",
+	},
+}
+
+/*     Types every backend answers, whatever     */
+/*     the installed handler supports            */
+var builtinMessages = []string{
+	MsgTypeHello, MsgTypeAuth, MsgTypeStatus, MsgTypeSubscribe, MsgTypeUnsubscribe,
+}
+
+func defaultMessageSpecs() map[string]MessageSpec {
+	specs := make(map[string]MessageSpec, len(messageSpecs))
+	for t, spec := range messageSpecs {
+		specs[t] = spec
+	}
+	return specs
+}
+
+/**************************************************/
+/*                                                */
+/*          SUPPORTED TYPES / FEATURES            */
+/*                                                */
+/**************************************************/
+
+func (s *IPCServer) Support(msgTypes ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range msgTypes {
+		s.supported[t] = true
+	}
+}
+
+func (s *IPCServer) supports(msgType string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.supported[msgType]
+}
+
+/*   Registers a message type that has no       */
+/*   built-in spec                               */
+func (s *IPCServer) RegisterMessage(spec MessageSpec) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.specs[spec.Type] = spec
+	s.supported[spec.Type] = true
+}
+
+func (s *IPCServer) SetFeature(name string, enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.features[name] = enabled
+}
+
+func (s *IPCServer) Capabilities() Capabilities {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	specs := make([]MessageSpec, 0, len(s.supported))
+	for t := range s.supported {
+		if spec, ok := s.specs[t]; ok {
+			specs = append(specs, spec)
+		} else {
+			specs = append(specs, MessageSpec{Type: t, Scope: ScopeAdmin})
+		}
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Type < specs[j].Type })
+
+	features := map[string]bool{"auth": s.auth != nil, "events": true}
+	for name, enabled := range s.features {
+		features[name] = enabled
+	}
+
+	return Capabilities{
+		ProtocolVersion: ProtocolVersion,
+		MessageTypes:    specs,
+		Features:        features,
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                HELLO HANDSHAKE                 */
+/*                                                */
+/**************************************************/
+
+/*   Returns false when the connection should    */
+/*   be closed after sending the response        */
+func (s *IPCServer) hello(client *Client, req Request) (Response, bool) {
+	var payload HelloPayload
+	if req.Payload != nil {
+		if err := json.Unmarshal(req.Payload, &payload); err != nil {
+			return Response{
+				Type: MsgTypeError, ID: req.ID, Success: false,
+				Error: fmt.Sprintf("Invalid hello payload: %v", err), Code: ErrCodeBadRequest,
+			}, true
+		}
+	}
+
+	if err := CheckCompatible(payload.ProtocolVersion); err != nil {
+		return Response{
+			Type: MsgTypeError, ID: req.ID, Success: false,
+			Error: err.Error(), Code: ErrCodeIncompatibleVersion,
+			Data: map[string]string{"protocol_version": ProtocolVersion},
+		}, false
+	}
+	client.protocol = payload.ProtocolVersion
+
+	if payload.Token != "" {
+		if resp := s.authenticate(client, req.ID, payload.Token); !resp.Success {
+			return resp, true
+		}
+	}
+
+	return Response{
+		Type: MsgTypeSuccess, ID: req.ID, Success: true,
+		Data: s.Capabilities(),
+	}, true
+}
+
+func CheckCompatible(clientVersion string) error {
+	if clientVersion == "" {
+		return fmt.Errorf("Missing protocol_version: server speaks %s", ProtocolVersion)
+	}
+	clientMajor, err := majorVersion(clientVersion)
+	if err != nil {
+		return fmt.Errorf("Invalid protocol_version %q: %v", clientVersion, err)
+	}
+	serverMajor, _ := majorVersion(ProtocolVersion)
+	if clientMajor != serverMajor {
+		return fmt.Errorf("Incompatible protocol version %s: server speaks %s", clientVersion, ProtocolVersion)
+	}
+	return nil
+}
+
+func majorVersion(version string) (int, error) {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	return strconv.Atoi(major)
+}
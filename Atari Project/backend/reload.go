@@ -0,0 +1,128 @@
+/**************************************/
+/*                                    */
+/*    Backend Config Hot Reload       */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package main
+
+import (
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"retro-gaming-ui/backend/config"
+	"retro-gaming-ui/backend/instance"
+	"retro-gaming-ui/backend/library"
+	"retro-gaming-ui/backend/logging"
+	"retro-gaming-ui/backend/server"
+)
+
+/**************************************************/
+/*                                                */
+/*               RELOADER STRUCT                  */
+/*   Owns the live config; SIGHUP and the         */
+/*   reload_config message both go through here   */
+/*                                                */
+/**************************************************/
+
+type reloader struct {
+	mu     sync.Mutex
+	loader *config.Loader
+	cfg    *config.Config
+	logs   *logging.Logging
+	lib    *library.Library
+	ipc    *server.IPCServer
+	notify *instance.Notifier
+	logger *slog.Logger
+}
+
+func (r *reloader) Current() *config.Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cfg
+}
+
+func (r *reloader) Reload() (config.ReloadPlan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notify.Reloading()
+	defer r.notify.Ready()
+
+	next, err := r.loader.Load()
+	if err != nil {
+		r.logger.Error("config reload failed", "error", err)
+		return config.ReloadPlan{}, err
+	}
+
+	plan := config.Diff(r.cfg, next)
+	applyLiveConfig(r.cfg, next, r.logs, r.lib, r.ipc, r.logger)
+	r.cfg = next
+
+	r.logger.Info("config reloaded", "file", next.File,
+		"applied", plan.Applied, "restart_required", plan.RestartRequired)
+	return plan, nil
+}
+
+/**************************************************/
+/*                                                */
+/*            APPLY HOT-RELOADABLE KEYS           */
+/*                                                */
+/**************************************************/
+
+/*   prev is nil at startup, when there is     */
+/*   nothing to take away                      */
+func applyLiveConfig(prev, cfg *config.Config, logs *logging.Logging, lib *library.Library, ipc *server.IPCServer, logger *slog.Logger) {
+	if level, err := logging.ParseLevel(cfg.Log.Level); err == nil {
+		logs.Level.Set(level)
+	}
+
+	ipc.SetLimits(toServerLimits(cfg.IPC.Limits))
+
+	/*   A bare path leaves options set over IPC   */
+	for _, sp := range cfg.Library.ScanPaths {
+		err := lib.AddScanPath(sp.Path)
+		if sp.HasOptions() {
+			err = lib.SetScanPath(sp)
+		}
+		if err != nil {
+			logger.Warn("ignoring configured scan path", "path", sp.Path, "error", err)
+		}
+	}
+
+	/*   Paths dropped from the file go too; ones   */
+	/*   added over IPC were never in it and stay   */
+	if prev == nil {
+		return
+	}
+	kept := make(map[string]bool, len(cfg.Library.ScanPaths))
+	for _, sp := range cfg.Library.ScanPaths {
+		kept[filepath.Clean(sp.Path)] = true
+	}
+	for _, sp := range prev.Library.ScanPaths {
+		if kept[filepath.Clean(sp.Path)] {
+			continue
+		}
+		if err := lib.RemoveScanPath(sp.Path); err != nil {
+			logger.Warn("configured scan path already gone", "path", sp.Path, "error", err)
+		}
+	}
+}
+
+func toServerLimits(l config.LimitsConfig) server.Limits {
+	return server.Limits{
+		MaxMessageSize: l.MaxMessageSize,
+		IdleTimeout:    time.Duration(l.IdleTimeout),
+		ReadTimeout:    time.Duration(l.ReadTimeout),
+		WriteTimeout:   time.Duration(l.WriteTimeout),
+		MaxConnections: l.MaxConnections,
+		RateLimit:      l.RateLimit,
+		RateBurst:      l.RateBurst,
+	}
+}
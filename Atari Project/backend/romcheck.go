@@ -0,0 +1,334 @@
+/**************************************/
+/*                                    */
+/*      ROM Header and Size Checks    */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package library
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+/**************************************************/
+/*                                                */
+/*                PROBLEM KINDS                   */
+/*                                                */
+/**************************************************/
+
+const (
+	ProblemUnreadable       = "unreadable"
+	ProblemBadHeader        = "bad_header"
+	ProblemBadSize          = "bad_size"
+	ProblemChecksumMismatch = "checksum_mismatch"
+)
+
+type romFinding struct {
+	Kind   string
+	Detail string
+}
+
+/*   Runs the checks for platform. data may be   */
+/*   only the start of a large file; checks      */
+/*   that need every byte skip themselves then   */
+func checkROM(platform, path string, data []byte, size int64) []romFinding {
+	if size == 0 {
+		return []romFinding{{ProblemBadSize, "file is empty"}}
+	}
+	c := &romCheck{data: data, size: size, full: int64(len(data)) == size}
+	switch platform {
+	case "NES":
+		if strings.EqualFold(filepath.Ext(path), ".nes") {
+			c.nes()
+		}
+	case "SNES":
+		c.snes()
+	case "N64":
+		c.n64()
+	case "GBA":
+		c.gba()
+	case "GB":
+		c.gb()
+	case "ATARI":
+		c.atari()
+	}
+	return c.findings
+}
+
+type romCheck struct {
+	data     []byte
+	size     int64
+	full     bool
+	findings []romFinding
+}
+
+func (c *romCheck) report(kind, format string, args ...interface{}) {
+	c.findings = append(c.findings, romFinding{kind, fmt.Sprintf(format, args...)})
+}
+
+/*   Truncated below want, overdumped above      */
+func (c *romCheck) expectSize(want int64, source string) {
+	switch {
+	case c.size < want:
+		c.report(ProblemBadSize, "truncated: %s says %s, file is %s", source, formatSize(want), formatSize(c.size))
+	case c.size > want:
+		c.report(ProblemBadSize, "overdump: %s says %s, file is %s", source, formatSize(want), formatSize(c.size))
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                 NES (iNES)                     */
+/*   16 byte header, optional 512 byte trainer,   */
+/*   then PRG in 16 KiB and CHR in 8 KiB units    */
+/*                                                */
+/**************************************************/
+
+func (c *romCheck) nes() {
+	h := c.data
+	if len(h) < 16 || !bytes.Equal(h[:4], []byte("NES\x1a")) {
+		c.report(ProblemBadHeader, "missing iNES header")
+		return
+	}
+
+	prg, chr := int64(h[4]), int64(h[5])
+	if h[7]&0x0C == 0x08 {
+		/*   NES 2.0 keeps the high size bits in     */
+		/*   byte 9; the exponent form is rare and   */
+		/*   left unchecked                          */
+		if h[9]&0x0F == 0x0F || h[9]&0xF0 == 0xF0 {
+			return
+		}
+		prg |= int64(h[9]&0x0F) << 8
+		chr |= int64(h[9]&0xF0) << 4
+	}
+	if prg == 0 {
+		c.report(ProblemBadHeader, "iNES header declares no PRG ROM")
+		return
+	}
+
+	want := 16 + prg*16384 + chr*8192
+	if h[6]&0x04 != 0 {
+		want += 512
+	}
+	c.expectSize(want, "iNES header")
+}
+
+/**************************************************/
+/*                                                */
+/*                    SNES                        */
+/*   Internal header at LoROM, HiROM or ExHiROM   */
+/*   offsets, found by its checksum complement    */
+/*                                                */
+/**************************************************/
+
+func (c *romCheck) snes() {
+	rom := c.data
+	romSize := c.size
+	if c.size%1024 == 512 {
+		/*   Copier header from old backup units     */
+		rom, romSize = rom[min(512, len(rom)):], romSize-512
+	}
+	if romSize%32768 != 0 {
+		c.report(ProblemBadSize, "size %s is not a multiple of 32 KiB", formatSize(romSize))
+	}
+
+	header := -1
+	for _, offset := range []int{0x7FC0, 0xFFC0, 0x40FFC0} {
+		if offset+0x20 > len(rom) {
+			continue
+		}
+		complement := binary.LittleEndian.Uint16(rom[offset+0x1C:])
+		checksum := binary.LittleEndian.Uint16(rom[offset+0x1E:])
+		if complement^checksum == 0xFFFF {
+			header = offset
+			break
+		}
+	}
+	if header < 0 {
+		c.report(ProblemBadHeader, "no internal header with a valid checksum complement")
+		return
+	}
+
+	if n := rom[header+0x17]; n > 0 && n < 16 {
+		if declared := int64(1024) << n; romSize < declared {
+			c.report(ProblemBadSize, "truncated: internal header says %s, ROM is %s", formatSize(declared), formatSize(romSize))
+		}
+	}
+
+	if !c.full {
+		return
+	}
+	if sum, ok := snesChecksum(rom); ok {
+		if want := binary.LittleEndian.Uint16(rom[header+0x1E:]); sum != want {
+			c.report(ProblemChecksumMismatch, "internal checksum is %04X, contents sum to %04X", want, sum)
+		}
+	}
+}
+
+/*   Sizes that are not a power of two mirror   */
+/*   their tail up to the next one              */
+func snesChecksum(rom []byte) (uint16, bool) {
+	base := 1
+	for base*2 <= len(rom) {
+		base *= 2
+	}
+	sum := sumBytes(rom[:base])
+	if rest := len(rom) - base; rest > 0 {
+		if base%rest != 0 {
+			return 0, false
+		}
+		sum += sumBytes(rom[base:]) * uint32(base/rest)
+	}
+	return uint16(sum), true
+}
+
+/**************************************************/
+/*                                                */
+/*                 NINTENDO 64                    */
+/*   Byte order shows in the first word: z64 is   */
+/*   big endian, v64 byte swapped, n64 little     */
+/*                                                */
+/**************************************************/
+
+var n64Magic = [][]byte{
+	{0x80, 0x37, 0x12, 0x40},
+	{0x37, 0x80, 0x40, 0x12},
+	{0x40, 0x12, 0x37, 0x80},
+}
+
+func (c *romCheck) n64() {
+	known := false
+	for _, magic := range n64Magic {
+		known = known || bytes.HasPrefix(c.data, magic)
+	}
+	if !known {
+		c.report(ProblemBadHeader, "unrecognized N64 header")
+	}
+	if c.size < 1<<20 {
+		c.report(ProblemBadSize, "truncated: %s is below the 1 MiB minimum", formatSize(c.size))
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*              GAME BOY ADVANCE                  */
+/*                                                */
+/**************************************************/
+
+func (c *romCheck) gba() {
+	h := c.data
+	if len(h) < 0xC0 {
+		c.report(ProblemBadSize, "truncated: %s is shorter than the cartridge header", formatSize(c.size))
+		return
+	}
+	if h[0xB2] != 0x96 {
+		c.report(ProblemBadHeader, "fixed header byte is %02X, want 96", h[0xB2])
+	}
+
+	/*   Complement over 0xA0-0xBC, checked by the  */
+	/*   BIOS at boot                               */
+	sum := byte(0)
+	for _, b := range h[0xA0:0xBD] {
+		sum -= b
+	}
+	if sum -= 0x19; h[0xBD] != sum {
+		c.report(ProblemChecksumMismatch, "header complement is %02X, want %02X", h[0xBD], sum)
+	}
+	if c.size > 32<<20 {
+		c.report(ProblemBadSize, "overdump: %s exceeds the 32 MiB cartridge limit", formatSize(c.size))
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*              GAME BOY / COLOR                  */
+/*                                                */
+/**************************************************/
+
+var gbLogoStart = []byte{0xCE, 0xED, 0x66, 0x66}
+
+func (c *romCheck) gb() {
+	h := c.data
+	if len(h) < 0x150 {
+		c.report(ProblemBadSize, "truncated: %s is shorter than the cartridge header", formatSize(c.size))
+		return
+	}
+	if !bytes.HasPrefix(h[0x104:], gbLogoStart) {
+		c.report(ProblemBadHeader, "boot logo is missing from the header")
+	}
+
+	/*   Header checksum: the boot ROM locks up    */
+	/*   when it is wrong                          */
+	sum := byte(0)
+	for _, b := range h[0x134:0x14D] {
+		sum = sum - b - 1
+	}
+	if h[0x14D] != sum {
+		c.report(ProblemChecksumMismatch, "header checksum is %02X, want %02X", h[0x14D], sum)
+	}
+
+	if n := h[0x148]; n <= 8 {
+		c.expectSize(int64(32768)<<n, "cartridge header")
+	}
+
+	/*   Global checksum: ignored by hardware,     */
+	/*   but a reliable sign of a bad dump         */
+	if c.full {
+		global := uint16(sumBytes(h) - uint32(h[0x14E]) - uint32(h[0x14F]))
+		if want := binary.BigEndian.Uint16(h[0x14E:]); global != want {
+			c.report(ProblemChecksumMismatch, "global checksum is %04X, contents sum to %04X", want, global)
+		}
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                 ATARI 2600                     */
+/*   No header; only the cartridge sizes that     */
+/*   bank switching schemes actually use          */
+/*                                                */
+/**************************************************/
+
+var atariSizes = []int64{2048, 4096, 6144, 8192, 10240, 12288, 16384, 32768, 65536, 131072, 262144, 524288}
+
+func (c *romCheck) atari() {
+	for _, size := range atariSizes {
+		if c.size == size {
+			return
+		}
+	}
+	c.report(ProblemBadSize, "%s is not a known 2600 cartridge size", formatSize(c.size))
+}
+
+/**************************************************/
+/*                                                */
+/*                  HELPERS                       */
+/*                                                */
+/**************************************************/
+
+func sumBytes(data []byte) uint32 {
+	sum := uint32(0)
+	for _, b := range data {
+		sum += uint32(b)
+	}
+	return sum
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20 && size%(1<<20) == 0:
+		return fmt.Sprintf("%d MiB", size>>20)
+	case size >= 1024 && size%1024 == 0:
+		return fmt.Sprintf("%d KiB", size>>10)
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
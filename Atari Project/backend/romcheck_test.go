@@ -0,0 +1,361 @@
+/**************************************/
+/*                                    */
+/*       ROM Header Check Tests       */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package library
+
+import (
+	"encoding/binary"
+	"slices"
+	"strings"
+	"testing"
+)
+
+/*   Kinds in the order checkROM reports them,  */
+/*   and a word the first detail must contain   */
+type romWant struct {
+	kinds  []string
+	detail string
+}
+
+func checkFindings(t *testing.T, platform, path string, data []byte, size int64, want romWant) {
+	t.Helper()
+	findings := checkROM(platform, path, data, size)
+	kinds := make([]string, 0, len(findings))
+	for _, f := range findings {
+		kinds = append(kinds, f.Kind)
+	}
+	if !slices.Equal(kinds, want.kinds) {
+		t.Fatalf("findings %+v, want kinds %v", findings, want.kinds)
+	}
+	if want.detail != "" && !strings.Contains(findings[0].Detail, want.detail) {
+		t.Errorf("detail %q does not mention %q", findings[0].Detail, want.detail)
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                    NES                         */
+/*   Only the header is passed; the size says     */
+/*   how long the file is                         */
+/*                                                */
+/**************************************************/
+
+func inesHeader(prg, chr, flags6, flags7, byte9 byte) []byte {
+	h := make([]byte, 16)
+	copy(h, "NES\x1a")
+	h[4], h[5], h[6], h[7], h[9] = prg, chr, flags6, flags7, byte9
+	return h
+}
+
+func TestCheckNES(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		header []byte
+		size   int64
+		want   romWant
+	}{
+		{"exact", "a.nes", inesHeader(2, 1, 0, 0, 0), 16 + 2*16384 + 8192, romWant{}},
+		{"no CHR", "a.nes", inesHeader(8, 0, 0, 0, 0), 16 + 8*16384, romWant{}},
+		{"trainer", "a.nes", inesHeader(2, 1, 0x04, 0, 0), 16 + 512 + 2*16384 + 8192, romWant{}},
+		{"trainer missing", "a.nes", inesHeader(2, 1, 0x04, 0, 0), 16 + 2*16384 + 8192, romWant{[]string{ProblemBadSize}, "truncated"}},
+		{"truncated", "a.nes", inesHeader(2, 1, 0, 0, 0), 16 + 2*16384, romWant{[]string{ProblemBadSize}, "truncated"}},
+		{"overdump", "a.nes", inesHeader(1, 1, 0, 0, 0), 16 + 2*16384 + 8192, romWant{[]string{ProblemBadSize}, "overdump"}},
+		{"no PRG", "a.nes", inesHeader(0, 1, 0, 0, 0), 16 + 8192, romWant{[]string{ProblemBadHeader}, "PRG"}},
+		{"no magic", "a.nes", make([]byte, 16), 40976, romWant{[]string{ProblemBadHeader}, "iNES"}},
+		{"short header", "a.nes", []byte("NES\x1a"), 4, romWant{[]string{ProblemBadHeader}, "iNES"}},
+		{"extension any case", "A.NES", inesHeader(0, 0, 0, 0, 0), 16, romWant{[]string{ProblemBadHeader}, "PRG"}},
+		{"disk images skipped", "a.fds", make([]byte, 16), 65500, romWant{}},
+
+		/*   NES 2.0: byte 9 holds the high nibbles   */
+		{"NES 2.0 high PRG bits", "a.nes", inesHeader(0x02, 0x00, 0, 0x08, 0x01), 16 + 0x102*16384, romWant{}},
+		{"NES 2.0 high CHR bits", "a.nes", inesHeader(0x02, 0x04, 0, 0x08, 0x10), 16 + 2*16384 + 0x104*8192, romWant{}},
+		{"NES 2.0 high bits ignored by iNES", "a.nes", inesHeader(0x02, 0x00, 0, 0x00, 0x01), 16 + 2*16384, romWant{}},
+		{"NES 2.0 truncated", "a.nes", inesHeader(0x02, 0x00, 0, 0x08, 0x01), 16 + 2*16384, romWant{[]string{ProblemBadSize}, "truncated"}},
+		{"NES 2.0 exponent PRG unchecked", "a.nes", inesHeader(0x07, 0x00, 0, 0x08, 0x0F), 12345, romWant{}},
+		{"NES 2.0 exponent CHR unchecked", "a.nes", inesHeader(0x02, 0x07, 0, 0x08, 0xF0), 12345, romWant{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkFindings(t, "NES", tt.path, tt.header, tt.size, tt.want)
+		})
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                    SNES                        */
+/*                                                */
+/**************************************************/
+
+const (
+	snesLoROM = 0x7FC0
+	snesHiROM = 0xFFC0
+)
+
+/*   A patterned ROM with an internal header at  */
+/*   offset; the checksum is the mirrored sum    */
+func snesROM(size, offset int, sizeCode byte) []byte {
+	rom := make([]byte, size)
+	for i := range rom {
+		rom[i] = byte(i * 7)
+	}
+	rom[offset+0x17] = sizeCode
+	/*   Complement and checksum bytes always add   */
+	/*   up to 0x1FE, so seed them before summing   */
+	binary.LittleEndian.PutUint16(rom[offset+0x1C:], 0xFFFF)
+	binary.LittleEndian.PutUint16(rom[offset+0x1E:], 0x0000)
+	sum, _ := snesChecksum(rom)
+	setSNESChecksum(rom, offset, sum)
+	return rom
+}
+
+func setSNESChecksum(rom []byte, offset int, sum uint16) {
+	binary.LittleEndian.PutUint16(rom[offset+0x1C:], ^sum)
+	binary.LittleEndian.PutUint16(rom[offset+0x1E:], sum)
+}
+
+func TestSNESChecksum(t *testing.T) {
+	tests := []struct {
+		name string
+		rom  []byte
+		want uint16
+		ok   bool
+	}{
+		{"power of two", []byte{1, 2, 3, 4}, 10, true},
+		{"single byte", []byte{9}, 9, true},
+		{"tail mirrored twice", []byte{1, 2, 3, 4, 5, 6}, 10 + 11*2, true},
+		{"tail mirrored four times", []byte{1, 2, 3, 4, 5}, 10 + 5*4, true},
+		{"tail that does not divide", []byte{1, 2, 3, 4, 5, 6, 7}, 0, false},
+		{"wraps at 16 bits", slices.Repeat([]byte{0xFF}, 512), 0xFE00, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := snesChecksum(tt.rom)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("snesChecksum = %04X, %v, want %04X, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCheckSNES(t *testing.T) {
+	const k32 = 32 << 10
+
+	corrupt := func(rom []byte) []byte {
+		rom[0x100] ^= 0xFF
+		return rom
+	}
+	copier := func(rom []byte) []byte {
+		return append(make([]byte, 512), rom...)
+	}
+
+	/*   96 KiB: the last 32 KiB count twice, so a  */
+	/*   plain sum of every byte is wrong           */
+	mirrored := snesROM(3*k32, snesLoROM, 0x06)
+	plain := slices.Clone(mirrored)
+	setSNESChecksum(plain, snesLoROM, uint16(sumBytes(plain)))
+
+	tests := []struct {
+		name string
+		rom  []byte
+		size int64 /*   0 for len(rom)   */
+		want romWant
+	}{
+		{"LoROM", snesROM(2*k32, snesLoROM, 0x06), 0, romWant{}},
+		{"HiROM", snesROM(4*k32, snesHiROM, 0x07), 0, romWant{}},
+		{"ExHiROM", snesROM(0x410000, 0x40FFC0, 0x0C), 0, romWant{}},
+		{"copier header", copier(snesROM(2*k32, snesLoROM, 0x06)), 0, romWant{}},
+		{"mirrored checksum", mirrored, 0, romWant{}},
+		{"unmirrored checksum", plain, 0, romWant{[]string{ProblemChecksumMismatch}, "internal checksum"}},
+		{"corrupt contents", corrupt(snesROM(2*k32, snesLoROM, 0x06)), 0, romWant{[]string{ProblemChecksumMismatch}, "internal checksum"}},
+		{"corrupt but only the start read", corrupt(snesROM(2*k32, snesLoROM, 0x06)), 3 * k32, romWant{}},
+		{"no header", make([]byte, 2*k32), 0, romWant{[]string{ProblemBadHeader}, "complement"}},
+		{"too short for any header", make([]byte, k32), 0, romWant{[]string{ProblemBadHeader}, "complement"}},
+		{"odd size", snesROM(2*k32+2048, snesLoROM, 0x06), 0, romWant{[]string{ProblemBadSize}, "32 KiB"}},
+		{"header says bigger", snesROM(2*k32, snesLoROM, 0x08), 0, romWant{[]string{ProblemBadSize}, "256 KiB"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := tt.size
+			if size == 0 {
+				size = int64(len(tt.rom))
+			}
+			checkFindings(t, "SNES", "game.sfc", tt.rom, size, tt.want)
+		})
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*              GAME BOY ADVANCE                  */
+/*                                                */
+/**************************************************/
+
+/*   edit runs before the complement is set     */
+func gbaHeader(edit func(h []byte)) []byte {
+	h := make([]byte, 0xC0)
+	copy(h[0xA0:], "TESTGAME\x00\x00\x00\x00ATSE01")
+	h[0xB2] = 0x96
+	if edit != nil {
+		edit(h)
+	}
+	sum := byte(0)
+	for _, b := range h[0xA0:0xBD] {
+		sum -= b
+	}
+	h[0xBD] = sum - 0x19
+	return h
+}
+
+func TestCheckGBA(t *testing.T) {
+	badComplement := gbaHeader(nil)
+	badComplement[0xBD]++
+
+	tests := []struct {
+		name   string
+		header []byte
+		size   int64
+		want   romWant
+	}{
+		{"valid", gbaHeader(nil), 8 << 20, romWant{}},
+		{"32 MiB is the limit", gbaHeader(nil), 32 << 20, romWant{}},
+		{"fixed byte", gbaHeader(func(h []byte) { h[0xB2] = 0 }), 8 << 20, romWant{[]string{ProblemBadHeader}, "96"}},
+		{"complement", badComplement, 8 << 20, romWant{[]string{ProblemChecksumMismatch}, "complement"}},
+		{"overdump", gbaHeader(nil), 64 << 20, romWant{[]string{ProblemBadSize}, "overdump"}},
+		{"shorter than the header", gbaHeader(nil)[:0x80], 0x80, romWant{[]string{ProblemBadSize}, "truncated"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkFindings(t, "GBA", "game.gba", tt.header, tt.size, tt.want)
+		})
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*              GAME BOY / COLOR                  */
+/*                                                */
+/**************************************************/
+
+/*   A full 32 KiB << sizeCode ROM; edit runs     */
+/*   before either checksum is set                */
+func gbROM(sizeCode byte, length int, edit func(rom []byte)) []byte {
+	rom := make([]byte, length)
+	for i := range rom {
+		rom[i] = byte(i * 13)
+	}
+	copy(rom[0x104:], gbLogoStart)
+	copy(rom[0x134:], "TESTGAME")
+	rom[0x148] = sizeCode
+	if edit != nil {
+		edit(rom)
+	}
+
+	sum := byte(0)
+	for _, b := range rom[0x134:0x14D] {
+		sum = sum - b - 1
+	}
+	rom[0x14D] = sum
+	global := sumBytes(rom) - uint32(rom[0x14E]) - uint32(rom[0x14F])
+	binary.BigEndian.PutUint16(rom[0x14E:], uint16(global))
+	return rom
+}
+
+func TestCheckGB(t *testing.T) {
+	const k32 = 32 << 10
+
+	badHeader := gbROM(0, k32, nil)
+	badHeader[0x14D]++
+	badGlobal := gbROM(0, k32, nil)
+	badGlobal[0x4000]++
+
+	tests := []struct {
+		name string
+		rom  []byte
+		size int64 /*   0 for len(rom)   */
+		want romWant
+	}{
+		{"32 KiB", gbROM(0, k32, nil), 0, romWant{}},
+		{"128 KiB", gbROM(2, 4*k32, nil), 0, romWant{}},
+		{"no logo", gbROM(0, k32, func(rom []byte) { rom[0x104] = 0 }), 0, romWant{[]string{ProblemBadHeader}, "logo"}},
+		/*   The global sum covers the header byte   */
+		{"header checksum", badHeader, 0, romWant{[]string{ProblemChecksumMismatch, ProblemChecksumMismatch}, "header checksum"}},
+		{"global checksum", badGlobal, 0, romWant{[]string{ProblemChecksumMismatch}, "global checksum"}},
+		{"global unchecked on a partial read", badGlobal[:0x150], k32, romWant{}},
+		{"truncated", gbROM(1, k32, nil), 0, romWant{[]string{ProblemBadSize}, "truncated"}},
+		{"overdump", gbROM(0, 2*k32, nil), 0, romWant{[]string{ProblemBadSize}, "overdump"}},
+		{"unknown size code", gbROM(0x52, k32, nil), 0, romWant{}},
+		{"shorter than the header", make([]byte, 0x100), 0, romWant{[]string{ProblemBadSize}, "truncated"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := tt.size
+			if size == 0 {
+				size = int64(len(tt.rom))
+			}
+			checkFindings(t, "GB", "game.gb", tt.rom, size, tt.want)
+		})
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*               OTHER PLATFORMS                  */
+/*                                                */
+/**************************************************/
+
+func TestCheckOtherPlatforms(t *testing.T) {
+	z64 := append([]byte{0x80, 0x37, 0x12, 0x40}, make([]byte, 60)...)
+	tests := []struct {
+		name     string
+		platform string
+		data     []byte
+		size     int64
+		want     romWant
+	}{
+		{"empty file", "SNES", nil, 0, romWant{[]string{ProblemBadSize}, "empty"}},
+		{"N64 z64", "N64", z64, 8 << 20, romWant{}},
+		{"N64 v64", "N64", []byte{0x37, 0x80, 0x40, 0x12}, 8 << 20, romWant{}},
+		{"N64 n64", "N64", []byte{0x40, 0x12, 0x37, 0x80}, 8 << 20, romWant{}},
+		{"N64 unknown", "N64", []byte{1, 2, 3, 4}, 8 << 20, romWant{[]string{ProblemBadHeader}, "N64"}},
+		{"N64 tiny", "N64", z64, 512 << 10, romWant{[]string{ProblemBadSize}, "1 MiB"}},
+		{"2600 4K", "ATARI", nil, 4096, romWant{}},
+		{"2600 F4 bank switching", "ATARI", nil, 32768, romWant{}},
+		{"2600 odd size", "ATARI", nil, 5000, romWant{[]string{ProblemBadSize}, "5000 bytes"}},
+		{"unchecked platform", "PS1", nil, 12345, romWant{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkFindings(t, tt.platform, "game.bin", tt.data, tt.size, tt.want)
+		})
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0 bytes"},
+		{1000, "1000 bytes"},
+		{1024, "1 KiB"},
+		{1536, "1536 bytes"},
+		{40960, "40 KiB"},
+		{1 << 20, "1 MiB"},
+		{3 << 19, "1536 KiB"},
+		{32 << 20, "32 MiB"},
+	}
+	for _, tt := range tests {
+		if got := formatSize(tt.size); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+/**************************************/
+/*                                    */
+/*      IPC Message Router - Go       */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+/**************************************************/
+/*                                                */
+/*                 ROUTER STRUCT                  */
+/*       Dispatches requests by message type      */
+/*                                                */
+/**************************************************/
+
+type Router struct {
+	routes map[string]Handler
+}
+
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]Handler)}
+}
+
+func (r *Router) Handle(msgType string, handler Handler) {
+	r.routes[msgType] = handler
+}
+
+func (r *Router) Types() []string {
+	types := make([]string, 0, len(r.routes))
+	for t := range r.routes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func (r *Router) ServeIPC(ctx context.Context, req Request) Response {
+	handler, ok := r.routes[req.Type]
+	if !ok {
+		return Fail(req, ErrCodeUnknownType, fmt.Sprintf("Unknown message type: %s", req.Type))
+	}
+	return handler(ctx, req)
+}
+
+/*   Installs the router as the handler and     */
+/*   advertises its types in hello              */
+func (s *IPCServer) SetRouter(r *Router) {
+	s.SetHandler(r.ServeIPC)
+	s.Support(r.Types()...)
+}
+
+/**************************************************/
+/*                                                */
+/*              RESPONSE HELPERS                  */
+/*                                                */
+/**************************************************/
+
+func OK(req Request, data interface{}) Response {
+	return Response{Type: MsgTypeSuccess, ID: req.ID, Success: true, Data: data}
+}
+
+func Fail(req Request, code, message string) Response {
+	return Response{Type: MsgTypeError, ID: req.ID, Success: false, Error: message, Code: code}
+}
+
+/*   An empty payload leaves v untouched         */
+func DecodePayload(req Request, v interface{}) error {
+	if len(req.Payload) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(req.Payload, v); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", req.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+/**************************************/
+/*                                    */
+/*     Scan Paths and Their Options   */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+/**************************************************/
+/*                                                */
+/*              SCAN PATH OPTIONS                 */
+/*                                                */
+/**************************************************/
+
+/*   Skipped under every scan path unless        */
+/*   NoDefaultExcludes is set: hidden entries,   */
+/*   BIOS folders and emulator output            */
+var DefaultExcludes = []string{
+	".*", "bios", "saves", "states", "savestates", "screenshots", "thumbnails",
+}
+
+/*   Patterns use path.Match syntax and ignore   */
+/*   case. One without "/" matches a file or     */
+/*   folder name at any depth; one with "/"      */
+/*   matches the path relative to the scan root  */
+type ScanPath struct {
+	Path              string   `json:"path"`
+	MaxDepth          int      `json:"max_depth,omitempty"` /*   0 = unlimited */
+	FollowSymlinks    bool     `json:"follow_symlinks,omitempty"`
+	Include           []string `json:"include,omitempty"`
+	Exclude           []string `json:"exclude,omitempty"`
+	NoDefaultExcludes bool     `json:"no_default_excludes,omitempty"`
+	Platform          string   `json:"platform,omitempty"`
+	Category          string   `json:"category,omitempty"`
+}
+
+/*   A path with no options is stored as a bare  */
+/*   string, as library.json always had it       */
+func (sp ScanPath) MarshalJSON() ([]byte, error) {
+	if !sp.HasOptions() {
+		return json.Marshal(sp.Path)
+	}
+	type raw ScanPath
+	return json.Marshal(raw(sp))
+}
+
+func (sp *ScanPath) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*sp = ScanPath{Path: text}
+		return nil
+	}
+	type raw ScanPath
+	var decoded raw
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("scan path must be a string or an object: %w", err)
+	}
+	*sp = ScanPath(decoded)
+	return nil
+}
+
+func (sp ScanPath) HasOptions() bool {
+	return sp.MaxDepth != 0 || sp.FollowSymlinks || len(sp.Include) > 0 || len(sp.Exclude) > 0 ||
+		sp.NoDefaultExcludes || sp.Platform != "" || sp.Category != ""
+}
+
+func (sp ScanPath) Validate() error {
+	if sp.Path == "" {
+		return fmt.Errorf("scan path must not be empty")
+	}
+	if sp.MaxDepth < 0 {
+		return fmt.Errorf("max_depth must not be negative")
+	}
+	for _, pattern := range append(append([]string{}, sp.Include...), sp.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (sp ScanPath) normalized() ScanPath {
+	sp.Path = filepath.Clean(sp.Path)
+	sp.Platform = strings.ToUpper(strings.TrimSpace(sp.Platform))
+	return sp
+}
+
+/*   Whether file lies under this scan path      */
+func (sp ScanPath) Contains(file string) bool {
+	rel, err := filepath.Rel(sp.Path, file)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+/**************************************************/
+/*                                                */
+/*               PATTERN MATCHING                 */
+/*                                                */
+/**************************************************/
+
+func (sp ScanPath) excluded(rel, name string) bool {
+	if !sp.NoDefaultExcludes && matchAny(DefaultExcludes, rel, name) {
+		return true
+	}
+	return matchAny(sp.Exclude, rel, name)
+}
+
+func (sp ScanPath) included(rel, name string) bool {
+	return len(sp.Include) == 0 || matchAny(sp.Include, rel, name)
+}
+
+func matchAny(patterns []string, rel, name string) bool {
+	rel, name = strings.ToLower(filepath.ToSlash(rel)), strings.ToLower(name)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = rel
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+/*   Forced platform wins; otherwise by          */
+/*   extension. With a forced platform, files    */
+/*   with unknown extensions count only when an  */
+/*   include pattern picked them                 */
+func (sp ScanPath) platformFor(file string) string {
+	detected := detectPlatform(strings.ToLower(filepath.Ext(file)))
+	switch {
+	case sp.Platform == "":
+		return detected
+	case detected != "" || len(sp.Include) > 0:
+		return sp.Platform
+	}
+	return ""
+}
+
+/**************************************************/
+/*                                                */
+/*                    WALK                        */
+/*   Honors depth, symlink and pattern options;   */
+/*   unreadable folders are logged and skipped    */
+/*                                                */
+/**************************************************/
+
+func (lib *Library) walkScanPath(ctx context.Context, sp ScanPath, fn func(path string, info os.FileInfo) error) error {
+	visited := make(map[string]bool)
+
+	var walkDir func(dir string, depth int) error
+	walkDir = func(dir string, depth int) error {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			if visited[real] {
+				return nil
+			}
+			visited[real] = true
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			lib.logger.Debug("skipping unreadable folder", "path", dir, "error", err)
+			return nil
+		}
+		for _, entry := range entries {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			full := filepath.Join(dir, entry.Name())
+			rel, _ := filepath.Rel(sp.Path, full)
+			if sp.excluded(rel, entry.Name()) {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err == nil && info.Mode()&os.ModeSymlink != 0 {
+				/*   Symlinked files always count; linked  */
+				/*   folders only when following links     */
+				info, err = os.Stat(full)
+				if err == nil && info.IsDir() && !sp.FollowSymlinks {
+					continue
+				}
+			}
+			if err != nil {
+				lib.logger.Debug("skipping unreadable path", "path", full, "error", err)
+				continue
+			}
+
+			if info.IsDir() {
+				if sp.MaxDepth == 0 || depth < sp.MaxDepth {
+					if err := walkDir(full, depth+1); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if !sp.included(rel, entry.Name()) {
+				continue
+			}
+			if err := fn(full, info); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walkDir(sp.Path, 1)
+}
+
+/**************************************************/
+/*                                                */
+/*             SCAN PATH STATUS                   */
+/*                                                */
+/**************************************************/
+
+const (
+	ScanPathOK           = "ok"
+	ScanPathMissing      = "missing"
+	ScanPathUnreadable   = "unreadable"
+	ScanPathNotDirectory = "not_a_directory"
+)
+
+type ScanPathStatus struct {
+	ScanPath
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Games  int    `json:"games"`
+}
+
+/*   Embedding would pick up ScanPath's own      */
+/*   MarshalJSON, so spell the fields out        */
+func (s ScanPathStatus) MarshalJSON() ([]byte, error) {
+	type raw ScanPath
+	return json.Marshal(struct {
+		raw
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+		Games  int    `json:"games"`
+	}{raw(s.ScanPath), s.Status, s.Error, s.Games})
+}
+
+func (s *ScanPathStatus) UnmarshalJSON(data []byte) error {
+	type raw ScanPath
+	var decoded struct {
+		raw
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+		Games  int    `json:"games"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*s = ScanPathStatus{ScanPath(decoded.raw), decoded.Status, decoded.Error, decoded.Games}
+	return nil
+}
+
+func (lib *Library) ScanPathStatuses() []ScanPathStatus {
+	lib.mu.RLock()
+	paths := append([]ScanPath{}, lib.ScanPaths...)
+	games := make([]string, len(lib.Games))
+	for i, game := range lib.Games {
+		games[i] = game.Path
+	}
+	lib.mu.RUnlock()
+
+	statuses := make([]ScanPathStatus, 0, len(paths))
+	for _, sp := range paths {
+		status := ScanPathStatus{ScanPath: sp, Status: ScanPathOK}
+		if info, err := os.Stat(sp.Path); os.IsNotExist(err) {
+			status.Status = ScanPathMissing
+		} else if err != nil {
+			status.Status, status.Error = ScanPathUnreadable, err.Error()
+		} else if !info.IsDir() {
+			status.Status = ScanPathNotDirectory
+		} else if dir, err := os.Open(sp.Path); err != nil {
+			status.Status, status.Error = ScanPathUnreadable, err.Error()
+		} else {
+			if _, err := dir.Readdirnames(1); err != nil && !errors.Is(err, io.EOF) {
+				status.Status, status.Error = ScanPathUnreadable, err.Error()
+			}
+			dir.Close()
+		}
+
+		for _, game := range games {
+			if sp.Contains(game) {
+				status.Games++
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -12,11 +12,17 @@ package server
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 /**************************************************/
@@ -29,18 +35,62 @@ const (
 	MsgTypeListGames      = "list_games"
 	MsgTypeGetGame        = "get_game"
 	MsgTypeLaunchGame     = "launch_game"
+	MsgTypeSetProfile     = "set_launch_profile"
+	MsgTypeClearProfile   = "clear_launch_profile"
+	MsgTypeGetFirmware    = "get_firmware_status"
+	MsgTypeGetMedia       = "get_media"
 	MsgTypeGetCategories  = "get_categories"
 	MsgTypeGetPlatforms   = "get_platforms"
 	MsgTypeGetFavorites   = "get_favorites"
 	MsgTypeToggleFavorite = "toggle_favorite"
 	MsgTypeGetRecent      = "get_recent"
 	MsgTypeScan           = "scan"
+	MsgTypeCancelScan     = "cancel_scan"
 	MsgTypeAddScanPath    = "add_scan_path"
+	MsgTypeRemoveScanPath = "remove_scan_path"
+	MsgTypeListScanPaths  = "list_scan_paths"
+	MsgTypeGetStats       = "get_stats"
+	MsgTypeVerifyLibrary  = "verify_library"
+	MsgTypeAnalyzeLibrary = "analyze_library"
+	MsgTypeExportLibrary  = "export_library"
+	MsgTypeImportLibrary  = "import_library"
 	MsgTypeStatus         = "status"
+	MsgTypeAuth           = "auth"
+	MsgTypeHello          = "hello"
+	MsgTypeSubscribe      = "subscribe"
+	MsgTypeUnsubscribe    = "unsubscribe"
+	MsgTypeEvent          = "event"
+	MsgTypeGetLogs        = "get_logs"
+	MsgTypeReloadConfig   = "reload_config"
 	MsgTypeError          = "error"
 	MsgTypeSuccess        = "success"
 )
 
+/*          Machine-readable error codes          */
+const (
+	ErrCodeUnauthorized = "unauthorized"
+	ErrCodeForbidden    = "forbidden"
+	ErrCodeBadRequest   = "bad_request"
+
+	ErrCodeIncompatibleVersion = "incompatible_version"
+	ErrCodeMessageTooLarge     = "message_too_large"
+	ErrCodeRateLimited         = "rate_limited"
+	ErrCodeTimeout             = "timeout"
+	ErrCodeTooManyConnections  = "too_many_connections"
+	ErrCodeUnknownType         = "unknown_type"
+	ErrCodeNotFound            = "not_found"
+	ErrCodeInternal            = "internal_error"
+	ErrCodeBusy                = "busy"
+	ErrCodeLaunchFailed        = "launch_failed"
+	ErrCodeFirmwareMissing     = "firmware_missing"
+	ErrCodeCanceled            = "canceled"
+)
+
+const (
+	DefaultShutdownTimeout = 5 * time.Second
+	acceptRetryDelay       = 50 * time.Millisecond
+)
+
 /**************************************************/
 /*                                                */
 /*           REQUEST / RESPONSE STRUCTS           */
@@ -59,16 +109,85 @@ type Response struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	Code    string      `json:"code,omitempty"`
 }
 
 type GameListPayload struct {
 	Platform string `json:"platform,omitempty"`
 	Category string `json:"category,omitempty"`
+	Query    string `json:"query,omitempty"`
 	Limit    int    `json:"limit,omitempty"`
 }
 
+/*   Options only apply to add_scan_path; with  */
+/*   none, an existing path keeps its own       */
 type ScanPathPayload struct {
-	Path string `json:"path"`
+	Path              string   `json:"path"`
+	MaxDepth          int      `json:"max_depth,omitempty"`
+	FollowSymlinks    bool     `json:"follow_symlinks,omitempty"`
+	Include           []string `json:"include,omitempty"`
+	Exclude           []string `json:"exclude,omitempty"`
+	NoDefaultExcludes bool     `json:"no_default_excludes,omitempty"`
+	Platform          string   `json:"platform,omitempty"`
+	Category          string   `json:"category,omitempty"`
+}
+
+/*   Empty fields fall back to the platform's   */
+/*   emulator config                            */
+type LaunchProfilePayload struct {
+	GameID     string            `json:"game_id"`
+	Emulator   string            `json:"emulator,omitempty"`
+	Core       string            `json:"core,omitempty"`
+	Args       []string          `json:"args,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+	WorkingDir string            `json:"working_dir,omitempty"`
+	PreLaunch  string            `json:"pre_launch,omitempty"`
+	PostExit   string            `json:"post_exit,omitempty"`
+}
+
+/*   No platform reports every known file       */
+type FirmwarePayload struct {
+	Platform string `json:"platform,omitempty"`
+}
+
+/*   Both optional: every game, every kind      */
+type MediaPayload struct {
+	GameID string `json:"game_id,omitempty"`
+	Kind   string `json:"kind,omitempty"`
+}
+
+/*   Regions best first, e.g. ["Europe", "USA"] */
+type AnalyzePayload struct {
+	PreferRegions []string `json:"prefer_regions,omitempty"`
+}
+
+/*   Without a format, export_library returns   */
+/*   the JSON document itself                   */
+type ExportPayload struct {
+	Format    string `json:"format,omitempty"`
+	Platform  string `json:"platform,omitempty"`
+	Favorites bool   `json:"favorites,omitempty"`
+	BaseDir   string `json:"base_dir,omitempty"`
+}
+
+type ExportResult struct {
+	Format  string `json:"format"`
+	Content string `json:"content"`
+}
+
+/*   import_library also accepts a bare export  */
+/*   document as its payload                    */
+type ImportPayload struct {
+	Format  string `json:"format"`
+	Content string `json:"content"`
+	BaseDir string `json:"base_dir,omitempty"`
+	Prefer  string `json:"prefer,omitempty"`
+	Launch  bool   `json:"launch,omitempty"` /*   adopt launch profiles */
+}
+
+type GetLogsPayload struct {
+	Limit int    `json:"limit,omitempty"`
+	Level string `json:"level,omitempty"`
 }
 
 /**************************************************/
@@ -79,11 +198,51 @@ type ScanPathPayload struct {
 
 type IPCServer struct {
 	listener net.Listener
-	clients  map[net.Conn]bool
+	clients  map[net.Conn]*Client
 	mu       sync.RWMutex
-	port     int
-	running  bool
-	handler  func(req Request) Response
+	addr     string
+	running  atomic.Bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	/*   Handlers get reqCtx, which is only        */
+	/*   cancelled once the drain deadline passes  */
+	reqCtx    context.Context
+	reqCancel context.CancelFunc
+
+	logger      *slog.Logger
+	handler     Handler
+	middlewares []Middleware
+	auth        *Authenticator
+	specs       map[string]MessageSpec
+	supported   map[string]bool
+	features    map[string]bool
+	limits      Limits
+}
+
+/*   Per-connection state. Scopes are empty     */
+/*   until the client authenticates.            */
+type Client struct {
+	conn     net.Conn
+	name     string
+	scopes   []Scope
+	protocol string
+	limiter  *rateLimiter
+	writeMu  sync.Mutex
+
+	/*   Queued events for writeEvents; done is    */
+	/*   closed when the read loop ends            */
+	events  chan Response
+	done    chan struct{}
+	drained chan struct{}
+	dropped atomic.Int64
+
+	/*   Guarded by mu; scopes is also written     */
+	/*   under it, since Broadcast reads it        */
+	mu      sync.Mutex
+	topics  map[string]bool
+	closing bool
 }
 
 /**************************************************/
@@ -93,17 +252,39 @@ type IPCServer struct {
 /**************************************************/
 
 func NewIPCServer(port int) *IPCServer {
-	return &IPCServer{
-		clients: make(map[net.Conn]bool),
-		port:    port,
-		running: false,
+	return NewIPCServerAddr(fmt.Sprintf("127.0.0.1:%d", port))
+}
+
+func NewIPCServerAddr(addr string) *IPCServer {
+	s := &IPCServer{
+		clients:   make(map[net.Conn]*Client),
+		addr:      addr,
+		specs:     defaultMessageSpecs(),
+		supported: make(map[string]bool),
+		features:  make(map[string]bool),
+		limits:    DefaultLimits(),
+		logger:    slog.Default(),
 	}
+	s.Support(builtinMessages...)
+	return s
 }
 
-func (s *IPCServer) SetHandler(handler func(req Request) Response) {
+func (s *IPCServer) SetHandler(handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.handler = handler
 }
 
+func (s *IPCServer) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+/*   Without an authenticator every client is   */
+/*   treated as admin                            */
+func (s *IPCServer) SetAuthenticator(auth *Authenticator) {
+	s.auth = auth
+}
+
 /**************************************************/
 /*                                                */
 /*             START / STOP SERVER                */
@@ -111,32 +292,76 @@ func (s *IPCServer) SetHandler(handler func(req Request) Response) {
 /**************************************************/
 
 func (s *IPCServer) Start() error {
-	addr := fmt.Sprintf("127.0.0.1:%d", s.port)
-	listener, err := net.Listen("tcp", addr)
+	listener, err := net.Listen("tcp", s.addr)
 	if err != nil {
 		return fmt.Errorf("failed to start server: %w", err)
 	}
 
 	s.listener = listener
-	s.running = true
-	fmt.Printf("IPC Server started on %s\n", addr)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.reqCtx, s.reqCancel = context.WithCancel(context.Background())
+	s.running.Store(true)
+	s.logger.Info("IPC server started", "addr", listener.Addr().String())
+
+	s.wg.Add(1)
 	go s.acceptConnections()
 	return nil
 }
 
-func (s *IPCServer) Stop() {
-	s.running = false
-	s.mu.Lock()
-	for conn := range s.clients {
-		conn.Close()
+/*   Stops accepting, lets in-flight requests    */
+/*   finish until ctx expires, then closes all   */
+/*   connections. Returns once every server      */
+/*   goroutine has exited.                       */
+func (s *IPCServer) Shutdown(ctx context.Context) error {
+	if !s.running.CompareAndSwap(true, false) {
+		return nil
 	}
-	s.clients = make(map[net.Conn]bool)
-	s.mu.Unlock()
 
-	if s.listener != nil {
-		s.listener.Close()
+	s.cancel()
+	s.listener.Close()
+	s.Broadcast(TopicServerShutdown, map[string]string{"reason": "server stopping"})
+
+	/*   Wake idle readers; busy ones finish      */
+	/*   their request and then see closing       */
+	s.mu.RLock()
+	for _, client := range s.clients {
+		client.mu.Lock()
+		client.closing = true
+		client.conn.SetReadDeadline(time.Now())
+		client.mu.Unlock()
 	}
-	fmt.Println("IPC Server stopped")
+	s.mu.RUnlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+		s.logger.Warn("shutdown deadline reached, closing remaining connections")
+		s.reqCancel()
+		s.mu.RLock()
+		for conn := range s.clients {
+			conn.Close()
+		}
+		s.mu.RUnlock()
+		<-done
+	}
+
+	s.reqCancel()
+	s.logger.Info("IPC server stopped")
+	return err
+}
+
+func (s *IPCServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+	defer cancel()
+	s.Shutdown(ctx)
 }
 
 /**************************************************/
@@ -146,60 +371,229 @@ func (s *IPCServer) Stop() {
 /**************************************************/
 
 func (s *IPCServer) acceptConnections() {
-	for s.running {
+	defer s.wg.Done()
+
+	for {
 		conn, err := s.listener.Accept()
 		if err != nil {
+			if errors.Is(err, net.ErrClosed) || s.ctx.Err() != nil {
+				return
+			}
+			s.logger.Error("accept failed", "error", err)
+			time.Sleep(acceptRetryDelay)
 			continue
 		}
 
+		limits := s.Limits()
+		client := &Client{
+			conn:    conn,
+			limiter: newRateLimiter(limits.RateLimit, limits.RateBurst),
+			topics:  make(map[string]bool),
+			events:  make(chan Response, eventQueueSize),
+			done:    make(chan struct{}),
+			drained: make(chan struct{}),
+		}
+		if s.auth == nil {
+			client.scopes = []Scope{ScopeAdmin}
+		}
+
 		s.mu.Lock()
-		s.clients[conn] = true
+		if s.ctx.Err() != nil {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		if limits.MaxConnections > 0 && len(s.clients) >= limits.MaxConnections {
+			s.mu.Unlock()
+			s.logger.Warn("client rejected: connection limit reached",
+				"remote", conn.RemoteAddr().String(), "limit", limits.MaxConnections)
+			s.sendError(client, "", ErrCodeTooManyConnections,
+				fmt.Sprintf("Too many connections: limit is %d", limits.MaxConnections))
+			conn.Close()
+			continue
+		}
+		s.clients[conn] = client
 		s.mu.Unlock()
 
-		fmt.Printf("Client connected: %s\n", conn.RemoteAddr())
-		go s.handleClient(conn)
+		s.logger.Info("client connected", "remote", conn.RemoteAddr().String())
+		s.wg.Add(1)
+		go s.handleClient(client)
 	}
 }
 
-func (s *IPCServer) handleClient(conn net.Conn) {
+func (s *IPCServer) handleClient(client *Client) {
+	conn := client.conn
+	go s.writeEvents(client)
 	defer func() {
+		close(client.done)
+		<-client.drained
+		if n := client.dropped.Load(); n > 0 {
+			s.logger.Warn("events dropped for slow client", "remote", conn.RemoteAddr().String(), "dropped", n)
+		}
 		conn.Close()
 		s.mu.Lock()
 		delete(s.clients, conn)
 		s.mu.Unlock()
+		s.logger.Info("client disconnected", "remote", conn.RemoteAddr().String())
+		s.wg.Done()
 	}()
 
 	reader := bufio.NewReader(conn)
 
-	for s.running {
-		line, err := reader.ReadString('\n')
+	for {
+		limits := s.Limits()
+
+		/*   Idle timeout covers the wait for the     */
+		/*   next message, read timeout its body      */
+		if !client.armRead(limits.IdleTimeout) {
+			break
+		}
+		if _, err := reader.Peek(1); err != nil {
+			if isTimeout(err) && !client.isClosing() {
+				s.sendError(client, "", ErrCodeTimeout, "Idle timeout: closing connection")
+			}
+			break
+		}
+		if !client.armRead(limits.ReadTimeout) {
+			break
+		}
+
+		data, err := readMessage(reader, limits.MaxMessageSize)
 		if err != nil {
+			switch {
+			case errors.Is(err, errMessageTooLarge):
+				s.sendError(client, "", ErrCodeMessageTooLarge,
+					fmt.Sprintf("Message too large: limit is %d bytes", limits.MaxMessageSize))
+			case isTimeout(err) && !client.isClosing():
+				s.sendError(client, "", ErrCodeTimeout, "Read timeout: incomplete message")
+			}
 			break
 		}
 
-		line = strings.TrimSpace(line)
+		line := strings.TrimSpace(string(data))
 		if line == "" {
 			continue
 		}
 
+		if !client.limiter.Allow() {
+			s.sendError(client, "", ErrCodeRateLimited,
+				fmt.Sprintf("Rate limit exceeded: %.0f requests per second", limits.RateLimit))
+			break
+		}
+
 		var req Request
 		if err := json.Unmarshal([]byte(line), &req); err != nil {
-			s.sendError(conn, "", fmt.Sprintf("Invalid JSON: %v", err))
+			s.sendError(client, "", ErrCodeBadRequest, fmt.Sprintf("Invalid JSON: %v", err))
 			continue
 		}
 
-		var resp Response
-		if s.handler != nil {
-			resp = s.handler(req)
-		} else {
-			resp = s.defaultHandler(req)
+		switch req.Type {
+		case MsgTypeHello:
+			resp, keep := s.hello(client, req)
+			s.sendResponse(client, resp)
+			if !keep {
+				return
+			}
+			continue
+		case MsgTypeAuth:
+			var payload AuthPayload
+			if req.Payload != nil {
+				json.Unmarshal(req.Payload, &payload)
+			}
+			s.sendResponse(client, s.authenticate(client, req.ID, payload.Token))
+			continue
+		}
+		/*   An unknown type is reported as such,   */
+		/*   whatever the client's scopes           */
+		if !s.supports(req.Type) {
+			s.sendResponse(client, Fail(req, ErrCodeUnknownType, fmt.Sprintf("Unknown message type: %s", req.Type)))
+			continue
+		}
+		if resp, ok := s.authorize(client, req); !ok {
+			s.sendResponse(client, resp)
+			continue
+		}
+		if req.Type == MsgTypeSubscribe || req.Type == MsgTypeUnsubscribe {
+			s.sendResponse(client, s.subscribe(client, req))
+			continue
 		}
 
-		s.sendResponse(conn, resp)
+		resp := s.dispatch(client, req)
+		if err := s.sendResponse(client, resp); err != nil {
+			break
+		}
 	}
 }
 
-func (s *IPCServer) defaultHandler(req Request) Response {
+/**************************************************/
+/*                                                */
+/*         AUTHENTICATION / AUTHORIZATION         */
+/*                                                */
+/**************************************************/
+
+func (s *IPCServer) authenticate(client *Client, reqID, secret string) Response {
+	if s.auth == nil {
+		return Response{
+			Type: MsgTypeSuccess, ID: reqID, Success: true,
+			Data: map[string]interface{}{"name": client.name, "scopes": client.scopes},
+		}
+	}
+
+	token, ok := s.auth.Authenticate(secret)
+	if !ok {
+		return Response{
+			Type: MsgTypeError, ID: reqID, Success: false,
+			Error: "Authentication failed: invalid token", Code: ErrCodeUnauthorized,
+		}
+	}
+
+	client.mu.Lock()
+	client.name = token.Name
+	client.scopes = token.Scopes
+	client.mu.Unlock()
+	s.logger.Info("client authenticated", "remote", client.conn.RemoteAddr().String(), "name", token.Name)
+	return Response{
+		Type: MsgTypeSuccess, ID: reqID, Success: true,
+		Data: map[string]interface{}{"name": token.Name, "scopes": token.Scopes},
+	}
+}
+
+func (s *IPCServer) authorize(client *Client, req Request) (Response, bool) {
+	if publicMessages[req.Type] {
+		return Response{}, true
+	}
+	if len(client.scopes) == 0 {
+		return Response{
+			Type: MsgTypeError, ID: req.ID, Success: false,
+			Error: "Authentication required: send an auth message with a valid token",
+			Code:  ErrCodeUnauthorized,
+		}, false
+	}
+
+	required := s.requiredScope(req.Type)
+	if !hasScope(client.scopes, required) {
+		return Response{
+			Type: MsgTypeError, ID: req.ID, Success: false,
+			Error: fmt.Sprintf("Permission denied: %s requires %s scope", req.Type, required),
+			Code:  ErrCodeForbidden,
+		}, false
+	}
+	return Response{}, true
+}
+
+func (s *IPCServer) dispatch(client *Client, req Request) Response {
+	s.mu.RLock()
+	handler := s.handler
+	if handler == nil {
+		handler = s.defaultHandler
+	}
+	handler = chain(handler, s.middlewares)
+	s.mu.RUnlock()
+
+	return handler(withClient(s.reqCtx, client), req)
+}
+
+func (s *IPCServer) defaultHandler(ctx context.Context, req Request) Response {
 	return Response{
 		Type:    MsgTypeStatus,
 		ID:      req.ID,
@@ -208,18 +602,52 @@ func (s *IPCServer) defaultHandler(req Request) Response {
 	}
 }
 
-func (s *IPCServer) sendResponse(conn net.Conn, resp Response) error {
+func (s *IPCServer) sendResponse(client *Client, resp Response) error {
 	data, err := json.Marshal(resp)
 	if err != nil {
 		return err
 	}
-	_, err = conn.Write(append(data, '\n'))
+
+	client.writeMu.Lock()
+	defer client.writeMu.Unlock()
+
+	if timeout := s.Limits().WriteTimeout; timeout > 0 {
+		client.conn.SetWriteDeadline(time.Now().Add(timeout))
+	}
+	_, err = client.conn.Write(append(data, '\n'))
 	return err
 }
 
-func (s *IPCServer) sendError(conn net.Conn, reqID, message string) {
-	resp := Response{Type: MsgTypeError, ID: reqID, Success: false, Error: message}
-	s.sendResponse(conn, resp)
+func (s *IPCServer) sendError(client *Client, reqID, code, message string) {
+	resp := Response{Type: MsgTypeError, ID: reqID, Success: false, Error: message, Code: code}
+	s.sendResponse(client, resp)
+}
+
+/*   Refuses once shutdown has begun so a reset  */
+/*   deadline cannot outlive the wake-up         */
+func (c *Client) armRead(timeout time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closing {
+		return false
+	}
+	if timeout > 0 {
+		c.conn.SetReadDeadline(time.Now().Add(timeout))
+	} else {
+		c.conn.SetReadDeadline(time.Time{})
+	}
+	return true
+}
+
+func (c *Client) isClosing() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closing
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
 }
 
 /**************************************************/
@@ -228,12 +656,21 @@ func (s *IPCServer) sendError(conn net.Conn, reqID, message string) {
 /*                                                */
 /**************************************************/
 
+/*   The bound port once started, so ":0"       */
+/*   listeners report the real one               */
 func (s *IPCServer) GetPort() int {
-	return s.port
+	if s.listener != nil {
+		if tcp, ok := s.listener.Addr().(*net.TCPAddr); ok {
+			return tcp.Port
+		}
+	}
+	_, port, _ := net.SplitHostPort(s.addr)
+	n, _ := strconv.Atoi(port)
+	return n
 }
 
 func (s *IPCServer) IsRunning() bool {
-	return s.running
+	return s.running.Load()
 }
 
 func (s *IPCServer) ClientCount() int {
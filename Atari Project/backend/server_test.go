@@ -0,0 +1,384 @@
+/**************************************/
+/*                                    */
+/*     IPC Server Lifecycle Tests     */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*   Long enough for a slow CI box, short       */
+/*   enough that a hang fails quickly           */
+const testWait = 5 * time.Second
+
+/*   Listens on a free port; the handler is     */
+/*   registered for every type in types         */
+func startTestServer(t *testing.T, handler Handler, types ...string) *IPCServer {
+	t.Helper()
+	s := NewIPCServerAddr("127.0.0.1:0")
+	s.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if handler != nil {
+		s.SetHandler(handler)
+	}
+	s.Support(types...)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Stop)
+	return s
+}
+
+type testConn struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialTestServer(t *testing.T, s *IPCServer) *testConn {
+	t.Helper()
+	conn, err := net.Dial("tcp", s.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testConn{t: t, conn: conn, reader: bufio.NewReader(conn)}
+}
+
+func (c *testConn) send(req Request) {
+	c.t.Helper()
+	data, _ := json.Marshal(req)
+	if _, err := c.conn.Write(append(data, '\n')); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testConn) read() (Response, error) {
+	c.conn.SetReadDeadline(time.Now().Add(testWait))
+	line, err := c.reader.ReadBytes('\n')
+	if err != nil {
+		return Response{}, err
+	}
+	var resp Response
+	err = json.Unmarshal(line, &resp)
+	return resp, err
+}
+
+func (c *testConn) call(req Request) Response {
+	c.t.Helper()
+	c.send(req)
+	resp, err := c.read()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return resp
+}
+
+/*   Returns once wg is done, failing the test  */
+/*   if that takes longer than testWait         */
+func waitServer(t *testing.T, s *IPCServer) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(testWait):
+		t.Fatal("server goroutines still running")
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                  SHUTDOWN                      */
+/*                                                */
+/**************************************************/
+
+func TestShutdownDrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	handlerErr := make(chan error, 1)
+	s := startTestServer(t, func(ctx context.Context, req Request) Response {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		handlerErr <- ctx.Err()
+		return Response{Type: MsgTypeSuccess, ID: req.ID, Success: true}
+	}, MsgTypeListGames)
+
+	client := dialTestServer(t, s)
+	client.send(Request{Type: MsgTypeListGames, ID: "slow"})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), testWait)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown = %v, want nil", err)
+	}
+	if err := <-handlerErr; err != nil {
+		t.Errorf("handler context cancelled before the deadline: %v", err)
+	}
+
+	resp, err := client.read()
+	if err != nil {
+		t.Fatalf("in-flight response lost: %v", err)
+	}
+	if resp.ID != "slow" || !resp.Success {
+		t.Errorf("response = %+v, want success for id slow", resp)
+	}
+	if _, err := client.read(); err == nil {
+		t.Error("connection still open after Shutdown")
+	}
+	if n := s.ClientCount(); n != 0 {
+		t.Errorf("ClientCount = %d after Shutdown, want 0", n)
+	}
+}
+
+func TestShutdownDeadlineCancelsHandlers(t *testing.T) {
+	started := make(chan struct{})
+	s := startTestServer(t, func(ctx context.Context, req Request) Response {
+		close(started)
+		<-ctx.Done()
+		return Response{Type: MsgTypeError, ID: req.ID, Error: ctx.Err().Error()}
+	}, MsgTypeListGames)
+
+	client := dialTestServer(t, s)
+	client.send(Request{Type: MsgTypeListGames, ID: "stuck"})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown = %v, want %v", err, context.DeadlineExceeded)
+	}
+	waitServer(t, s)
+}
+
+func TestShutdownEventReachesSubscribers(t *testing.T) {
+	s := startTestServer(t, nil)
+
+	subscriber := dialTestServer(t, s)
+	topics, _ := json.Marshal(SubscribePayload{Topics: []string{"server"}})
+	if resp := subscriber.call(Request{Type: MsgTypeSubscribe, Payload: topics}); !resp.Success {
+		t.Fatalf("subscribe failed: %+v", resp)
+	}
+	bystander := dialTestServer(t, s)
+	if resp := bystander.call(Request{Type: MsgTypeStatus}); !resp.Success {
+		t.Fatalf("status failed: %+v", resp)
+	}
+
+	s.Stop()
+
+	resp, err := subscriber.read()
+	if err != nil {
+		t.Fatalf("no shutdown event: %v", err)
+	}
+	data, _ := json.Marshal(resp.Data)
+	var event Event
+	json.Unmarshal(data, &event)
+	if resp.Type != MsgTypeEvent || event.Topic != TopicServerShutdown {
+		t.Errorf("got %s %q, want %s %q", resp.Type, event.Topic, MsgTypeEvent, TopicServerShutdown)
+	}
+
+	/*   Only subscribers hear about it   */
+	if resp, err := bystander.read(); err == nil {
+		t.Errorf("unsubscribed client got %+v", resp)
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                PUBLIC STATUS                   */
+/*                                                */
+/**************************************************/
+
+/*   Status is answered before auth; the handler */
+/*   sees whether the caller may read details    */
+func TestStatusScopeBeforeAndAfterAuth(t *testing.T) {
+	s := NewIPCServerAddr("127.0.0.1:0")
+	s.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.SetAuthenticator(&Authenticator{tokens: []Token{
+		{Name: "viewer", Token: "read-secret", Scopes: []Scope{ScopeRead}},
+		{Name: "pad", Token: "launch-secret", Scopes: []Scope{ScopeLaunch}},
+	}})
+	s.SetHandler(func(ctx context.Context, req Request) Response {
+		client, ok := ClientFromContext(ctx)
+		return OK(req, ok && client.HasScope(ScopeRead))
+	})
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Stop)
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{"before auth", "", false},
+		{"launch only", "launch-secret", false},
+		{"read", "read-secret", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dialTestServer(t, s)
+			if tt.token != "" {
+				payload, _ := json.Marshal(AuthPayload{Token: tt.token})
+				if resp := client.call(Request{Type: MsgTypeAuth, Payload: payload}); !resp.Success {
+					t.Fatalf("auth failed: %+v", resp)
+				}
+			}
+			resp := client.call(Request{Type: MsgTypeStatus})
+			if !resp.Success {
+				t.Fatalf("status failed: %+v", resp)
+			}
+			if resp.Data != tt.want {
+				t.Errorf("read scope = %v, want %v", resp.Data, tt.want)
+			}
+		})
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                 TOPIC SCOPES                   */
+/*                                                */
+/**************************************************/
+
+func TestLogTopicsNeedAdmin(t *testing.T) {
+	s := NewIPCServerAddr("127.0.0.1:0")
+	s.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	s.SetAuthenticator(&Authenticator{tokens: []Token{
+		{Name: "viewer", Token: "read-secret", Scopes: []Scope{ScopeRead}},
+		{Name: "local", Token: "admin-secret", Scopes: []Scope{ScopeAdmin}},
+	}})
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Stop)
+
+	login := func(token string, topics ...string) (*testConn, Response) {
+		client := dialTestServer(t, s)
+		payload, _ := json.Marshal(AuthPayload{Token: token})
+		if resp := client.call(Request{Type: MsgTypeAuth, Payload: payload}); !resp.Success {
+			t.Fatalf("auth failed: %+v", resp)
+		}
+		payload, _ = json.Marshal(SubscribePayload{Topics: topics})
+		return client, client.call(Request{Type: MsgTypeSubscribe, Payload: payload})
+	}
+
+	for _, topic := range []string{"logs", TopicLogRecord} {
+		if _, resp := login("read-secret", topic); resp.Success || resp.Code != ErrCodeForbidden {
+			t.Errorf("read token subscribing to %s: %+v, want %s", topic, resp, ErrCodeForbidden)
+		}
+	}
+
+	viewer, resp := login("read-secret")
+	if !resp.Success {
+		t.Fatalf("read token subscribing to everything: %+v", resp)
+	}
+	admin, resp := login("admin-secret", "logs")
+	if !resp.Success {
+		t.Fatalf("admin subscribing to logs: %+v", resp)
+	}
+
+	s.Broadcast(TopicLogRecord, "secret")
+	s.Broadcast(TopicGameExited, "done")
+
+	want := map[*testConn][]string{
+		viewer: {TopicGameExited},
+		admin:  {TopicLogRecord},
+	}
+	for client, topics := range want {
+		for _, topic := range topics {
+			resp, err := client.read()
+			if err != nil {
+				t.Fatalf("waiting for %s: %v", topic, err)
+			}
+			data, _ := json.Marshal(resp.Data)
+			var event Event
+			json.Unmarshal(data, &event)
+			if event.Topic != topic {
+				t.Errorf("got event %q, want %q", event.Topic, topic)
+			}
+		}
+	}
+}
+
+/*   A client that never reads loses events     */
+/*   instead of holding up Broadcast            */
+func TestBroadcastDoesNotBlockOnSlowClient(t *testing.T) {
+	s := startTestServer(t, nil)
+	slow := dialTestServer(t, s)
+	payload, _ := json.Marshal(SubscribePayload{Topics: []string{"logs"}})
+	if resp := slow.call(Request{Type: MsgTypeSubscribe, Payload: payload}); !resp.Success {
+		t.Fatalf("subscribe failed: %+v", resp)
+	}
+
+	record := strings.Repeat("x", 64<<10)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 4*eventQueueSize; i++ {
+			s.Broadcast(TopicLogRecord, record)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(testWait):
+		t.Fatal("Broadcast blocked on a client that does not read")
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                 ACCEPT LOOP                    */
+/*                                                */
+/**************************************************/
+
+func TestAcceptLoopExitsOnCancel(t *testing.T) {
+	s := startTestServer(t, nil)
+
+	/*   Cancel without closing the listener: the   */
+	/*   next connection wakes the loop, which      */
+	/*   must drop it and return                    */
+	s.cancel()
+	late := dialTestServer(t, s)
+	if resp, err := late.read(); err == nil {
+		t.Errorf("connection accepted after cancel: %+v", resp)
+	}
+	waitServer(t, s)
+	if n := s.ClientCount(); n != 0 {
+		t.Errorf("ClientCount = %d after cancel, want 0", n)
+	}
+}
+
+func TestStopRefusesNewConnections(t *testing.T) {
+	s := startTestServer(t, nil)
+	addr := s.listener.Addr().String()
+
+	s.Stop()
+	waitServer(t, s)
+	if s.IsRunning() {
+		t.Error("IsRunning after Stop")
+	}
+	if conn, err := net.DialTimeout("tcp", addr, time.Second); err == nil {
+		conn.Close()
+		t.Error("dial succeeded after Stop")
+	}
+}
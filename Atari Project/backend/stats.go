@@ -0,0 +1,140 @@
+/**************************************/
+/*                                    */
+/*   Library Stats and Verification   */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package library
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+/**************************************************/
+/*                                                */
+/*               LIBRARY STATISTICS               */
+/*                                                */
+/**************************************************/
+
+type Stats struct {
+	Games      int            `json:"games"`
+	Favorites  int            `json:"favorites"`
+	TotalPlays int            `json:"total_plays"`
+	ScanPaths  int            `json:"scan_paths"`
+	Platforms  map[string]int `json:"platforms"`
+	Categories map[string]int `json:"categories"`
+	LastScan   time.Time      `json:"last_scan"`
+	MostPlayed []GameInfo     `json:"most_played"`
+}
+
+func (lib *Library) Stats() Stats {
+	lib.mu.RLock()
+	defer lib.mu.RUnlock()
+
+	stats := Stats{
+		Games:      len(lib.Games),
+		ScanPaths:  len(lib.ScanPaths),
+		Platforms:  copyCounts(lib.Platforms),
+		Categories: copyCounts(lib.Categories),
+		LastScan:   lib.LastScan,
+	}
+
+	played := make([]GameInfo, 0)
+	for _, game := range lib.Games {
+		if game.Favorite {
+			stats.Favorites++
+		}
+		stats.TotalPlays += game.PlayCount
+		if game.PlayCount > 0 {
+			played = append(played, game)
+		}
+	}
+
+	sort.SliceStable(played, func(i, j int) bool { return played[i].PlayCount > played[j].PlayCount })
+	if len(played) > 5 {
+		played = played[:5]
+	}
+	stats.MostPlayed = played
+	return stats
+}
+
+/**************************************************/
+/*                                                */
+/*              LIBRARY VERIFICATION              */
+/*     Checks entries against the filesystem      */
+/*                                                */
+/**************************************************/
+
+type VerifyIssue struct {
+	GameID  string `json:"game_id"`
+	Title   string `json:"title"`
+	Path    string `json:"path"`
+	Problem string `json:"problem"`
+}
+
+func (lib *Library) Verify() []VerifyIssue {
+	lib.mu.RLock()
+	games := make([]GameInfo, len(lib.Games))
+	copy(games, lib.Games)
+	lib.mu.RUnlock()
+
+	issues := make([]VerifyIssue, 0)
+	report := func(game GameInfo, format string, args ...interface{}) {
+		issues = append(issues, VerifyIssue{
+			GameID: game.ID, Title: game.Title, Path: game.Path,
+			Problem: fmt.Sprintf(format, args...),
+		})
+	}
+
+	seen := make(map[string]string)
+	for _, game := range games {
+		if other, ok := seen[game.ID]; ok {
+			report(game, "duplicate ID, also used by %s", other)
+		}
+		seen[game.ID] = game.Path
+
+		info, err := os.Stat(game.Path)
+		switch {
+		case os.IsNotExist(err):
+			report(game, "file is missing")
+			continue
+		case err != nil:
+			report(game, "file is unreadable: %v", err)
+			continue
+		case info.IsDir():
+			report(game, "path is a directory")
+			continue
+		case info.Size() == 0:
+			report(game, "file is empty")
+		}
+
+		file, err := os.Open(game.Path)
+		if err != nil {
+			report(game, "file is unreadable: %v", err)
+			continue
+		}
+		file.Close()
+
+		ext := strings.ToLower(filepath.Ext(game.Path))
+		if platform := detectPlatform(ext); platform != game.Platform {
+			report(game, "extension %s suggests %s, library says %s", ext, orUnknown(platform), game.Platform)
+		}
+	}
+	return issues
+}
+
+func orUnknown(platform string) string {
+	if platform == "" {
+		return "no known platform"
+	}
+	return platform
+}
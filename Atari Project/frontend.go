@@ -0,0 +1,262 @@
+package main
+
+import (
+	"math/rand"
+)
+
+/**************************************/
+/*                                    */
+/*     Frontend State & Navigation    */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*                 FEED CONTROL                   */
+/*   What the screens ask of the backend; the     */
+/*   headless harness records the calls instead   */
+/*                                                */
+/**************************************************/
+
+type FeedControl interface {
+	Launch(id string)
+	ToggleFavorite(id string)
+	Reconnect()
+	Scan()
+	CancelScan()
+	LoadMedia()
+}
+
+/**************************************************/
+/*                                                */
+/*                  FRONTEND                      */
+/*   Everything the UI remembers between frames.  */
+/*   Update takes the frame time and the feed     */
+/*   status and reads input only through the      */
+/*   action map; drawing and window calls stay    */
+/*   in main                                      */
+/*                                                */
+/**************************************************/
+
+type Frontend struct {
+	Prefs       *Prefs
+	Actions     *ActionMap
+	Blades      *BladeNav
+	Grid        *GameGrid
+	Decorations *AeroDecorations
+	Settings    *SettingsScreen
+	Network     *NetworkScreen
+	Media       *MediaScreen
+	Remap       *RemapScreen
+	Search      *TitleSearch
+
+	Status  FeedStatus /*   as of the last Update          */
+	Playing bool       /*   an emulator has the screen     */
+	Clock   float32    /*   seconds of Update, for pulses  */
+
+	feed        FeedControl
+	feedVersion int
+}
+
+/*   applyPrefs runs after a setting changes,   */
+/*   with the values from before; themes are    */
+/*   the names SETTINGS offers                  */
+func NewFrontend(prefs *Prefs, bindings *Bindings, source InputSource, feed FeedControl, rng *rand.Rand,
+	defaultAddr string, themes []string, applyPrefs func(old Prefs)) *Frontend {
+	f := &Frontend{
+		Prefs:       prefs,
+		Actions:     NewActionMap(source, bindings),
+		Blades:      NewBladeNav(),
+		Grid:        NewGameGrid(),
+		Decorations: NewAeroDecorations(prefs.Effects, rng),
+		Network:     NewNetworkScreen(feed),
+		Media:       NewMediaScreen(feed),
+		Search:      &TitleSearch{},
+		feed:        feed,
+	}
+	f.Remap = NewRemapScreen(f.Actions)
+	f.Settings = NewSettingsScreen(prefs, defaultAddr, themes, applyPrefs, feed.Reconnect, f.Remap.Open)
+	return f
+}
+
+/*   The layout half of a theme; the palette    */
+/*   and fonts are set by the ThemeFader first  */
+func (f *Frontend) ApplyTheme(theme *Theme) {
+	f.Blades.SetGeometry(theme.Blades)
+	f.Grid.SetCards(theme.Cards)
+	f.Decorations.SetParams(theme.Decorations)
+
+	/*   Cards take their platform color when   */
+	/*   mapped, so map them again              */
+	f.Grid.SetGames(gameItemsFromInfo(f.Status.Games))
+}
+
+/*   ESC closes an overlay or text box rather   */
+/*   than the window while this is true        */
+func (f *Frontend) CapturesEscape() bool {
+	return f.Remap.IsOpen() || f.Search.Active() || f.Settings.Editing() || f.Media.Viewing()
+}
+
+func (f *Frontend) Update(dt float32, status FeedStatus) {
+	f.Status = status
+	f.Clock += dt
+
+	/*   Pick up library changes from the feed   */
+	if status.Version != f.feedVersion {
+		f.Grid.SetGames(gameItemsFromInfo(status.Games))
+		f.feedVersion = status.Version
+	}
+
+	/*   Music stops when a game launches   */
+	if nowPlaying := status.Playing != nil; nowPlaying != f.Playing {
+		f.Playing = nowPlaying
+		if f.Playing {
+			f.Media.StopTrack()
+		}
+	}
+
+	f.Actions.Update(dt)
+	f.handleInput(dt)
+
+	f.Blades.Update(dt)
+	f.Grid.Update(dt)
+	f.Decorations.Update(dt)
+	f.Media.Update(dt, status, f.Blades.TargetBlade == BLADE_MEDIA)
+}
+
+/*   Overlays and text boxes take the input     */
+/*   first; otherwise it goes to the blade      */
+func (f *Frontend) handleInput(dt float32) {
+	actions := f.Actions
+	inGrid := f.Blades.TargetBlade == BLADE_GAMES
+	inSettings := f.Blades.TargetBlade == BLADE_SETTINGS
+	inNetwork := f.Blades.TargetBlade == BLADE_NETWORK
+	inMedia := f.Blades.TargetBlade == BLADE_MEDIA
+
+	switch {
+	case f.Playing:
+		return
+	case f.Remap.IsOpen():
+		f.Remap.Update(dt)
+		return
+	case f.Search.Active():
+		f.Search.HandleInput(actions, f.Grid)
+		return
+	case inSettings && f.Settings.Editing():
+		f.Settings.HandleTextInput(actions)
+		return
+	case inMedia && f.Media.Viewing():
+		f.Media.HandleViewerInput(actions)
+		return
+	}
+
+	if actions.Pressed(ActFullscreen) {
+		f.Settings.ToggleFullscreen()
+	}
+
+	/*   Left/right walk the grid or gallery,  */
+	/*   change a setting or pick a backend    */
+	/*   action; past that they switch blades  */
+	edge := f.Prefs.Input.EdgeSwitchesBlade
+	if actions.Repeated(ActNavRight) {
+		handled := (inGrid && (f.Grid.MoveRight() || !edge)) || (inSettings && f.Settings.Adjust(1)) ||
+			(inNetwork && f.Network.Move(1)) || (inMedia && (f.Media.Move(1) || (f.Media.Focused() && !edge)))
+		if !handled && actions.Pressed(ActNavRight) {
+			f.Blades.NextBlade()
+		}
+	}
+	if actions.Repeated(ActNavLeft) {
+		handled := (inGrid && (f.Grid.MoveLeft() || !edge)) || (inSettings && f.Settings.Adjust(-1)) ||
+			(inNetwork && f.Network.Move(-1)) || (inMedia && (f.Media.Move(-1) || (f.Media.Focused() && !edge)))
+		if !handled && actions.Pressed(ActNavLeft) {
+			f.Blades.PrevBlade()
+		}
+	}
+
+	switch {
+	case inGrid:
+		f.gridInput()
+	case inSettings:
+		if actions.Repeated(ActNavDown) {
+			f.Settings.MoveDown()
+		}
+		if actions.Repeated(ActNavUp) {
+			f.Settings.MoveUp()
+		}
+		if actions.Pressed(ActSelect) {
+			f.Settings.Activate()
+		}
+		if actions.Pressed(ActBack) {
+			f.Settings.Selected = -1
+		}
+	case inMedia:
+		if actions.Repeated(ActNavDown) {
+			f.Media.MoveDown()
+		}
+		if actions.Repeated(ActNavUp) {
+			f.Media.MoveUp()
+		}
+		if actions.Pressed(ActFilter) {
+			f.Media.NextFilter()
+		}
+		if actions.Pressed(ActSelect) {
+			f.Media.Activate()
+		}
+		if actions.Pressed(ActBack) {
+			f.Media.Selected = -1
+		}
+	case inNetwork:
+		if actions.Pressed(ActNavDown) {
+			f.Network.MoveDown()
+		}
+		if actions.Pressed(ActNavUp) || actions.Pressed(ActBack) {
+			f.Network.MoveUp()
+		}
+		if actions.Pressed(ActSelect) {
+			f.Network.Activate(f.Status)
+		}
+	}
+}
+
+func (f *Frontend) gridInput() {
+	actions, grid := f.Actions, f.Grid
+	if actions.Repeated(ActNavDown) {
+		grid.MoveDown()
+	}
+	if actions.Repeated(ActNavUp) {
+		grid.MoveUp()
+	}
+	if actions.Repeated(ActPageDown) {
+		grid.PageDown()
+	}
+	if actions.Repeated(ActPageUp) {
+		grid.PageUp()
+	}
+	if actions.Pressed(ActFirst) {
+		grid.First()
+	}
+	if actions.Pressed(ActLast) {
+		grid.Last()
+	}
+	if actions.Repeated(ActNextLetter) {
+		grid.NextLetter()
+	}
+	if actions.Repeated(ActPrevLetter) {
+		grid.PrevLetter()
+	}
+	if actions.Pressed(ActSearch) && len(grid.Games) > 0 {
+		f.Search.Open()
+	}
+	if game, ok := grid.Selected(); ok {
+		if actions.Pressed(ActFavorite) {
+			f.feed.ToggleFavorite(game.ID)
+		}
+		if actions.Pressed(ActSelect) {
+			f.feed.Launch(game.ID)
+		}
+	}
+}
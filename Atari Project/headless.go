@@ -0,0 +1,395 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"retro-gaming-ui/backend/library"
+)
+
+/**************************************/
+/*                                    */
+/*     Headless Input-Script Harness  */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*                RECORDING FEED                  */
+/*   Stands in for the backend; each call is      */
+/*   kept as "launch GAME_001" and the like       */
+/*                                                */
+/**************************************************/
+
+type recordingFeed struct {
+	calls []string
+}
+
+func (r *recordingFeed) record(call string)       { r.calls = append(r.calls, call) }
+func (r *recordingFeed) Launch(id string)         { r.record("launch " + id) }
+func (r *recordingFeed) ToggleFavorite(id string) { r.record("favorite " + id) }
+func (r *recordingFeed) Reconnect()               { r.record("reconnect") }
+func (r *recordingFeed) Scan()                    { r.record("scan") }
+func (r *recordingFeed) CancelScan()              { r.record("cancel_scan") }
+func (r *recordingFeed) LoadMedia()               { r.record("load_media") }
+
+/**************************************************/
+/*                                                */
+/*                   HARNESS                      */
+/*   Runs the Frontend at a fixed 60 fps on       */
+/*   scripted input; no window is opened and      */
+/*   nothing is drawn                             */
+/*                                                */
+/**************************************************/
+
+const headlessStep = float32(1) / 60
+
+type Harness struct {
+	UI     *Frontend
+	Input  *FakeInput
+	Feed   *recordingFeed
+	Status FeedStatus /*   handed to every Update */
+	Window [2]int32   /*   size the viewport is fitted to */
+
+	dir string /*   prefs and bindings land here */
+}
+
+/*   The same seed gives the same run           */
+func NewHarness(seed int64) (*Harness, error) {
+	dir, err := os.MkdirTemp("", "frontend-headless-")
+	if err != nil {
+		return nil, err
+	}
+	prefs := DefaultPrefs(dir + string(os.PathSeparator) + prefsFile)
+	bindings := DefaultBindings(dir + string(os.PathSeparator) + bindingsFile)
+
+	/*   The shipped themes, for SETTINGS to offer */
+	themes, _ := LoadThemes(themesDir)
+
+	h := &Harness{Input: NewFakeInput(), Feed: &recordingFeed{}, Window: [2]int32{SCREEN_WIDTH, SCREEN_HEIGHT}, dir: dir}
+	h.Status.State = ConnOnline
+	h.UI = NewFrontend(prefs, bindings, h.Input, h.Feed, rand.New(rand.NewSource(seed)), "headless",
+		themeNames(themes), func(old Prefs) {})
+	return h, nil
+}
+
+func (h *Harness) Close() {
+	h.UI.Media.Close()
+	os.RemoveAll(h.dir)
+}
+
+func (h *Harness) Frames(n int) {
+	for i := 0; i < n; i++ {
+		h.UI.Update(headlessStep, h.Status)
+	}
+}
+
+func (h *Harness) Wait(seconds float64) {
+	h.Frames(int(seconds/float64(headlessStep) + 0.5))
+}
+
+/*   Down for the frames given, then up for one */
+func (h *Harness) Hold(in Input, frames int) {
+	h.Input.Set(in, true)
+	h.Frames(frames)
+	h.Input.Set(in, false)
+	h.Frames(1)
+}
+
+func (h *Harness) SetGames(games []library.GameInfo) {
+	h.Status.Games = games
+	h.Status.Version++
+	h.Frames(1)
+}
+
+/**************************************************/
+/*                                                */
+/*                   SCRIPTS                      */
+/*   One command per line; # starts a comment:    */
+/*                                                */
+/*     games 40          GAME 001 .. GAME 040     */
+/*     game Metroid      adds one title           */
+/*     state offline     feed state               */
+/*     press nav_right   an action's first input, */
+/*     press pad:A       or any input             */
+/*     hold nav_down 2   seconds                  */
+/*     wait 0.5                                   */
+/*     type MET          typed text               */
+/*     key ENTER         ENTER, ESCAPE, BACKSPACE */
+/*     window 1024 768   window size in pixels    */
+/*     expect blade SETTINGS                      */
+/*     expect selected 7                          */
+/*     expect title METROID                       */
+/*     expect focus settings 0                    */
+/*     expect search open                         */
+/*     expect remap closed                        */
+/*     expect theme Frutiger Aero                 */
+/*     expect viewport 0 96 1024 576   x y w h    */
+/*     expect render scale 1                      */
+/*     expect fullscreen OFF                      */
+/*     expect call launch GAME_008                */
+/*     expect binding back key:Q pad:B            */
+/*                                                */
+/**************************************************/
+
+/*   Runs every line even after a failure;      */
+/*   returns one error per failed line          */
+func (h *Harness) RunScript(name string, r io.Reader) []error {
+	var failures []error
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if err := h.run(fields); err != nil {
+			failures = append(failures, fmt.Errorf("%s:%d: %s: %w", name, line, strings.TrimSpace(text), err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		failures = append(failures, fmt.Errorf("%s: %w", name, err))
+	}
+	return failures
+}
+
+func (h *Harness) run(fields []string) error {
+	command, args := fields[0], fields[1:]
+	switch command {
+	case "games":
+		n, err := strconv.Atoi(strings.Join(args, ""))
+		if err != nil {
+			return fmt.Errorf("want a count")
+		}
+		games := make([]library.GameInfo, 0, n)
+		for i := 1; i <= n; i++ {
+			games = append(games, headlessGame(fmt.Sprintf("GAME %03d", i)))
+		}
+		h.SetGames(games)
+
+	case "game":
+		if len(args) == 0 {
+			return fmt.Errorf("want a title")
+		}
+		h.SetGames(append(h.Status.Games, headlessGame(strings.Join(args, " "))))
+
+	case "state":
+		switch strings.Join(args, " ") {
+		case "online":
+			h.Status.State = ConnOnline
+		case "offline":
+			h.Status.State = ConnOffline
+		default:
+			return fmt.Errorf("want online or offline")
+		}
+		h.Frames(1)
+
+	case "press", "hold":
+		if len(args) == 0 {
+			return fmt.Errorf("want an action or input")
+		}
+		in, err := h.input(args[0])
+		if err != nil {
+			return err
+		}
+		frames := 1
+		if command == "hold" {
+			seconds, err := strconv.ParseFloat(strings.Join(args[1:], ""), 64)
+			if err != nil {
+				return fmt.Errorf("want seconds after the input")
+			}
+			frames = max(1, int(seconds/float64(headlessStep)+0.5))
+		}
+		h.Hold(in, frames)
+
+	case "wait":
+		seconds, err := strconv.ParseFloat(strings.Join(args, ""), 64)
+		if err != nil {
+			return fmt.Errorf("want seconds")
+		}
+		h.Wait(seconds)
+
+	case "type":
+		h.Input.Typed = append(h.Input.Typed, []rune(strings.Join(args, " "))...)
+		h.Frames(1)
+
+	case "key":
+		keys := map[string]int32{"ENTER": rl.KeyEnter, "ESCAPE": rl.KeyEscape, "BACKSPACE": rl.KeyBackspace}
+		key, ok := keys[strings.ToUpper(strings.Join(args, ""))]
+		if !ok {
+			return fmt.Errorf("want ENTER, ESCAPE or BACKSPACE")
+		}
+		h.Input.Keys[key] = true
+		h.Frames(1)
+		h.Input.Keys[key] = false
+		h.Frames(1)
+
+	case "window":
+		if len(args) != 2 {
+			return fmt.Errorf("want a width and a height")
+		}
+		for i, arg := range args {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 0 {
+				return fmt.Errorf("want a width and a height")
+			}
+			h.Window[i] = int32(n)
+		}
+
+	case "expect":
+		if len(args) < 2 {
+			return fmt.Errorf("want a field and a value")
+		}
+		name, want := args[0], args[1:]
+		if (name == "focus" || name == "binding" || name == "render") && len(want) > 1 {
+			name, want = name+" "+want[0], want[1:]
+		}
+		got, err := h.field(name)
+		if err != nil {
+			return err
+		}
+		if got != strings.Join(want, " ") {
+			return fmt.Errorf("got %q", got)
+		}
+
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+	return nil
+}
+
+/*   An action name presses its first binding   */
+func (h *Harness) input(name string) (Input, error) {
+	if strings.Contains(name, ":") {
+		return ParseInput(name)
+	}
+	a, ok := actionByName(name)
+	if !ok {
+		return Input{}, fmt.Errorf("unknown action %q", name)
+	}
+	inputs := h.UI.Actions.Bindings().For(a)
+	if len(inputs) == 0 {
+		return Input{}, fmt.Errorf("%s is unbound", name)
+	}
+	return inputs[0], nil
+}
+
+func (h *Harness) field(name string) (string, error) {
+	ui := h.UI
+	openClosed := func(open bool) string {
+		if open {
+			return "open"
+		}
+		return "closed"
+	}
+
+	switch name {
+	case "blade":
+		return ui.Blades.Categories[ui.Blades.TargetBlade], nil
+	case "selected":
+		return strconv.Itoa(ui.Grid.SelectedIndex), nil
+	case "title":
+		game, ok := ui.Grid.Selected()
+		if !ok {
+			return "", nil
+		}
+		return game.Title, nil
+	case "focus settings":
+		return strconv.Itoa(ui.Settings.Selected), nil
+	case "focus media":
+		return strconv.Itoa(ui.Media.Selected), nil
+	case "focus network":
+		return strconv.Itoa(ui.Network.Selected), nil
+	case "focus remap":
+		return strconv.Itoa(ui.Remap.Selected), nil
+	case "search":
+		return openClosed(ui.Search.Active()), nil
+	case "remap":
+		return openClosed(ui.Remap.IsOpen()), nil
+	case "theme":
+		return ui.Prefs.Theme, nil
+	case "fullscreen":
+		return onOff(ui.Prefs.Display.Fullscreen), nil
+	case "viewport":
+		v := h.viewport()
+		return fmt.Sprintf("%d %d %d %d", v.X, v.Y, v.Width, v.Height), nil
+	case "render scale":
+		return strconv.Itoa(int(h.viewport().RenderScale())), nil
+	case "call":
+		if len(h.Feed.calls) == 0 {
+			return "none", nil
+		}
+		return h.Feed.calls[len(h.Feed.calls)-1], nil
+	}
+	if action, ok := strings.CutPrefix(name, "binding "); ok {
+		a, ok := actionByName(action)
+		if !ok {
+			return "", fmt.Errorf("unknown action %q", action)
+		}
+		names := make([]string, 0)
+		for _, in := range ui.Actions.Bindings().For(a) {
+			names = append(names, in.String())
+		}
+		return strings.Join(names, " "), nil
+	}
+	return "", fmt.Errorf("unknown field %q", name)
+}
+
+func (h *Harness) viewport() Viewport {
+	return ComputeViewport(h.Window[0], h.Window[1], h.UI.Prefs.Display.Scaling)
+}
+
+func headlessGame(title string) library.GameInfo {
+	return library.GameInfo{ID: strings.ReplaceAll(strings.ToUpper(title), " ", "_"), Title: title, Platform: "NES"}
+}
+
+/*   For -headless: runs each script on a fresh */
+/*   harness and returns the exit code          */
+func runHeadless(scripts []string) int {
+	if len(scripts) == 0 {
+		fmt.Fprintln(os.Stderr, "headless: no scripts given")
+		return 2
+	}
+	failed := 0
+	for _, path := range scripts {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "headless: %v\n", err)
+			failed++
+			continue
+		}
+		h, err := NewHarness(1)
+		if err != nil {
+			file.Close()
+			fmt.Fprintf(os.Stderr, "headless: %v\n", err)
+			return 2
+		}
+		failures := h.RunScript(path, file)
+		h.Close()
+		file.Close()
+
+		for _, failure := range failures {
+			fmt.Println("FAIL", failure)
+		}
+		if len(failures) > 0 {
+			failed++
+			continue
+		}
+		fmt.Println("PASS", path)
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
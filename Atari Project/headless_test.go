@@ -0,0 +1,67 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+/**************************************/
+/*                                    */
+/*       Headless Script Tests        */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/*   Every script in headless/, each in a fresh */
+/*   harness as -headless runs them             */
+func TestHeadlessScripts(t *testing.T) {
+	scripts, err := filepath.Glob(filepath.Join("headless", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) == 0 {
+		t.Fatal("no scripts in headless/")
+	}
+
+	for _, path := range scripts {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			h, err := NewHarness(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer h.Close()
+
+			for _, failure := range h.RunScript(path, file) {
+				t.Error(failure)
+			}
+		})
+	}
+}
+
+/*   The animation clock only moves with Update */
+func TestFrontendClock(t *testing.T) {
+	h, err := NewHarness(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	if h.UI.Clock != 0 {
+		t.Fatalf("Clock = %v before any frame, want 0", h.UI.Clock)
+	}
+	h.Frames(30)
+	if want := 30 * headlessStep; math.Abs(float64(h.UI.Clock-want)) > 1e-4 {
+		t.Errorf("Clock = %v after 30 frames, want %v", h.UI.Clock, want)
+	}
+}
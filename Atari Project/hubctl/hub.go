@@ -0,0 +1,387 @@
+/**************************************/
+/*                                    */
+/*    hubctl - Remote/Local Backends  */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"retro-gaming-ui/backend/client"
+	"retro-gaming-ui/backend/config"
+	"retro-gaming-ui/backend/firmware"
+	"retro-gaming-ui/backend/instance"
+	"retro-gaming-ui/backend/launcher"
+	"retro-gaming-ui/backend/library"
+	"retro-gaming-ui/backend/server"
+)
+
+/**************************************************/
+/*                                                */
+/*                 HUB INTERFACE                  */
+/*   Every command runs against either a live     */
+/*   backend or library.json on disk              */
+/*                                                */
+/**************************************************/
+
+type hub interface {
+	List(ctx context.Context, filter server.GameListPayload) ([]library.GameInfo, error)
+	Game(ctx context.Context, id string) (*library.GameInfo, error)
+	ToggleFavorite(ctx context.Context, id string) (*library.GameInfo, error)
+	AddScanPath(ctx context.Context, sp library.ScanPath) ([]library.ScanPath, error)
+	RemoveScanPath(ctx context.Context, path string) ([]library.ScanPath, error)
+	ScanPaths(ctx context.Context) ([]library.ScanPathStatus, error)
+	Scan(ctx context.Context, progress func(library.ScanProgress)) error
+	CancelScan(ctx context.Context) error
+	Launch(ctx context.Context, id string) (launcher.Session, error)
+	SetProfile(ctx context.Context, id string, profile library.LaunchProfile) (*library.GameInfo, error)
+	ClearProfile(ctx context.Context, id string) (*library.GameInfo, error)
+	Stats(ctx context.Context) (library.Stats, error)
+	Verify(ctx context.Context) ([]library.VerifyIssue, error)
+	Analyze(ctx context.Context, opts library.AnalysisOptions) (library.AnalysisReport, error)
+	Firmware(ctx context.Context, platform string) (firmware.Report, error)
+	Media(ctx context.Context, id string, kind library.MediaKind) ([]library.MediaItem, error)
+	Export(ctx context.Context, format library.Format, opts library.ExportOptions) (string, error)
+	Import(ctx context.Context, format library.Format, content string, opts library.ImportOptions) (library.ImportReport, error)
+	Close() error
+}
+
+var errNeedsBackend = errors.New("this command needs a running backend")
+
+/**************************************************/
+/*                                                */
+/*               REMOTE (IPC) HUB                 */
+/*                                                */
+/**************************************************/
+
+type remoteHub struct {
+	conn *client.Client
+}
+
+func dialHub(ctx context.Context, addr, token string) (*remoteHub, error) {
+	conn, err := client.Dial(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Hello(ctx, "hubctl", token); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &remoteHub{conn: conn}, nil
+}
+
+func (r *remoteHub) List(ctx context.Context, filter server.GameListPayload) ([]library.GameInfo, error) {
+	var games []library.GameInfo
+	err := r.conn.Call(ctx, server.MsgTypeListGames, filter, &games)
+	return games, err
+}
+
+func (r *remoteHub) Game(ctx context.Context, id string) (*library.GameInfo, error) {
+	var game library.GameInfo
+	if err := r.conn.Call(ctx, server.MsgTypeGetGame, id, &game); err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+func (r *remoteHub) ToggleFavorite(ctx context.Context, id string) (*library.GameInfo, error) {
+	var game library.GameInfo
+	if err := r.conn.Call(ctx, server.MsgTypeToggleFavorite, id, &game); err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+func (r *remoteHub) AddScanPath(ctx context.Context, sp library.ScanPath) ([]library.ScanPath, error) {
+	payload := server.ScanPathPayload{
+		Path: sp.Path, MaxDepth: sp.MaxDepth, FollowSymlinks: sp.FollowSymlinks,
+		Include: sp.Include, Exclude: sp.Exclude, NoDefaultExcludes: sp.NoDefaultExcludes,
+		Platform: sp.Platform, Category: sp.Category,
+	}
+	var paths []library.ScanPath
+	err := r.conn.Call(ctx, server.MsgTypeAddScanPath, payload, &paths)
+	return paths, err
+}
+
+func (r *remoteHub) RemoveScanPath(ctx context.Context, path string) ([]library.ScanPath, error) {
+	var paths []library.ScanPath
+	err := r.conn.Call(ctx, server.MsgTypeRemoveScanPath, server.ScanPathPayload{Path: path}, &paths)
+	return paths, err
+}
+
+func (r *remoteHub) ScanPaths(ctx context.Context) ([]library.ScanPathStatus, error) {
+	var statuses []library.ScanPathStatus
+	err := r.conn.Call(ctx, server.MsgTypeListScanPaths, nil, &statuses)
+	return statuses, err
+}
+
+/*   Progress arrives as scan.progress events    */
+/*   while the scan call is outstanding          */
+func (r *remoteHub) Scan(ctx context.Context, progress func(library.ScanProgress)) error {
+	if err := r.conn.Subscribe(ctx, server.TopicScanProgress); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range r.conn.Events() {
+			var p library.ScanProgress
+			if event.Topic != server.TopicScanProgress || decodeEvent(event, &p) != nil {
+				continue
+			}
+			progress(p)
+			if p.Done {
+				return
+			}
+		}
+	}()
+
+	/*   The final event is sent before the reply, */
+	/*   but may be dropped if events back up      */
+	err := r.conn.Call(ctx, server.MsgTypeScan, nil, nil)
+	if err == nil {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+	}
+	return err
+}
+
+func (r *remoteHub) CancelScan(ctx context.Context) error {
+	return r.conn.Call(ctx, server.MsgTypeCancelScan, nil, nil)
+}
+
+func (r *remoteHub) Launch(ctx context.Context, id string) (launcher.Session, error) {
+	var session launcher.Session
+	err := r.conn.Call(ctx, server.MsgTypeLaunchGame, id, &session)
+	var callErr *client.Error
+	if errors.As(err, &callErr) && callErr.Code == server.ErrCodeUnknownType {
+		return session, fmt.Errorf("this backend cannot launch games yet")
+	}
+	return session, err
+}
+
+func (r *remoteHub) SetProfile(ctx context.Context, id string, profile library.LaunchProfile) (*library.GameInfo, error) {
+	payload := server.LaunchProfilePayload{
+		GameID: id, Emulator: profile.Emulator, Core: profile.Core, Args: profile.Args, Env: profile.Env,
+		WorkingDir: profile.WorkingDir, PreLaunch: profile.PreLaunch, PostExit: profile.PostExit,
+	}
+	var game library.GameInfo
+	if err := r.conn.Call(ctx, server.MsgTypeSetProfile, payload, &game); err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+func (r *remoteHub) ClearProfile(ctx context.Context, id string) (*library.GameInfo, error) {
+	var game library.GameInfo
+	if err := r.conn.Call(ctx, server.MsgTypeClearProfile, id, &game); err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
+
+func (r *remoteHub) Stats(ctx context.Context) (library.Stats, error) {
+	var stats library.Stats
+	err := r.conn.Call(ctx, server.MsgTypeGetStats, nil, &stats)
+	return stats, err
+}
+
+func (r *remoteHub) Verify(ctx context.Context) ([]library.VerifyIssue, error) {
+	var issues []library.VerifyIssue
+	err := r.conn.Call(ctx, server.MsgTypeVerifyLibrary, nil, &issues)
+	return issues, err
+}
+
+func (r *remoteHub) Analyze(ctx context.Context, opts library.AnalysisOptions) (library.AnalysisReport, error) {
+	var report library.AnalysisReport
+	err := r.conn.Call(ctx, server.MsgTypeAnalyzeLibrary, server.AnalyzePayload{PreferRegions: opts.PreferRegions}, &report)
+	return report, err
+}
+
+func (r *remoteHub) Firmware(ctx context.Context, platform string) (firmware.Report, error) {
+	var report firmware.Report
+	err := r.conn.Call(ctx, server.MsgTypeGetFirmware, server.FirmwarePayload{Platform: platform}, &report)
+	return report, err
+}
+
+func (r *remoteHub) Media(ctx context.Context, id string, kind library.MediaKind) ([]library.MediaItem, error) {
+	var items []library.MediaItem
+	err := r.conn.Call(ctx, server.MsgTypeGetMedia, server.MediaPayload{GameID: id, Kind: string(kind)}, &items)
+	return items, err
+}
+
+func (r *remoteHub) Export(ctx context.Context, format library.Format, opts library.ExportOptions) (string, error) {
+	var result server.ExportResult
+	payload := server.ExportPayload{
+		Format: string(format), Platform: opts.Platform, Favorites: opts.Favorites, BaseDir: opts.BaseDir,
+	}
+	err := r.conn.Call(ctx, server.MsgTypeExportLibrary, payload, &result)
+	return result.Content, err
+}
+
+func (r *remoteHub) Import(ctx context.Context, format library.Format, content string, opts library.ImportOptions) (library.ImportReport, error) {
+	var report library.ImportReport
+	payload := server.ImportPayload{
+		Format: string(format), Content: content, BaseDir: opts.BaseDir, Prefer: opts.Prefer,
+		Launch: opts.Launch,
+	}
+	err := r.conn.Call(ctx, server.MsgTypeImportLibrary, payload, &report)
+	return report, err
+}
+
+func (r *remoteHub) Close() error {
+	return r.conn.Close()
+}
+
+/**************************************************/
+/*                                                */
+/*             LOCAL (OFFLINE) HUB                */
+/*        Works directly on library.json          */
+/*                                                */
+/**************************************************/
+
+type localHub struct {
+	lib  *library.Library
+	cfg  *config.Config
+	lock *instance.Lock
+}
+
+/*   Holds the backend's instance lock while     */
+/*   open, so a backend on another address, or   */
+/*   one starting up, cannot save over us        */
+func openLocalHub(cfg *config.Config) (*localHub, error) {
+	lock, err := instance.Acquire(cfg.Dir, instance.Info{Owner: "hubctl"})
+	if err != nil {
+		return nil, fmt.Errorf("cannot work offline on %s: %w", cfg.Library.Path, err)
+	}
+	lib := library.NewLibrary(cfg.Library.Path)
+	if err := lib.LoadState(); err != nil {
+		lock.Release()
+		return nil, fmt.Errorf("cannot open %s: %w", cfg.Library.Path, err)
+	}
+	return &localHub{lib: lib, cfg: cfg, lock: lock}, nil
+}
+
+func (l *localHub) List(ctx context.Context, filter server.GameListPayload) ([]library.GameInfo, error) {
+	games := make([]library.GameInfo, 0)
+	for _, game := range l.lib.GetGames(filter.Platform, filter.Category) {
+		if filter.Query == "" || game.Matches(filter.Query) {
+			games = append(games, game)
+		}
+	}
+	if filter.Limit > 0 && filter.Limit < len(games) {
+		games = games[:filter.Limit]
+	}
+	return games, nil
+}
+
+func (l *localHub) Game(ctx context.Context, id string) (*library.GameInfo, error) {
+	game := l.lib.GetGameByID(id)
+	if game == nil {
+		return nil, library.ErrGameNotFound
+	}
+	return game, nil
+}
+
+func (l *localHub) ToggleFavorite(ctx context.Context, id string) (*library.GameInfo, error) {
+	if err := l.lib.ToggleFavorite(id); err != nil {
+		return nil, err
+	}
+	return l.lib.GetGameByID(id), nil
+}
+
+func (l *localHub) AddScanPath(ctx context.Context, sp library.ScanPath) ([]library.ScanPath, error) {
+	err := l.lib.AddScanPath(sp.Path)
+	if sp.HasOptions() {
+		err = l.lib.SetScanPath(sp)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot add %s: %w", sp.Path, err)
+	}
+	return l.lib.GetScanPaths(), l.lib.Save()
+}
+
+func (l *localHub) ScanPaths(ctx context.Context) ([]library.ScanPathStatus, error) {
+	return l.lib.ScanPathStatuses(), nil
+}
+
+func (l *localHub) RemoveScanPath(ctx context.Context, path string) ([]library.ScanPath, error) {
+	if err := l.lib.RemoveScanPath(path); err != nil {
+		return nil, err
+	}
+	return l.lib.GetScanPaths(), l.lib.Save()
+}
+
+func (l *localHub) Scan(ctx context.Context, progress func(library.ScanProgress)) error {
+	return l.lib.ScanContext(ctx, progress)
+}
+
+/*   An offline scan runs in this process, so   */
+/*   there is nothing to cancel                 */
+func (l *localHub) CancelScan(ctx context.Context) error {
+	return errNeedsBackend
+}
+
+func (l *localHub) Launch(ctx context.Context, id string) (launcher.Session, error) {
+	return launcher.Session{}, errNeedsBackend
+}
+
+/*   Library saves profile changes itself        */
+func (l *localHub) SetProfile(ctx context.Context, id string, profile library.LaunchProfile) (*library.GameInfo, error) {
+	return l.lib.SetLaunchProfile(id, profile)
+}
+
+func (l *localHub) ClearProfile(ctx context.Context, id string) (*library.GameInfo, error) {
+	return l.lib.ClearLaunchProfile(id)
+}
+
+func (l *localHub) Stats(ctx context.Context) (library.Stats, error) {
+	return l.lib.Stats(), nil
+}
+
+func (l *localHub) Verify(ctx context.Context) ([]library.VerifyIssue, error) {
+	return l.lib.Verify(), nil
+}
+
+func (l *localHub) Analyze(ctx context.Context, opts library.AnalysisOptions) (library.AnalysisReport, error) {
+	return l.lib.Analyze(ctx, opts)
+}
+
+func (l *localHub) Firmware(ctx context.Context, platform string) (firmware.Report, error) {
+	report := firmware.Check(l.cfg.Firmware.Dirs, l.cfg.RequiredFirmware())
+	if platform != "" {
+		report = report.Platform(platform)
+	}
+	return report, nil
+}
+
+func (l *localHub) Media(ctx context.Context, id string, kind library.MediaKind) ([]library.MediaItem, error) {
+	return l.lib.Media(id, kind)
+}
+
+func (l *localHub) Export(ctx context.Context, format library.Format, opts library.ExportOptions) (string, error) {
+	var buf strings.Builder
+	err := l.lib.ExportTo(&buf, format, opts)
+	return buf.String(), err
+}
+
+func (l *localHub) Import(ctx context.Context, format library.Format, content string, opts library.ImportOptions) (library.ImportReport, error) {
+	return l.lib.ImportFrom(strings.NewReader(content), format, opts)
+}
+
+func (l *localHub) Close() error {
+	return l.lock.Release()
+}
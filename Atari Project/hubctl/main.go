@@ -0,0 +1,1071 @@
+/**************************************/
+/*                                    */
+/*   hubctl - Library Management CLI  */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"retro-gaming-ui/backend/client"
+	"retro-gaming-ui/backend/config"
+	"retro-gaming-ui/backend/firmware"
+	"retro-gaming-ui/backend/library"
+	"retro-gaming-ui/backend/server"
+)
+
+/**************************************************/
+/*                                                */
+/*                   COMMANDS                     */
+/*                                                */
+/**************************************************/
+
+type command struct {
+	name  string
+	usage string
+	help  string
+	run   func(ctx context.Context, app *app, args []string) error
+}
+
+var commands = []command{
+	{"list", "list [-platform P] [-category C] [-favorites] [-limit N]", "list games", cmdList},
+	{"search", "search [-platform P] [-limit N] <words...>", "find games by title", cmdSearch},
+	{"show", "show <game-id>", "show one game", cmdShow},
+	{"favorite", "favorite <game-id>", "toggle a game's favorite flag", cmdFavorite},
+	{"paths", "paths", "list scan paths with their status and game count", cmdPaths},
+	{"add-path", "add-path [-depth N] [-follow-symlinks] [-include P] [-exclude P] [-no-default-excludes] [-platform P] [-category C] <dir>", "add a scan path or change its options", cmdAddPath},
+	{"remove-path", "remove-path <dir>", "remove a scan path", cmdRemovePath},
+	{"scan", "scan", "rescan all scan paths", cmdScan},
+	{"cancel-scan", "cancel-scan", "stop a running scan (needs a running backend)", cmdCancelScan},
+	{"launch", "launch <game-id>", "launch a game (needs a running backend)", cmdLaunch},
+	{"set-profile", "set-profile [-emulator E] [-core C] [-arg A] [-env K=V] [-workdir D] [-pre S] [-post S] <game-id>", "override how one game is launched", cmdSetProfile},
+	{"clear-profile", "clear-profile <game-id>", "launch a game with its platform's emulator again", cmdClearProfile},
+	{"stats", "stats", "library statistics", cmdStats},
+	{"export", "export [-format F] [-platform P] [-favorites] [file]", "export as json, csv, gamelist or lpl (default stdout)", cmdExport},
+	{"import", "import [-format F] [-prefer local|imported] [-launch] <file|->", "merge an export, matching games by content hash", cmdImport},
+	{"verify", "verify", "check games against the filesystem", cmdVerify},
+	{"analyze", "analyze [-prefer REGION,...]", "find duplicates, alternates and damaged ROMs", cmdAnalyze},
+	{"media", "media [-kind cover|screenshot|clip|track] [game-id]", "list covers, screenshots, clips and soundtracks beside the ROMs", cmdMedia},
+	{"firmware", "firmware [-platform P]", "check BIOS and firmware files against known checksums", cmdFirmware},
+}
+
+/*          Per-call timeout for quick requests   */
+const callTimeout = 10 * time.Second
+
+type app struct {
+	hub  hub
+	json bool
+	out  io.Writer
+}
+
+/**************************************************/
+/*                                                */
+/*                    MAIN                        */
+/*                                                */
+/**************************************************/
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	fs := flag.NewFlagSet("hubctl", flag.ContinueOnError)
+	addr := fs.String("addr", "", "backend IPC address (default from config)")
+	token := fs.String("token", os.Getenv(config.EnvPrefix+"TOKEN"), "auth token (default the \"local\" token from the tokens file)")
+	tokensFile := fs.String("tokens-file", "", "auth tokens file (default from config)")
+	configDir := fs.String("config-dir", "", "configuration directory (default "+config.DefaultDir()+")")
+	offline := fs.Bool("offline", false, "work on library.json directly instead of the backend")
+	asJSON := fs.Bool("json", false, "print JSON instead of tables")
+	fs.Usage = func() { usage(fs) }
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		usage(fs)
+		return 2
+	}
+
+	cmd, ok := findCommand(fs.Arg(0))
+	if !ok {
+		fmt.Fprintf(os.Stderr, "hubctl: unknown command %q\n", fs.Arg(0))
+		usage(fs)
+		return 2
+	}
+
+	/*   Library logs go to stderr; keep them to   */
+	/*   warnings so they don't break the output   */
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	cfg, err := config.LoadFile(*configDir, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hubctl: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a := &app{json: *asJSON, out: os.Stdout}
+	a.hub, err = connect(ctx, cfg, firstNonEmpty(*addr, cfg.IPC.Listen),
+		*token, firstNonEmpty(*tokensFile, cfg.IPC.TokensFile), *offline)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hubctl: %v\n", err)
+		return 1
+	}
+	defer a.hub.Close()
+
+	if err := cmd.run(ctx, a, fs.Args()[1:]); err != nil {
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			fmt.Fprintf(os.Stderr, "usage: hubctl %s\n", cmd.usage)
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "hubctl %s: %v\n", cmd.name, err)
+		return 1
+	}
+	return 0
+}
+
+/*   Falls back to library.json when nothing is  */
+/*   listening; auth and protocol errors do not  */
+func connect(ctx context.Context, cfg *config.Config, addr, token, tokensFile string, offline bool) (hub, error) {
+	if !offline {
+		if token == "" {
+			token, _ = client.LoadToken(tokensFile, "local")
+		}
+		dialCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+
+		remote, err := dialHub(dialCtx, addr, token)
+		if err == nil {
+			return remote, nil
+		}
+		var callErr *client.Error
+		if errors.As(err, &callErr) {
+			return nil, fmt.Errorf("backend at %s refused hubctl: %w", addr, err)
+		}
+
+		/*   Something answered but the handshake     */
+		/*   failed: a backend at its connection cap  */
+		/*   or hung, not one that is down            */
+		var opErr *net.OpError
+		if !errors.As(err, &opErr) || opErr.Op != "dial" {
+			return nil, fmt.Errorf("backend at %s did not answer hubctl: %w", addr, err)
+		}
+		fmt.Fprintf(os.Stderr, "hubctl: no backend at %s, working offline on %s\n", addr, cfg.Library.Path)
+	}
+
+	local, err := openLocalHub(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return local, nil
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func usage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprintf(w, "usage: hubctl [flags] <command> [args]\n\ncommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-12s %s\n", cmd.name, cmd.help)
+	}
+	fmt.Fprintf(w, "\nflags:\n")
+	fs.PrintDefaults()
+}
+
+type usageError struct{}
+
+func (usageError) Error() string { return "bad usage" }
+
+/**************************************************/
+/*                                                */
+/*               GAME COMMANDS                    */
+/*                                                */
+/**************************************************/
+
+func cmdList(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	filter := server.GameListPayload{}
+	fs.StringVar(&filter.Platform, "platform", "", "only this platform")
+	fs.StringVar(&filter.Category, "category", "", "only this category")
+	fs.IntVar(&filter.Limit, "limit", 0, "at most this many games")
+	favorites := fs.Bool("favorites", false, "only favorites")
+	if fs.Parse(args) != nil || fs.NArg() > 0 {
+		return usageError{}
+	}
+
+	/*   Favorites are filtered here, so limit     */
+	/*   after filtering rather than on the wire   */
+	limit := filter.Limit
+	if *favorites {
+		filter.Limit = 0
+	}
+	games, err := a.list(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if *favorites {
+		kept := make([]library.GameInfo, 0)
+		for _, game := range games {
+			if game.Favorite {
+				kept = append(kept, game)
+			}
+		}
+		if limit > 0 && limit < len(kept) {
+			kept = kept[:limit]
+		}
+		games = kept
+	}
+	return a.printGames(games)
+}
+
+func cmdSearch(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	filter := server.GameListPayload{}
+	fs.StringVar(&filter.Platform, "platform", "", "only this platform")
+	fs.IntVar(&filter.Limit, "limit", 0, "at most this many games")
+	if fs.Parse(args) != nil || fs.NArg() == 0 {
+		return usageError{}
+	}
+	filter.Query = strings.Join(fs.Args(), " ")
+
+	games, err := a.list(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return a.printGames(games)
+}
+
+func (a *app) list(ctx context.Context, filter server.GameListPayload) ([]library.GameInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+	games, err := a.hub.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(games, func(i, j int) bool {
+		return strings.ToLower(games[i].Title) < strings.ToLower(games[j].Title)
+	})
+	return games, nil
+}
+
+func cmdShow(ctx context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return usageError{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	game, err := a.hub.Game(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if a.json {
+		return a.printJSON(game)
+	}
+	w := a.table()
+	fmt.Fprintf(w, "ID\t%s\n", game.ID)
+	fmt.Fprintf(w, "Title\t%s\n", game.Title)
+	fmt.Fprintf(w, "Platform\t%s\n", game.Platform)
+	fmt.Fprintf(w, "Category\t%s\n", game.Category)
+	fmt.Fprintf(w, "Path\t%s\n", game.Path)
+	fmt.Fprintf(w, "Favorite\t%s\n", yesNo(game.Favorite))
+	fmt.Fprintf(w, "Plays\t%d\n", game.PlayCount)
+	fmt.Fprintf(w, "Last played\t%s\n", formatTime(game.LastPlayed))
+	if game.PlaySeconds > 0 {
+		fmt.Fprintf(w, "Play time\t%s\n", time.Duration(game.PlaySeconds)*time.Second)
+	}
+	if game.Description != "" {
+		fmt.Fprintf(w, "Description\t%s\n", game.Description)
+	}
+	if game.Launch != nil {
+		writeProfile(w, game.Launch)
+	}
+	return w.Flush()
+}
+
+func cmdFavorite(ctx context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return usageError{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	game, err := a.hub.ToggleFavorite(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if a.json {
+		return a.printJSON(game)
+	}
+	if game.Favorite {
+		fmt.Fprintf(a.out, "%s is now a favorite\n", game.Title)
+	} else {
+		fmt.Fprintf(a.out, "%s is no longer a favorite\n", game.Title)
+	}
+	return nil
+}
+
+func cmdLaunch(ctx context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return usageError{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	session, err := a.hub.Launch(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if a.json {
+		return a.printJSON(session)
+	}
+	fmt.Fprintf(a.out, "launched %s with %s (pid %d)\n", session.GameID, session.Command, session.PID)
+	return nil
+}
+
+func cmdSetProfile(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("set-profile", flag.ContinueOnError)
+	profile := library.LaunchProfile{}
+	fs.StringVar(&profile.Emulator, "emulator", "", "emulator command (default the platform's)")
+	fs.StringVar(&profile.Core, "core", "", "core, substituted for {core} in the arguments")
+	fs.Func("arg", "emulator argument, replaces the platform's (repeatable)", func(arg string) error {
+		profile.Args = append(profile.Args, arg)
+		return nil
+	})
+	fs.Func("env", "KEY=VALUE added to the environment (repeatable)", func(pair string) error {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("want KEY=VALUE, got %q", pair)
+		}
+		if profile.Env == nil {
+			profile.Env = make(map[string]string)
+		}
+		profile.Env[key] = value
+		return nil
+	})
+	fs.StringVar(&profile.WorkingDir, "workdir", "", "working directory")
+	fs.StringVar(&profile.PreLaunch, "pre", "", "script to run before launching")
+	fs.StringVar(&profile.PostExit, "post", "", "script to run after the emulator exits")
+	if fs.Parse(args) != nil || fs.NArg() != 1 {
+		return usageError{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	game, err := a.hub.SetProfile(ctx, fs.Arg(0), profile)
+	if err != nil {
+		return err
+	}
+	return a.printProfile(game)
+}
+
+func cmdClearProfile(ctx context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return usageError{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	game, err := a.hub.ClearProfile(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return a.printProfile(game)
+}
+
+func (a *app) printProfile(game *library.GameInfo) error {
+	if a.json {
+		return a.printJSON(game)
+	}
+	if game.Launch == nil {
+		fmt.Fprintf(a.out, "%s launches with the %s emulator\n", game.ID, game.Platform)
+		return nil
+	}
+	w := a.table()
+	writeProfile(w, game.Launch)
+	return w.Flush()
+}
+
+func writeProfile(w io.Writer, p *library.LaunchProfile) {
+	row := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%s\t%s\n", name, value)
+		}
+	}
+	row("Emulator", p.Emulator)
+	row("Core", p.Core)
+	row("Arguments", strings.Join(p.Args, " "))
+	for _, key := range sortedKeys(p.Env) {
+		row("Env "+key, p.Env[key])
+	}
+	row("Working dir", p.WorkingDir)
+	row("Pre-launch", p.PreLaunch)
+	row("Post-exit", p.PostExit)
+}
+
+/**************************************************/
+/*                                                */
+/*              SCAN PATHS / SCAN                 */
+/*                                                */
+/**************************************************/
+
+func cmdPaths(ctx context.Context, a *app, args []string) error {
+	if len(args) != 0 {
+		return usageError{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	statuses, err := a.hub.ScanPaths(ctx)
+	if err != nil {
+		return err
+	}
+	if a.json {
+		return a.printJSON(statuses)
+	}
+	w := a.table()
+	fmt.Fprintf(w, "PATH\tSTATUS\tGAMES\tOPTIONS\n")
+	for _, s := range statuses {
+		status := s.Status
+		if s.Error != "" {
+			status += " (" + s.Error + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", s.Path, status, s.Games, describeScanPath(s.ScanPath))
+	}
+	return w.Flush()
+}
+
+func cmdAddPath(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("add-path", flag.ContinueOnError)
+	sp := library.ScanPath{}
+	fs.IntVar(&sp.MaxDepth, "depth", 0, "descend at most this many folders (0 = unlimited)")
+	fs.BoolVar(&sp.FollowSymlinks, "follow-symlinks", false, "descend into symlinked folders")
+	fs.Func("include", "only files matching this pattern (repeatable)", func(p string) error {
+		sp.Include = append(sp.Include, p)
+		return nil
+	})
+	fs.Func("exclude", "skip files and folders matching this pattern (repeatable)", func(p string) error {
+		sp.Exclude = append(sp.Exclude, p)
+		return nil
+	})
+	fs.BoolVar(&sp.NoDefaultExcludes, "no-default-excludes", false, "also scan hidden, bios, saves and similar folders")
+	fs.StringVar(&sp.Platform, "platform", "", "treat every game here as this platform")
+	fs.StringVar(&sp.Category, "category", "", "default category for games here")
+	if fs.Parse(args) != nil || fs.NArg() != 1 {
+		return usageError{}
+	}
+	/*   The backend resolves relative paths from  */
+	/*   its own working directory, not ours       */
+	var err error
+	if sp.Path, err = filepath.Abs(fs.Arg(0)); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	paths, err := a.hub.AddScanPath(ctx, sp)
+	if err != nil {
+		return err
+	}
+	return a.printPaths(paths)
+}
+
+func cmdRemovePath(ctx context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return usageError{}
+	}
+	path, err := filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	paths, err := a.hub.RemoveScanPath(ctx, path)
+	if err != nil {
+		return err
+	}
+	return a.printPaths(paths)
+}
+
+func cmdScan(ctx context.Context, a *app, args []string) error {
+	if len(args) != 0 {
+		return usageError{}
+	}
+
+	bar := newProgressBar(os.Stderr)
+	var last library.ScanProgress
+	err := a.hub.Scan(ctx, func(p library.ScanProgress) {
+		last = p
+		bar.update(p)
+	})
+	bar.finish()
+	if err != nil {
+		return err
+	}
+
+	if a.json {
+		return a.printJSON(last)
+	}
+	fmt.Fprintf(a.out, "scanned %d files, found %d games\n", last.FilesScanned, last.GamesFound)
+	return nil
+}
+
+func cmdCancelScan(ctx context.Context, a *app, args []string) error {
+	if len(args) != 0 {
+		return usageError{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+	if err := a.hub.CancelScan(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintln(a.out, "scan canceled")
+	return nil
+}
+
+/**************************************************/
+/*                                                */
+/*           STATS / EXPORT / IMPORT              */
+/*                                                */
+/**************************************************/
+
+func cmdStats(ctx context.Context, a *app, args []string) error {
+	if len(args) != 0 {
+		return usageError{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	stats, err := a.hub.Stats(ctx)
+	if err != nil {
+		return err
+	}
+	if a.json {
+		return a.printJSON(stats)
+	}
+
+	w := a.table()
+	fmt.Fprintf(w, "Games\t%d\n", stats.Games)
+	fmt.Fprintf(w, "Favorites\t%d\n", stats.Favorites)
+	fmt.Fprintf(w, "Total plays\t%d\n", stats.TotalPlays)
+	fmt.Fprintf(w, "Scan paths\t%d\n", stats.ScanPaths)
+	fmt.Fprintf(w, "Last scan\t%s\n", formatTime(stats.LastScan))
+	fmt.Fprintf(w, "\nPLATFORM\tGAMES\n")
+	for _, platform := range sortedKeys(stats.Platforms) {
+		fmt.Fprintf(w, "%s\t%d\n", platform, stats.Platforms[platform])
+	}
+	if len(stats.MostPlayed) > 0 {
+		fmt.Fprintf(w, "\nMOST PLAYED\tPLAYS\n")
+		for _, game := range stats.MostPlayed {
+			fmt.Fprintf(w, "%s\t%d\n", game.Title, game.PlayCount)
+		}
+	}
+	return w.Flush()
+}
+
+func cmdExport(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatName := fs.String("format", "", "json, csv, gamelist or lpl (default from the file extension)")
+	opts := library.ExportOptions{}
+	fs.StringVar(&opts.Platform, "platform", "", "only this platform")
+	fs.BoolVar(&opts.Favorites, "favorites", false, "only favorites")
+	if fs.Parse(args) != nil || fs.NArg() > 1 {
+		return usageError{}
+	}
+	target := fs.Arg(0)
+
+	format, err := pickFormat(*formatName, target)
+	if err != nil {
+		return err
+	}
+	/*   gamelist.xml paths are relative to where  */
+	/*   the file is written                       */
+	if target != "" && target != "-" {
+		if opts.BaseDir, err = filepath.Abs(filepath.Dir(target)); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+	content, err := a.hub.Export(ctx, format, opts)
+	if err != nil {
+		return err
+	}
+
+	if target == "" || target == "-" {
+		_, err = io.WriteString(a.out, content)
+		return err
+	}
+	if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %s to %s\n", format, target)
+	return nil
+}
+
+func cmdImport(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	formatName := fs.String("format", "", "json, csv, gamelist or lpl (default from the file extension)")
+	opts := library.ImportOptions{}
+	fs.StringVar(&opts.Prefer, "prefer", library.PreferLocal, "which side wins conflicts: local or imported")
+	fs.BoolVar(&opts.Launch, "launch", false, "also adopt launch profiles, including their hook scripts")
+	if fs.Parse(args) != nil || fs.NArg() != 1 {
+		return usageError{}
+	}
+	source := fs.Arg(0)
+
+	format, err := pickFormat(*formatName, source)
+	if err != nil {
+		return err
+	}
+
+	var content []byte
+	if source == "-" {
+		content, err = io.ReadAll(os.Stdin)
+		opts.BaseDir, _ = os.Getwd()
+	} else {
+		content, err = os.ReadFile(source)
+		if err == nil {
+			opts.BaseDir, err = filepath.Abs(filepath.Dir(source))
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	/*    Hashes files it has not seen, so no      */
+	/*    call timeout                             */
+	report, err := a.hub.Import(ctx, format, string(content), opts)
+	if err != nil {
+		return err
+	}
+	if a.json {
+		return a.printJSON(report)
+	}
+
+	fmt.Fprintf(a.out, "added %d, updated %d, unchanged %d, skipped %d, conflicts %d\n",
+		report.Added, report.Updated, report.Unchanged, len(report.Skipped), len(report.Conflicts))
+	if len(report.Conflicts) > 0 {
+		w := a.table()
+		fmt.Fprintf(w, "\nID\tFIELD\tLOCAL\tIMPORTED\tKEPT\n")
+		for _, c := range report.Conflicts {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.GameID, c.Field, clip(c.Local), clip(c.Imported), c.Kept)
+		}
+		w.Flush()
+	}
+	if len(report.Skipped) > 0 {
+		fmt.Fprintln(a.out, "\nskipped:")
+		for _, s := range report.Skipped {
+			fmt.Fprintf(a.out, "  %s (%s): %s\n", s.Title, s.Path, s.Reason)
+		}
+	}
+	return nil
+}
+
+func pickFormat(name, path string) (library.Format, error) {
+	if name != "" {
+		return library.ParseFormat(name)
+	}
+	return library.FormatForPath(path), nil
+}
+
+/**************************************************/
+/*                                                */
+/*                   VERIFY                       */
+/*       Exits non-zero when problems are found   */
+/*                                                */
+/**************************************************/
+
+func cmdVerify(ctx context.Context, a *app, args []string) error {
+	if len(args) != 0 {
+		return usageError{}
+	}
+	/*    Touches every file, so no call timeout   */
+	issues, err := a.hub.Verify(ctx)
+	if err != nil {
+		return err
+	}
+
+	if a.json {
+		if err := a.printJSON(issues); err != nil {
+			return err
+		}
+	} else if len(issues) == 0 {
+		fmt.Fprintln(a.out, "library OK")
+	} else {
+		w := a.table()
+		fmt.Fprintf(w, "ID\tTITLE\tPROBLEM\n")
+		for _, issue := range issues {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", issue.GameID, issue.Title, issue.Problem)
+		}
+		w.Flush()
+	}
+
+	if len(issues) > 0 {
+		return fmt.Errorf("%d problem(s) found", len(issues))
+	}
+	return nil
+}
+
+func cmdFirmware(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("firmware", flag.ContinueOnError)
+	platform := fs.String("platform", "", "only this platform")
+	if fs.Parse(args) != nil || fs.NArg() != 0 {
+		return usageError{}
+	}
+	/*    Hashes every candidate, so no timeout    */
+	report, err := a.hub.Firmware(ctx, *platform)
+	if err != nil {
+		return err
+	}
+
+	/*   A missing group counts once   */
+	missing, groups := 0, make(map[string]bool)
+	for _, f := range report.Missing() {
+		if f.Group == "" || !groups[f.Group] {
+			missing++
+		}
+		groups[f.Group] = true
+	}
+	if a.json {
+		if err := a.printJSON(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(a.out, "searched: %s\n\n", strings.Join(report.Dirs, ", "))
+		w := a.table()
+		fmt.Fprintf(w, "PLATFORM\tFILE\tSTATUS\tREQUIRED\tPATH\n")
+		for _, f := range report.Files {
+			required := ""
+			if f.Required {
+				required = "yes"
+			}
+			if f.Required && f.Group != "" {
+				required = "one of " + f.Group
+			}
+			status := f.Status
+			if f.Status == firmware.StatusMismatch {
+				status += " (md5 " + f.FoundMD5 + ")"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", f.Platform, f.Name, status, required, f.Path)
+		}
+		w.Flush()
+	}
+
+	if missing > 0 {
+		return fmt.Errorf("%d required file(s) missing or wrong", missing)
+	}
+	return nil
+}
+
+func cmdMedia(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("media", flag.ContinueOnError)
+	kind := fs.String("kind", "", "only this kind")
+	if fs.Parse(args) != nil || fs.NArg() > 1 {
+		return usageError{}
+	}
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	items, err := a.hub.Media(ctx, fs.Arg(0), library.MediaKind(*kind))
+	if err != nil {
+		return err
+	}
+	if a.json {
+		return a.printJSON(items)
+	}
+	w := a.table()
+	fmt.Fprintf(w, "GAME\tKIND\tSIZE\tPATH\n")
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.GameID, item.Kind, humanSize(item.Size), item.Path)
+	}
+	return w.Flush()
+}
+
+func cmdAnalyze(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	prefer := fs.String("prefer", strings.Join(library.DefaultRegions, ","), "regions to prefer, best first")
+	if fs.Parse(args) != nil || fs.NArg() != 0 {
+		return usageError{}
+	}
+	opts := library.AnalysisOptions{}
+	for _, region := range strings.Split(*prefer, ",") {
+		if region = strings.TrimSpace(region); region != "" {
+			opts.PreferRegions = append(opts.PreferRegions, region)
+		}
+	}
+
+	/*    Reads every file, so no call timeout     */
+	report, err := a.hub.Analyze(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if a.json {
+		return a.printJSON(report)
+	}
+
+	fmt.Fprintf(a.out, "%d games: %d duplicate group(s), %d alternate group(s), %d problem(s), %s reclaimable\n",
+		report.GamesAnalyzed, len(report.Duplicates), len(report.Alternates), len(report.Problems),
+		humanSize(report.ReclaimableBytes))
+
+	for _, group := range report.Duplicates {
+		fmt.Fprintf(a.out, "\nduplicate %s (%s):\n", group.Hash[:min(12, len(group.Hash))], humanSize(group.Size))
+		printRomEntries(a.out, group.Games, group.Keep, "keep")
+	}
+	for _, group := range report.Alternates {
+		fmt.Fprintf(a.out, "\nalternates of %q on %s:\n", group.Title, group.Platform)
+		printRomEntries(a.out, group.Games, group.Preferred, "prefer")
+	}
+	if len(report.Problems) > 0 {
+		w := a.table()
+		fmt.Fprintf(w, "\nID\tKIND\tPROBLEM\n")
+		for _, p := range report.Problems {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", p.GameID, p.Kind, p.Detail)
+		}
+		w.Flush()
+	}
+	if len(report.Actions) > 0 {
+		w := a.table()
+		fmt.Fprintf(w, "\nACTION\tID\tPATH\tWHY\n")
+		for _, action := range report.Actions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", action.Action, action.GameID, action.Path, action.Reason)
+		}
+		w.Flush()
+	}
+	return nil
+}
+
+func printRomEntries(w io.Writer, entries []library.RomEntry, marked, mark string) {
+	for _, entry := range entries {
+		label := strings.Repeat(" ", len(mark))
+		if entry.GameID == marked {
+			label = mark
+		}
+		details := append(append([]string{}, entry.Regions...), entry.Flags...)
+		if entry.Revision != "" {
+			details = append(details, entry.Revision)
+		}
+		fmt.Fprintf(w, "  %s  %s  %s", label, entry.GameID, entry.Path)
+		if len(details) > 0 {
+			fmt.Fprintf(w, "  [%s]", strings.Join(details, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                   OUTPUT                       */
+/*                                                */
+/**************************************************/
+
+func (a *app) table() *tabwriter.Writer {
+	return tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+}
+
+func (a *app) printJSON(v interface{}) error {
+	enc := json.NewEncoder(a.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (a *app) printGames(games []library.GameInfo) error {
+	if a.json {
+		return a.printJSON(games)
+	}
+	w := a.table()
+	fmt.Fprintf(w, "ID\tTITLE\tPLATFORM\tCATEGORY\tFAV\tPLAYS\n")
+	for _, game := range games {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\n",
+			game.ID, game.Title, game.Platform, game.Category, star(game.Favorite), game.PlayCount)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "%d game(s)\n", len(games))
+	return nil
+}
+
+func (a *app) printPaths(paths []library.ScanPath) error {
+	if a.json {
+		return a.printJSON(paths)
+	}
+	fmt.Fprintln(a.out, "scan paths:")
+	for _, sp := range paths {
+		if options := describeScanPath(sp); options != "-" {
+			fmt.Fprintf(a.out, "  %s  [%s]\n", sp.Path, options)
+		} else {
+			fmt.Fprintf(a.out, "  %s\n", sp.Path)
+		}
+	}
+	return nil
+}
+
+func describeScanPath(sp library.ScanPath) string {
+	var parts []string
+	if sp.MaxDepth > 0 {
+		parts = append(parts, fmt.Sprintf("depth %d", sp.MaxDepth))
+	}
+	if sp.FollowSymlinks {
+		parts = append(parts, "follow symlinks")
+	}
+	if len(sp.Include) > 0 {
+		parts = append(parts, "include "+strings.Join(sp.Include, ","))
+	}
+	if len(sp.Exclude) > 0 {
+		parts = append(parts, "exclude "+strings.Join(sp.Exclude, ","))
+	}
+	if sp.NoDefaultExcludes {
+		parts = append(parts, "no default excludes")
+	}
+	if sp.Platform != "" {
+		parts = append(parts, "platform "+sp.Platform)
+	}
+	if sp.Category != "" {
+		parts = append(parts, "category "+sp.Category)
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+/**************************************************/
+/*                                                */
+/*                PROGRESS BAR                    */
+/*        Redraws one line on a terminal          */
+/*                                                */
+/**************************************************/
+
+const progressWidth = 30
+
+type progressBar struct {
+	w     io.Writer
+	drawn bool
+}
+
+func newProgressBar(w io.Writer) *progressBar {
+	return &progressBar{w: w}
+}
+
+func (b *progressBar) update(p library.ScanProgress) {
+	if p.FilesTotal == 0 {
+		fmt.Fprintf(b.w, "\rscanning... %d files, %d games", p.FilesScanned, p.GamesFound)
+		b.drawn = true
+		return
+	}
+
+	ratio := float64(p.FilesScanned) / float64(p.FilesTotal)
+	if ratio > 1 {
+		ratio = 1
+	}
+	filled := int(ratio * progressWidth)
+	fmt.Fprintf(b.w, "\r[%s%s] %3.0f%%  %d/%d files, %d games",
+		strings.Repeat("#", filled), strings.Repeat("-", progressWidth-filled),
+		ratio*100, p.FilesScanned, p.FilesTotal, p.GamesFound)
+	b.drawn = true
+}
+
+func (b *progressBar) finish() {
+	if b.drawn {
+		fmt.Fprintln(b.w)
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                  HELPERS                       */
+/*                                                */
+/**************************************************/
+
+func decodeEvent(event client.Event, v interface{}) error {
+	return json.Unmarshal(event.Data, v)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func clip(s string) string {
+	if runes := []rune(s); len(runes) > 40 {
+		return string(runes[:37]) + "..."
+	}
+	return s
+}
+
+func humanSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func star(b bool) string {
+	if b {
+		return "*"
+	}
+	return ""
+}
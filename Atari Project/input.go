@@ -0,0 +1,641 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+/**************************************/
+/*                                    */
+/*    Input Actions & Key Bindings    */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*                   ACTIONS                      */
+/*   What the UI reacts to; keys, pad buttons     */
+/*   and sticks are bound to these                */
+/*                                                */
+/**************************************************/
+
+type Action int
+
+const (
+	ActNavUp Action = iota
+	ActNavDown
+	ActNavLeft
+	ActNavRight
+	ActSelect
+	ActBack
+	ActFavorite
+	ActSearch
+	ActFilter
+	ActPageUp
+	ActPageDown
+	ActFirst
+	ActLast
+	ActPrevLetter
+	ActNextLetter
+	ActFullscreen
+	actionCount
+)
+
+/*   Names in the bindings file                 */
+var actionNames = [actionCount]string{
+	"nav_up", "nav_down", "nav_left", "nav_right", "select", "back", "favorite", "search",
+	"filter", "page_up", "page_down", "first", "last", "prev_letter", "next_letter", "fullscreen",
+}
+
+/*   Names on the remap screen                  */
+var actionLabels = [actionCount]string{
+	"Up", "Down", "Left", "Right", "Select / launch", "Back", "Favorite", "Find by title",
+	"Media filter", "Page up", "Page down", "First game", "Last game", "Previous letter", "Next letter",
+	"Fullscreen",
+}
+
+func actionByName(name string) (Action, bool) {
+	for a, n := range actionNames {
+		if n == name {
+			return Action(a), true
+		}
+	}
+	return 0, false
+}
+
+/**************************************************/
+/*                                                */
+/*               PHYSICAL INPUTS                  */
+/*   Written as key:LEFT, pad:A or axis:LX-       */
+/*                                                */
+/**************************************************/
+
+type InputDevice int
+
+const (
+	DeviceKey InputDevice = iota
+	DeviceButton
+	DeviceAxis
+)
+
+type Input struct {
+	Device InputDevice
+	Code   int32
+	Dir    int8 /*   axis direction, +1 or -1 */
+}
+
+/*   ESCAPE is left out: raylib quits on it     */
+var keyNames = func() map[string]int32 {
+	names := map[string]int32{
+		"UP": rl.KeyUp, "DOWN": rl.KeyDown, "LEFT": rl.KeyLeft, "RIGHT": rl.KeyRight,
+		"ENTER": rl.KeyEnter, "SPACE": rl.KeySpace, "BACKSPACE": rl.KeyBackspace, "TAB": rl.KeyTab,
+		"HOME": rl.KeyHome, "END": rl.KeyEnd, "PAGE_UP": rl.KeyPageUp, "PAGE_DOWN": rl.KeyPageDown,
+		"INSERT": rl.KeyInsert, "DELETE": rl.KeyDelete,
+		"LEFT_CONTROL": rl.KeyLeftControl, "LEFT_ALT": rl.KeyLeftAlt, "LEFT_SHIFT": rl.KeyLeftShift,
+		"RIGHT_CONTROL": rl.KeyRightControl, "RIGHT_ALT": rl.KeyRightAlt, "RIGHT_SHIFT": rl.KeyRightShift,
+		"LEFT_BRACKET": rl.KeyLeftBracket, "RIGHT_BRACKET": rl.KeyRightBracket,
+		"SLASH": rl.KeySlash, "MINUS": rl.KeyMinus, "EQUAL": rl.KeyEqual,
+		"COMMA": rl.KeyComma, "PERIOD": rl.KeyPeriod,
+	}
+	for k := int32(rl.KeyA); k <= rl.KeyZ; k++ {
+		names[string(rune(k))] = k
+	}
+	for k := int32(rl.KeyZero); k <= rl.KeyNine; k++ {
+		names[string(rune(k))] = k
+	}
+	for i := int32(0); i < 12; i++ {
+		names[fmt.Sprintf("F%d", i+1)] = rl.KeyF1 + i
+	}
+	return names
+}()
+
+/*   Xbox names; the face buttons sit in the    */
+/*   same places on other pads                  */
+var buttonNames = map[string]int32{
+	"DPAD_UP": rl.GamepadButtonLeftFaceUp, "DPAD_DOWN": rl.GamepadButtonLeftFaceDown,
+	"DPAD_LEFT": rl.GamepadButtonLeftFaceLeft, "DPAD_RIGHT": rl.GamepadButtonLeftFaceRight,
+	"A": rl.GamepadButtonRightFaceDown, "B": rl.GamepadButtonRightFaceRight,
+	"X": rl.GamepadButtonRightFaceLeft, "Y": rl.GamepadButtonRightFaceUp,
+	"LB": rl.GamepadButtonLeftTrigger1, "LT": rl.GamepadButtonLeftTrigger2,
+	"RB": rl.GamepadButtonRightTrigger1, "RT": rl.GamepadButtonRightTrigger2,
+	"SELECT": rl.GamepadButtonMiddleLeft, "GUIDE": rl.GamepadButtonMiddle, "START": rl.GamepadButtonMiddleRight,
+	"L3": rl.GamepadButtonLeftThumb, "R3": rl.GamepadButtonRightThumb,
+}
+
+var axisNames = map[string]int32{
+	"LX": rl.GamepadAxisLeftX, "LY": rl.GamepadAxisLeftY,
+	"RX": rl.GamepadAxisRightX, "RY": rl.GamepadAxisRightY,
+	"LT": rl.GamepadAxisLeftTrigger, "RT": rl.GamepadAxisRightTrigger,
+}
+
+func nameOf(names map[string]int32, code int32) string {
+	for name, c := range names {
+		if c == code {
+			return name
+		}
+	}
+	return fmt.Sprint(code)
+}
+
+func (in Input) String() string {
+	switch in.Device {
+	case DeviceButton:
+		return "pad:" + nameOf(buttonNames, in.Code)
+	case DeviceAxis:
+		sign := "+"
+		if in.Dir < 0 {
+			sign = "-"
+		}
+		return "axis:" + nameOf(axisNames, in.Code) + sign
+	}
+	return "key:" + nameOf(keyNames, in.Code)
+}
+
+/*   Short form for hints: "ENTER", "PAD A"     */
+func (in Input) Label() string {
+	_, name, _ := strings.Cut(in.String(), ":")
+	switch in.Device {
+	case DeviceButton:
+		return "PAD " + name
+	case DeviceAxis:
+		return "STICK " + name
+	}
+	return name
+}
+
+func ParseInput(text string) (Input, error) {
+	device, name, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(text)), ":")
+	if !ok {
+		return Input{}, fmt.Errorf("input %q: want key:NAME, pad:NAME or axis:NAME+/-", text)
+	}
+	switch device {
+	case "KEY":
+		if code, ok := keyNames[name]; ok {
+			return Input{Device: DeviceKey, Code: code}, nil
+		}
+	case "PAD":
+		if code, ok := buttonNames[name]; ok {
+			return Input{Device: DeviceButton, Code: code}, nil
+		}
+	case "AXIS":
+		dir := int8(1)
+		if strings.HasSuffix(name, "-") {
+			dir = -1
+		}
+		if code, ok := axisNames[strings.TrimRight(name, "+-")]; ok {
+			return Input{Device: DeviceAxis, Code: code, Dir: dir}, nil
+		}
+	}
+	return Input{}, fmt.Errorf("input %q: unknown %s", text, strings.ToLower(device))
+}
+
+func (in Input) MarshalText() ([]byte, error) {
+	return []byte(in.String()), nil
+}
+
+func (in *Input) UnmarshalText(text []byte) error {
+	parsed, err := ParseInput(string(text))
+	if err != nil {
+		return err
+	}
+	*in = parsed
+	return nil
+}
+
+/**************************************************/
+/*                                                */
+/*                INPUT SOURCES                   */
+/*                                                */
+/**************************************************/
+
+const maxGamepads = 4
+
+type InputSource interface {
+	KeyDown(key int32) bool
+	GamepadAvailable(pad int32) bool
+	ButtonDown(pad, button int32) bool
+	Axis(pad, axis int32) float32
+	CharPressed() rune /*   next typed character, 0 when none */
+}
+
+type raylibInput struct{}
+
+func (raylibInput) CharPressed() rune                 { return rl.GetCharPressed() }
+func (raylibInput) KeyDown(key int32) bool            { return rl.IsKeyDown(key) }
+func (raylibInput) GamepadAvailable(pad int32) bool   { return rl.IsGamepadAvailable(pad) }
+func (raylibInput) ButtonDown(pad, button int32) bool { return rl.IsGamepadButtonDown(pad, button) }
+func (raylibInput) Axis(pad, axis int32) float32      { return rl.GetGamepadAxisMovement(pad, axis) }
+
+/*   Scripted input for tests and the headless  */
+/*   harness; one pad                           */
+type FakeInput struct {
+	Keys    map[int32]bool
+	Buttons map[int32]bool
+	Axes    map[int32]float32
+	Typed   []rune
+	NoPad   bool
+}
+
+func NewFakeInput() *FakeInput {
+	return &FakeInput{Keys: map[int32]bool{}, Buttons: map[int32]bool{}, Axes: map[int32]float32{}}
+}
+
+/*   Axes go to full deflection or back to 0    */
+func (f *FakeInput) Set(in Input, down bool) {
+	switch in.Device {
+	case DeviceKey:
+		f.Keys[in.Code] = down
+	case DeviceButton:
+		f.Buttons[in.Code] = down
+	case DeviceAxis:
+		f.Axes[in.Code] = 0
+		if down {
+			f.Axes[in.Code] = float32(in.Dir)
+		}
+	}
+}
+
+func (f *FakeInput) CharPressed() rune {
+	if len(f.Typed) == 0 {
+		return 0
+	}
+	r := f.Typed[0]
+	f.Typed = f.Typed[1:]
+	return r
+}
+
+func (f *FakeInput) KeyDown(key int32) bool          { return f.Keys[key] }
+func (f *FakeInput) GamepadAvailable(pad int32) bool { return pad == 0 && !f.NoPad }
+func (f *FakeInput) ButtonDown(pad, button int32) bool {
+	return pad == 0 && f.Buttons[button]
+}
+func (f *FakeInput) Axis(pad, axis int32) float32 {
+	if pad != 0 {
+		return 0
+	}
+	return f.Axes[axis]
+}
+
+/**************************************************/
+/*                                                */
+/*                  BINDINGS                      */
+/*   Saved beside the preferences; actions the    */
+/*   file leaves out keep their defaults          */
+/*                                                */
+/**************************************************/
+
+const bindingsFile = "frontend_bindings.json"
+
+type Bindings struct {
+	Deadzone      float32            `json:"deadzone"`
+	RepeatDelayMS int                `json:"repeat_delay_ms"` /*   before a held input repeats */
+	RepeatRateMS  int                `json:"repeat_rate_ms"`
+	FastAfterMS   int                `json:"fast_after_ms"` /*   hold-to-scroll speeds up     */
+	FastRateMS    int                `json:"fast_rate_ms"`
+	Actions       map[string][]Input `json:"actions"`
+
+	path string
+}
+
+func bind(names ...string) []Input {
+	inputs := make([]Input, 0, len(names))
+	for _, name := range names {
+		in, err := ParseInput(name)
+		if err != nil {
+			panic(err)
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs
+}
+
+/*   Keyboard, an Xbox-style pad and a key-     */
+/*   encoder arcade panel in the usual MAME     */
+/*   layout (stick = arrows, buttons = LCTRL,   */
+/*   LALT, SPACE, LSHIFT, Z, X, start = 1)      */
+func DefaultBindings(path string) *Bindings {
+	return &Bindings{
+		Deadzone: 0.35, RepeatDelayMS: 350, RepeatRateMS: 90, FastAfterMS: 1500, FastRateMS: 35,
+		Actions: map[string][]Input{
+			"nav_up":      bind("key:UP", "key:W", "pad:DPAD_UP", "axis:LY-"),
+			"nav_down":    bind("key:DOWN", "key:S", "pad:DPAD_DOWN", "axis:LY+"),
+			"nav_left":    bind("key:LEFT", "key:A", "pad:DPAD_LEFT", "axis:LX-"),
+			"nav_right":   bind("key:RIGHT", "key:D", "pad:DPAD_RIGHT", "axis:LX+"),
+			"select":      bind("key:ENTER", "key:LEFT_CONTROL", "key:1", "pad:A", "pad:START"),
+			"back":        bind("key:BACKSPACE", "key:LEFT_ALT", "pad:B"),
+			"favorite":    bind("key:F", "key:LEFT_SHIFT", "pad:Y"),
+			"search":      bind("key:SLASH", "key:Z", "pad:SELECT"),
+			"filter":      bind("key:TAB", "key:X", "pad:X"),
+			"page_up":     bind("key:PAGE_UP", "pad:LB"),
+			"page_down":   bind("key:PAGE_DOWN", "pad:RB"),
+			"first":       bind("key:HOME"),
+			"last":        bind("key:END"),
+			"prev_letter": bind("key:LEFT_BRACKET", "pad:LT", "axis:LT+"),
+			"next_letter": bind("key:RIGHT_BRACKET", "pad:RT", "axis:RT+"),
+			"fullscreen":  bind("key:F11"),
+		},
+		path: path,
+	}
+}
+
+/*   A missing file gives the defaults; a bad   */
+/*   one gives the defaults and the error       */
+func LoadBindings(dir string) (*Bindings, error) {
+	b := DefaultBindings(filepath.Join(dir, bindingsFile))
+	data, err := os.ReadFile(b.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return b, err
+	}
+	if err := json.Unmarshal(data, b); err != nil {
+		return DefaultBindings(b.path), fmt.Errorf("invalid bindings %s: %w", b.path, err)
+	}
+	for name := range b.Actions {
+		if _, ok := actionByName(name); !ok {
+			delete(b.Actions, name)
+			err = fmt.Errorf("bindings %s: unknown action %q ignored", b.path, name)
+		}
+	}
+	b.clamp()
+	return b, err
+}
+
+func (b *Bindings) clamp() {
+	b.Deadzone = max(0.1, min(b.Deadzone, 0.9))
+	b.RepeatDelayMS = max(100, b.RepeatDelayMS)
+	b.RepeatRateMS = max(20, b.RepeatRateMS)
+	b.FastAfterMS = max(b.RepeatDelayMS, b.FastAfterMS)
+	b.FastRateMS = max(10, min(b.FastRateMS, b.RepeatRateMS))
+}
+
+func (b *Bindings) Save() error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	os.MkdirAll(filepath.Dir(b.path), 0755)
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.path)
+}
+
+func (b *Bindings) Path() string {
+	return b.path
+}
+
+func (b *Bindings) For(a Action) []Input {
+	return b.Actions[actionNames[a]]
+}
+
+/*   Binds in to a, replacing a's inputs of the */
+/*   same kind (keys, or pad buttons and axes), */
+/*   and takes it off any other action. Returns */
+/*   the action it was taken from, if any       */
+func (b *Bindings) Assign(a Action, in Input) (Action, bool) {
+	pad := func(i Input) bool { return i.Device != DeviceKey }
+
+	from, moved := Action(0), false
+	for other := Action(0); other < actionCount; other++ {
+		if other == a {
+			continue
+		}
+		inputs := b.For(other)
+		if i := indexOf(inputs, in); i >= 0 {
+			b.Actions[actionNames[other]] = append(inputs[:i:i], inputs[i+1:]...)
+			from, moved = other, true
+		}
+	}
+
+	kept := []Input{in}
+	for _, old := range b.For(a) {
+		if pad(old) != pad(in) {
+			kept = append(kept, old)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Device < kept[j].Device })
+	b.Actions[actionNames[a]] = kept
+	return from, moved
+}
+
+/*   Carries over the old launch-key setting;   */
+/*   ENTER is already bound                     */
+func (b *Bindings) migrateLaunchKey(key string) {
+	space := Input{Device: DeviceKey, Code: rl.KeySpace}
+	if key == "SPACE" && indexOf(b.For(ActSelect), space) < 0 {
+		b.Actions[actionNames[ActSelect]] = append([]Input{space}, b.For(ActSelect)...)
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                 ACTION MAP                     */
+/*   Polled once per frame; dt comes in so tests  */
+/*   can step time                                */
+/*                                                */
+/**************************************************/
+
+type actionState struct {
+	down       bool
+	pressed    bool /*   went down this frame           */
+	padDown    bool /*   held through a pad button/axis */
+	padPressed bool
+	repeated   bool    /*   pressed, or a held auto-repeat */
+	held       float32 /*   seconds                        */
+	nextRepeat float32
+}
+
+/*   Keys text boxes read directly, whatever    */
+/*   the bindings say                           */
+var editKeys = [...]int32{rl.KeyEscape, rl.KeyEnter, rl.KeyBackspace}
+
+type ActionMap struct {
+	source   InputSource
+	bindings *Bindings
+	states   [actionCount]actionState
+
+	editDown    [len(editKeys)]bool
+	editPressed [len(editKeys)]bool
+	typed       []rune /*   this frame   */
+}
+
+func NewActionMap(source InputSource, bindings *Bindings) *ActionMap {
+	return &ActionMap{source: source, bindings: bindings}
+}
+
+func (m *ActionMap) Bindings() *Bindings {
+	return m.bindings
+}
+
+func (m *ActionMap) SetBindings(b *Bindings) {
+	m.bindings = b
+}
+
+func (m *ActionMap) Update(dt float32) {
+	delay := float32(m.bindings.RepeatDelayMS) / 1000
+	rate := float32(m.bindings.RepeatRateMS) / 1000
+	fastAfter := float32(m.bindings.FastAfterMS) / 1000
+	fastRate := float32(m.bindings.FastRateMS) / 1000
+
+	for a := Action(0); a < actionCount; a++ {
+		s := &m.states[a]
+		down, padDown := m.active(a)
+		s.pressed = down && !s.down
+		s.padPressed, s.padDown = padDown && !s.padDown, padDown
+		s.repeated = s.pressed
+		switch {
+		case s.pressed:
+			s.held, s.nextRepeat = 0, delay
+		case down:
+			s.held += dt
+			if s.held >= s.nextRepeat {
+				s.repeated = true
+				step := rate
+				if s.held >= fastAfter {
+					step = fastRate
+				}
+				s.nextRepeat = s.held + step
+			}
+		default:
+			s.held = 0
+		}
+		s.down = down
+	}
+
+	for i, key := range editKeys {
+		down := m.source.KeyDown(key)
+		m.editPressed[i], m.editDown[i] = down && !m.editDown[i], down
+	}
+	m.typed = m.typed[:0]
+	for r := m.source.CharPressed(); r != 0; r = m.source.CharPressed() {
+		m.typed = append(m.typed, r)
+	}
+}
+
+func (m *ActionMap) Pressed(a Action) bool {
+	return m.states[a].pressed
+}
+
+/*   Pressed, then repeating while held; for    */
+/*   navigation and hold-to-scroll              */
+func (m *ActionMap) Repeated(a Action) bool {
+	return m.states[a].repeated
+}
+
+/*   Pressed on a pad or stick; text boxes use  */
+/*   this, since keys there are typing          */
+func (m *ActionMap) PadPressed(a Action) bool {
+	return m.states[a].padPressed
+}
+
+/*   ESC, ENTER or BACKSPACE went down          */
+func (m *ActionMap) KeyPressed(key int32) bool {
+	for i, k := range editKeys {
+		if k == key {
+			return m.editPressed[i]
+		}
+	}
+	return false
+}
+
+/*   Characters typed this frame                */
+func (m *ActionMap) Typed() []rune {
+	return m.typed
+}
+
+func (m *ActionMap) Down(a Action) bool {
+	return m.states[a].down
+}
+
+func (m *ActionMap) Held(a Action) float32 {
+	return m.states[a].held
+}
+
+/*   Drops pending presses, e.g. when an        */
+/*   overlay closes on the same frame           */
+func (m *ActionMap) Clear() {
+	for a := range m.states {
+		m.states[a].pressed, m.states[a].repeated, m.states[a].padPressed = false, false, false
+	}
+	m.editPressed = [len(editKeys)]bool{}
+	m.typed = m.typed[:0]
+}
+
+/*   First binding's label, for footer hints    */
+func (m *ActionMap) Label(a Action) string {
+	if inputs := m.bindings.For(a); len(inputs) > 0 {
+		return inputs[0].Label()
+	}
+	return "UNBOUND"
+}
+
+/*   Whether any binding is down, and any pad   */
+/*   binding                                    */
+func (m *ActionMap) active(a Action) (bool, bool) {
+	down, pad := false, false
+	for _, in := range m.bindings.For(a) {
+		if m.inputDown(in, m.bindings.Deadzone) {
+			down = true
+			pad = pad || in.Device != DeviceKey
+		}
+	}
+	return down, pad
+}
+
+func (m *ActionMap) inputDown(in Input, deadzone float32) bool {
+	if in.Device == DeviceKey {
+		return m.source.KeyDown(in.Code)
+	}
+	for pad := int32(0); pad < maxGamepads; pad++ {
+		if !m.source.GamepadAvailable(pad) {
+			continue
+		}
+		if in.Device == DeviceButton && m.source.ButtonDown(pad, in.Code) {
+			return true
+		}
+		if in.Device == DeviceAxis && m.source.Axis(pad, in.Code)*float32(in.Dir) > deadzone {
+			return true
+		}
+	}
+	return false
+}
+
+/*   Every bindable input that is down now;     */
+/*   sticks must travel further than the        */
+/*   deadzone so a resting stick is ignored     */
+func (m *ActionMap) ActiveInputs() []Input {
+	const captureDeadzone = 0.6
+	active := make([]Input, 0)
+	for _, code := range keyNames {
+		if in := (Input{Device: DeviceKey, Code: code}); m.inputDown(in, 0) {
+			active = append(active, in)
+		}
+	}
+	for _, code := range buttonNames {
+		if in := (Input{Device: DeviceButton, Code: code}); m.inputDown(in, 0) {
+			active = append(active, in)
+		}
+	}
+	for _, code := range axisNames {
+		for _, dir := range []int8{1, -1} {
+			if in := (Input{Device: DeviceAxis, Code: code, Dir: dir}); m.inputDown(in, captureDeadzone) {
+				active = append(active, in)
+			}
+		}
+	}
+	return active
+}
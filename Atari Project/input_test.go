@@ -0,0 +1,244 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+/**************************************/
+/*                                    */
+/*         Action Map Tests           */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/*   Powers of two, so held time sums exactly:  */
+/*   at 64 frames a second the repeats below    */
+/*   land on whole frames                       */
+const testDT float32 = 1.0 / 64
+
+/*   Delay 1s, then every 0.5s, then every      */
+/*   0.25s once held for 2s                     */
+func testBindings() *Bindings {
+	b := DefaultBindings("")
+	b.RepeatDelayMS, b.RepeatRateMS = 1000, 500
+	b.FastAfterMS, b.FastRateMS = 2000, 250
+	return b
+}
+
+func mustInput(t *testing.T, text string) Input {
+	t.Helper()
+	in, err := ParseInput(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return in
+}
+
+/**************************************************/
+/*                                                */
+/*                 KEY REPEAT                     */
+/*                                                */
+/**************************************************/
+
+func TestActionMapRepeat(t *testing.T) {
+	tests := []struct {
+		name   string
+		frames int
+		want   []int /*   frames where Repeated is true */
+	}{
+		{"tap", 1, []int{0}},
+		{"held short of the delay", 64, []int{0}},
+		{"first repeat at the delay", 65, []int{0, 64}},
+		{"slow rate after the delay", 128, []int{0, 64, 96}},
+		{"fast switch at fast_after", 129, []int{0, 64, 96, 128}},
+		{"fast rate after the switch", 177, []int{0, 64, 96, 128, 144, 160, 176}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewFakeInput()
+			actions := NewActionMap(source, testBindings())
+			source.Set(mustInput(t, "key:DOWN"), true)
+
+			got := make([]int, 0)
+			for frame := 0; frame < tt.frames; frame++ {
+				actions.Update(testDT)
+				if actions.Repeated(ActNavDown) {
+					got = append(got, frame)
+				}
+				if pressed := actions.Pressed(ActNavDown); pressed != (frame == 0) {
+					t.Errorf("frame %d: Pressed = %v", frame, pressed)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("repeats on frames %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+/*   Letting go starts the delay over, even     */
+/*   after the fast rate kicked in              */
+func TestActionMapRepeatResetsOnRelease(t *testing.T) {
+	source := NewFakeInput()
+	actions := NewActionMap(source, testBindings())
+	down := mustInput(t, "key:DOWN")
+
+	source.Set(down, true)
+	for frame := 0; frame < 200; frame++ {
+		actions.Update(testDT)
+	}
+	source.Set(down, false)
+	actions.Update(testDT)
+	if actions.Down(ActNavDown) || actions.Held(ActNavDown) != 0 {
+		t.Fatalf("still down after release: held %v", actions.Held(ActNavDown))
+	}
+
+	source.Set(down, true)
+	repeats := 0
+	for frame := 0; frame < 64; frame++ {
+		actions.Update(testDT)
+		if actions.Repeated(ActNavDown) {
+			repeats++
+		}
+	}
+	if repeats != 1 {
+		t.Errorf("%d repeats in the first second after a re-press, want 1", repeats)
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*               PAD VS KEY PRESSES               */
+/*                                                */
+/**************************************************/
+
+func TestActionMapPadPressed(t *testing.T) {
+	tests := []struct {
+		name        string
+		noPad       bool
+		before      []string /*   held on the frame before */
+		now         []string
+		wantPressed bool
+		wantPad     bool
+	}{
+		{"key", false, nil, []string{"key:ENTER"}, true, false},
+		{"pad button", false, nil, []string{"pad:A"}, true, true},
+		{"arcade key", false, nil, []string{"key:LEFT_CONTROL"}, true, false},
+		{"pad while key held", false, []string{"key:ENTER"}, []string{"key:ENTER", "pad:A"}, false, true},
+		{"key while pad held", false, []string{"pad:A"}, []string{"pad:A", "key:ENTER"}, false, false},
+		{"key and pad together", false, nil, []string{"key:ENTER", "pad:START"}, true, true},
+		{"pad unplugged", true, nil, []string{"pad:A"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewFakeInput()
+			source.NoPad = tt.noPad
+			actions := NewActionMap(source, testBindings())
+
+			for _, name := range tt.before {
+				source.Set(mustInput(t, name), true)
+			}
+			actions.Update(testDT)
+			for _, name := range tt.now {
+				source.Set(mustInput(t, name), true)
+			}
+			actions.Update(testDT)
+
+			if got := actions.Pressed(ActSelect); got != tt.wantPressed {
+				t.Errorf("Pressed = %v, want %v", got, tt.wantPressed)
+			}
+			if got := actions.PadPressed(ActSelect); got != tt.wantPad {
+				t.Errorf("PadPressed = %v, want %v", got, tt.wantPad)
+			}
+		})
+	}
+}
+
+/*   A stick is pad input too, for navigation   */
+func TestActionMapStickIsPadInput(t *testing.T) {
+	source := NewFakeInput()
+	actions := NewActionMap(source, testBindings())
+	source.Set(mustInput(t, "axis:LY+"), true)
+	actions.Update(testDT)
+	if !actions.Pressed(ActNavDown) || !actions.PadPressed(ActNavDown) {
+		t.Errorf("Pressed = %v, PadPressed = %v, want both", actions.Pressed(ActNavDown), actions.PadPressed(ActNavDown))
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                STICK DEADZONES                 */
+/*                                                */
+/**************************************************/
+
+func TestActionMapDeadzone(t *testing.T) {
+	tests := []struct {
+		name     string
+		deadzone float32
+		ly       float32
+		wantDown bool
+		wantUp   bool
+	}{
+		{"resting", 0.35, 0, false, false},
+		{"drift", 0.35, 0.2, false, false},
+		{"on the deadzone", 0.35, 0.35, false, false},
+		{"past the deadzone", 0.35, 0.4, true, false},
+		{"full down", 0.35, 1, true, false},
+		{"full up", 0.35, -1, false, true},
+		{"past it upwards", 0.35, -0.4, false, true},
+		{"tight deadzone", 0.1, 0.2, true, false},
+		{"wide deadzone", 0.9, 0.8, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewFakeInput()
+			bindings := testBindings()
+			bindings.Deadzone = tt.deadzone
+			actions := NewActionMap(source, bindings)
+
+			source.Axes[mustInput(t, "axis:LY+").Code] = tt.ly
+			actions.Update(testDT)
+			if got := actions.Down(ActNavDown); got != tt.wantDown {
+				t.Errorf("nav_down = %v, want %v", got, tt.wantDown)
+			}
+			if got := actions.Down(ActNavUp); got != tt.wantUp {
+				t.Errorf("nav_up = %v, want %v", got, tt.wantUp)
+			}
+		})
+	}
+}
+
+/*   Remapping wants a deliberate push, not     */
+/*   whatever the binding deadzone lets through */
+func TestActiveInputsCaptureDeadzone(t *testing.T) {
+	tests := []struct {
+		lx   float32
+		want []string
+	}{
+		{0, nil},
+		{0.5, nil},
+		{0.7, []string{"axis:LX+"}},
+		{-0.7, []string{"axis:LX-"}},
+	}
+	for _, tt := range tests {
+		source := NewFakeInput()
+		actions := NewActionMap(source, testBindings())
+		source.Axes[mustInput(t, "axis:LX+").Code] = tt.lx
+
+		got := make([]string, 0)
+		for _, in := range actions.ActiveInputs() {
+			got = append(got, in.String())
+		}
+		want := tt.want
+		if want == nil {
+			want = []string{}
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("LX %v: ActiveInputs = %v, want %v", tt.lx, got, want)
+		}
+	}
+}
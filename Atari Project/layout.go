@@ -0,0 +1,161 @@
+package main
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+/**************************************/
+/*                                    */
+/*     Virtual Layout & Letterbox     */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*                  VIEWPORT                      */
+/*   Everything is laid out in SCREEN_WIDTH x     */
+/*   SCREEN_HEIGHT virtual units; the viewport    */
+/*   is where that lands in the window. No        */
+/*   raylib here, so the sums can be checked      */
+/*   headless                                     */
+/*                                                */
+/**************************************************/
+
+type ScaleMode string
+
+const (
+	ScaleFit     ScaleMode = "fit"     /*   largest 16:9 box, bars on the long side */
+	ScaleInteger ScaleMode = "integer" /*   whole multiples only; fit below 1x      */
+	ScaleStretch ScaleMode = "stretch" /*   the whole window, aspect ignored        */
+)
+
+/*   Choices the settings blade cycles through  */
+var scaleModes = []ScaleMode{ScaleFit, ScaleInteger, ScaleStretch}
+
+/*   Beyond 3x the canvas grows large for       */
+/*   little gain                                */
+const maxRenderScale = 3
+
+/*   In window pixels   */
+type Viewport struct {
+	X, Y          int32
+	Width, Height int32
+}
+
+/*   An unknown mode fits; an empty window,     */
+/*   as when minimized, gives an empty viewport */
+func ComputeViewport(windowWidth, windowHeight int32, mode ScaleMode) Viewport {
+	if windowWidth <= 0 || windowHeight <= 0 {
+		return Viewport{}
+	}
+	switch mode {
+	case ScaleStretch:
+		return Viewport{Width: windowWidth, Height: windowHeight}
+	case ScaleInteger:
+		if n := min(windowWidth/SCREEN_WIDTH, windowHeight/SCREEN_HEIGHT); n >= 1 {
+			return centered(windowWidth, windowHeight, SCREEN_WIDTH*n, SCREEN_HEIGHT*n)
+		}
+	}
+
+	width, height := windowWidth, windowWidth*SCREEN_HEIGHT/SCREEN_WIDTH
+	if height > windowHeight {
+		width, height = windowHeight*SCREEN_WIDTH/SCREEN_HEIGHT, windowHeight
+	}
+	return centered(windowWidth, windowHeight, width, height)
+}
+
+func centered(windowWidth, windowHeight, width, height int32) Viewport {
+	return Viewport{X: (windowWidth - width) / 2, Y: (windowHeight - height) / 2, Width: width, Height: height}
+}
+
+/*   Window pixels per virtual unit   */
+func (v Viewport) Scale() (x, y float32) {
+	return float32(v.Width) / SCREEN_WIDTH, float32(v.Height) / SCREEN_HEIGHT
+}
+
+/*   How many canvas pixels per virtual unit:   */
+/*   enough that the canvas is never magnified  */
+/*   by much, so a 4K TV stays sharp            */
+func (v Viewport) RenderScale() int32 {
+	x, y := v.Scale()
+	scale := int32(math.Ceil(float64(max(x, y)) - 0.05))
+	return max(1, min(scale, maxRenderScale))
+}
+
+func (v Viewport) Rect() rl.Rectangle {
+	return rl.Rectangle{X: float32(v.X), Y: float32(v.Y), Width: float32(v.Width), Height: float32(v.Height)}
+}
+
+/**************************************************/
+/*                                                */
+/*                   CANVAS                       */
+/*   A render texture the frame is drawn into in  */
+/*   virtual units, then scaled to the viewport   */
+/*                                                */
+/**************************************************/
+
+type Canvas struct {
+	target rl.RenderTexture2D
+	scale  int32
+	loaded bool
+}
+
+/*   Scissor rectangles are in canvas pixels,   */
+/*   so clips scale by this while drawing       */
+var clipScale int32 = 1
+
+/*   Only reallocates when the scale changes    */
+func (c *Canvas) Resize(scale int32) {
+	if c.loaded && c.scale == scale {
+		return
+	}
+	c.Unload()
+	c.target = rl.LoadRenderTexture(SCREEN_WIDTH*scale, SCREEN_HEIGHT*scale)
+	rl.SetTextureFilter(c.target.Texture, rl.FilterBilinear)
+	c.scale, c.loaded = scale, true
+}
+
+func (c *Canvas) Scale() int32 {
+	return c.scale
+}
+
+func (c *Canvas) Begin() {
+	rl.BeginTextureMode(c.target)
+	rl.ClearBackground(rl.Black)
+	rl.BeginMode2D(rl.Camera2D{Zoom: float32(c.scale)})
+	clipScale = c.scale
+}
+
+func (c *Canvas) End() {
+	rl.EndMode2D()
+	rl.EndTextureMode()
+	clipScale = 1
+}
+
+/*   Render textures are stored upside down     */
+func (c *Canvas) Draw(dest rl.Rectangle, tint rl.Color) {
+	if !c.loaded {
+		return
+	}
+	texture := c.target.Texture
+	source := rl.Rectangle{Width: float32(texture.Width), Height: -float32(texture.Height)}
+	rl.DrawTexturePro(texture, source, dest, rl.Vector2{}, 0, tint)
+}
+
+func (c *Canvas) Unload() {
+	if c.loaded {
+		rl.UnloadRenderTexture(c.target)
+		c.loaded = false
+	}
+}
+
+/*   rl.BeginScissorMode in virtual units       */
+func beginClip(x, y, width, height int32) {
+	rl.BeginScissorMode(x*clipScale, y*clipScale, width*clipScale, height*clipScale)
+}
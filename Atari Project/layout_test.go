@@ -0,0 +1,119 @@
+package main
+
+import "testing"
+
+/**************************************/
+/*                                    */
+/*       Virtual Layout Tests         */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+func TestComputeViewport(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int32
+		mode          ScaleMode
+		want          Viewport
+	}{
+		/*   Native size is the same in every mode   */
+		{"native fit", 1280, 720, ScaleFit, Viewport{0, 0, 1280, 720}},
+		{"native integer", 1280, 720, ScaleInteger, Viewport{0, 0, 1280, 720}},
+		{"native stretch", 1280, 720, ScaleStretch, Viewport{0, 0, 1280, 720}},
+
+		{"1080p fit", 1920, 1080, ScaleFit, Viewport{0, 0, 1920, 1080}},
+		{"1080p integer", 1920, 1080, ScaleInteger, Viewport{320, 180, 1280, 720}},
+		{"1440p integer", 2560, 1440, ScaleInteger, Viewport{0, 0, 2560, 1440}},
+		{"4K integer", 3840, 2160, ScaleInteger, Viewport{0, 0, 3840, 2160}},
+
+		/*   4:3 gets bars top and bottom   */
+		{"4:3 fit", 1024, 768, ScaleFit, Viewport{0, 96, 1024, 576}},
+		{"4:3 integer below 1x fits", 1024, 768, ScaleInteger, Viewport{0, 96, 1024, 576}},
+		{"4:3 stretch", 1024, 768, ScaleStretch, Viewport{0, 0, 1024, 768}},
+
+		/*   21:9 gets bars left and right   */
+		{"ultrawide fit", 3440, 1440, ScaleFit, Viewport{440, 0, 2560, 1440}},
+		{"ultrawide integer", 3440, 1440, ScaleInteger, Viewport{440, 0, 2560, 1440}},
+
+		/*   Odd sizes round down and stay centred   */
+		{"laptop fit", 1366, 768, ScaleFit, Viewport{0, 0, 1366, 768}},
+		{"laptop integer", 1366, 768, ScaleInteger, Viewport{43, 24, 1280, 720}},
+		{"one pixel over fit", 1281, 721, ScaleFit, Viewport{0, 0, 1281, 720}},
+		{"one pixel over integer", 1281, 721, ScaleInteger, Viewport{0, 0, 1280, 720}},
+		{"tiny", 1, 1, ScaleFit, Viewport{0, 0, 1, 0}},
+
+		{"very tall fit", 720, 4000, ScaleFit, Viewport{0, 1797, 720, 405}},
+		{"very tall integer", 720, 4000, ScaleInteger, Viewport{0, 1797, 720, 405}},
+		{"very tall stretch", 720, 4000, ScaleStretch, Viewport{0, 0, 720, 4000}},
+		{"very wide fit", 10000, 300, ScaleFit, Viewport{4733, 0, 533, 300}},
+		{"very wide integer", 10000, 300, ScaleInteger, Viewport{4733, 0, 533, 300}},
+		{"very wide stretch", 10000, 300, ScaleStretch, Viewport{0, 0, 10000, 300}},
+
+		/*   Minimized windows report 0x0   */
+		{"empty fit", 0, 0, ScaleFit, Viewport{}},
+		{"empty integer", 0, 0, ScaleInteger, Viewport{}},
+		{"empty stretch", 0, 0, ScaleStretch, Viewport{}},
+		{"no width", 0, 720, ScaleFit, Viewport{}},
+		{"no height", 1280, 0, ScaleStretch, Viewport{}},
+		{"negative", -1280, 720, ScaleFit, Viewport{}},
+
+		{"unknown mode fits", 1024, 768, ScaleMode("zoom"), Viewport{0, 96, 1024, 576}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComputeViewport(tt.width, tt.height, tt.mode)
+			if got != tt.want {
+				t.Errorf("ComputeViewport(%d, %d, %s) = %+v, want %+v", tt.width, tt.height, tt.mode, got, tt.want)
+			}
+			if got.X < 0 || got.Y < 0 || got.X+got.Width > max(tt.width, 0) || got.Y+got.Height > max(tt.height, 0) {
+				t.Errorf("viewport %+v spills out of a %dx%d window", got, tt.width, tt.height)
+			}
+		})
+	}
+}
+
+func TestViewportRenderScale(t *testing.T) {
+	tests := []struct {
+		name string
+		view Viewport
+		want int32
+	}{
+		{"empty", Viewport{}, 1},
+		{"half size", Viewport{Width: 640, Height: 360}, 1},
+		{"native", Viewport{Width: 1280, Height: 720}, 1},
+		{"within the slack", Viewport{Width: 1300, Height: 731}, 1},
+		{"just past the slack", Viewport{Width: 1366, Height: 768}, 2},
+		{"1080p", Viewport{Width: 1920, Height: 1080}, 2},
+		{"1440p", Viewport{Width: 2560, Height: 1440}, 2},
+		{"4K", Viewport{Width: 3840, Height: 2160}, 3},
+		{"8K is capped", Viewport{Width: 7680, Height: 4320}, maxRenderScale},
+		{"stretched tall uses the larger axis", Viewport{Width: 1280, Height: 2000}, 3},
+		{"stretched wide uses the larger axis", Viewport{Width: 3000, Height: 720}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.view.RenderScale(); got != tt.want {
+				t.Errorf("RenderScale of %dx%d = %d, want %d", tt.view.Width, tt.view.Height, got, tt.want)
+			}
+		})
+	}
+}
+
+/*   Fit and integer keep the 16:9 shape to     */
+/*   within a pixel of rounding                 */
+func TestViewportKeepsAspect(t *testing.T) {
+	for _, mode := range []ScaleMode{ScaleFit, ScaleInteger} {
+		for width := int32(200); width <= 4000; width += 173 {
+			for height := int32(150); height <= 3000; height += 211 {
+				view := ComputeViewport(width, height, mode)
+				ideal := view.Width * SCREEN_HEIGHT / SCREEN_WIDTH
+				if diff := view.Height - ideal; diff < -1 || diff > 1 {
+					t.Errorf("%s %dx%d: viewport %dx%d is not 16:9", mode, width, height, view.Width, view.Height)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,753 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"retro-gaming-ui/backend/client"
+	"retro-gaming-ui/backend/config"
+	"retro-gaming-ui/backend/launcher"
+	"retro-gaming-ui/backend/library"
+	"retro-gaming-ui/backend/logging"
+	"retro-gaming-ui/backend/server"
+)
+
+/**************************************/
+/*                                    */
+/*    Backend Library Feed & Cache    */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*              CONNECTION STATE                  */
+/*                                                */
+/**************************************************/
+
+type ConnState int
+
+const (
+	ConnConnecting ConnState = iota
+	ConnOnline
+	ConnOffline
+)
+
+/*   Cached list the grid shows while offline   */
+const gameCacheFile = "frontend_games.json"
+
+const (
+	retryMin    = 2 * time.Second
+	retryMax    = 30 * time.Second
+	feedTimeout = 10 * time.Second
+
+	/*   launch_game waits for the pre-launch hook */
+	launchTimeout = launcher.HookTimeout + feedTimeout
+
+	/*   status pings measure latency and keep the */
+	/*   connection inside the idle timeout        */
+	pollInterval = 5 * time.Second
+	scanTimeout  = 30 * time.Minute
+	logLines     = 50
+)
+
+/*   What the render loop reads each frame      */
+type FeedStatus struct {
+	State    ConnState
+	Addr     string
+	Loading  bool
+	Cached   bool /*   games came from the cache   */
+	Error    string
+	RetryAt  time.Time
+	Version  int /*   bumped whenever Games changes */
+	Games    []library.GameInfo
+	LastSync time.Time
+
+	Launching     string            /*   game ID awaiting launch_game */
+	Playing       *launcher.Session /*   while an emulator runs       */
+	Ended         *launcher.Session /*   the last finished session    */
+	LaunchError   string
+	LaunchErrorAt time.Time
+
+	Latency      time.Duration /*   last status round trip       */
+	Protocol     string
+	Clients      int
+	Scanning     bool
+	Progress     library.ScanProgress
+	LastScan     *library.ScanReport
+	ScanResult   string /*   reply to our own scan or cancel */
+	ScanResultAt time.Time
+	Logs         []logging.Record /*   oldest first               */
+
+	Media        []library.MediaItem
+	MediaVersion int /*   bumped whenever Media changes */
+	MediaLoading bool
+	MediaError   string
+
+	Notice   string /*   failed one-off calls, e.g. favorites */
+	NoticeAt time.Time
+}
+
+/**************************************************/
+/*                                                */
+/*                LIBRARY FEED                    */
+/*   Keeps a copy of the backend's game list on   */
+/*   a goroutine; the raylib loop only polls it   */
+/*                                                */
+/**************************************************/
+
+type LibraryFeed struct {
+	addr      string
+	token     string
+	cachePath string
+
+	mu     sync.Mutex
+	status FeedStatus
+	conn   *client.Client /*   nil while offline */
+	kick   chan struct{}  /*   cuts a retry wait short */
+}
+
+func NewLibraryFeed(cfg *config.Config) *LibraryFeed {
+	token := os.Getenv(config.EnvPrefix + "TOKEN")
+	if token == "" {
+		token, _ = client.LoadToken(cfg.IPC.TokensFile, "local")
+	}
+	f := &LibraryFeed{
+		addr:      cfg.IPC.Listen,
+		token:     token,
+		cachePath: filepath.Join(cfg.Dir, gameCacheFile),
+		status:    FeedStatus{State: ConnConnecting, Addr: cfg.IPC.Listen, Loading: true},
+		kick:      make(chan struct{}, 1),
+	}
+	if games, err := f.loadCache(); err == nil {
+		f.status.Games, f.status.Cached, f.status.Version = games, true, 1
+	}
+	return f
+}
+
+func (f *LibraryFeed) Status() FeedStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.status
+}
+
+func (f *LibraryFeed) update(change func(*FeedStatus)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	change(&f.status)
+}
+
+/*   Drops the connection and dials again at    */
+/*   once                                       */
+func (f *LibraryFeed) Reconnect() {
+	f.mu.Lock()
+	conn := f.conn
+	f.mu.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+	select {
+	case f.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (f *LibraryFeed) SetAddr(addr string) {
+	f.mu.Lock()
+	changed := addr != f.addr
+	f.addr, f.status.Addr = addr, addr
+	f.mu.Unlock()
+	if changed {
+		f.Reconnect()
+	}
+}
+
+/*   Connects, reconnects with backoff, and     */
+/*   returns once ctx is done                   */
+func (f *LibraryFeed) Run(ctx context.Context) {
+	wait := retryMin
+	for ctx.Err() == nil {
+		f.update(func(s *FeedStatus) { s.State, s.Loading = ConnConnecting, true })
+
+		err := f.session(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			wait = retryMin /*   was online; retry quickly */
+		}
+		retryAt := time.Now().Add(wait)
+		f.update(func(s *FeedStatus) {
+			s.State, s.Loading, s.RetryAt = ConnOffline, false, retryAt
+			if err != nil {
+				s.Error = err.Error()
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-f.kick:
+			wait = retryMin
+			continue
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, retryMax)
+	}
+}
+
+/*   One connection: nil once it was online and  */
+/*   the backend went away                       */
+func (f *LibraryFeed) session(ctx context.Context) error {
+	dialCtx, cancel := context.WithTimeout(ctx, feedTimeout)
+	defer cancel()
+
+	f.mu.Lock()
+	addr := f.addr
+	f.mu.Unlock()
+	conn, err := client.Dial(dialCtx, addr)
+	if err != nil {
+		return fmt.Errorf("no backend at %s", addr)
+	}
+	defer conn.Close()
+
+	/*   Unblocks the event loop on shutdown       */
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	if _, err := conn.Hello(dialCtx, "frontend", f.token); err != nil {
+		return err
+	}
+	topics := []string{
+		server.TopicLibraryChanged, server.TopicGameStarted, server.TopicGameExited,
+		server.TopicScanProgress,
+	}
+	if err := conn.Subscribe(dialCtx, topics...); err != nil {
+		return err
+	}
+	if err := f.reload(ctx, conn); err != nil {
+		return err
+	}
+
+	f.loadLogs(dialCtx, conn)
+
+	/*   A game may already be running, e.g. after  */
+	/*   the frontend was restarted                 */
+	playing, err := f.poll(ctx, conn)
+	if err != nil {
+		return err
+	}
+	f.update(func(s *FeedStatus) { s.State, s.Playing = ConnOnline, playing })
+	f.mu.Lock()
+	f.conn = conn
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.conn = nil
+		f.status.Playing, f.status.Launching = nil, ""
+		f.mu.Unlock()
+	}()
+
+	/*   A backend that stops answering is dropped  */
+	/*   so Run dials it again; a pending launch    */
+	/*   holds the connection, so no ping then      */
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if f.Status().Launching != "" {
+					continue
+				}
+				playing, err := f.poll(ctx, conn)
+				if err != nil {
+					conn.Close()
+					return
+				}
+				f.update(func(s *FeedStatus) { s.syncPlaying(playing) })
+			}
+		}
+	}()
+
+	for event := range conn.Events() {
+		switch event.Topic {
+		case server.TopicLibraryChanged:
+			if err := f.reload(ctx, conn); err != nil {
+				return err
+			}
+		case server.TopicGameStarted:
+			var session launcher.Session
+			if json.Unmarshal(event.Data, &session) == nil {
+				f.update(func(s *FeedStatus) { s.Playing = &session })
+			}
+		case server.TopicGameExited:
+			var exited struct {
+				Session launcher.Session `json:"session"`
+			}
+			if json.Unmarshal(event.Data, &exited) == nil {
+				f.update(func(s *FeedStatus) { s.Playing, s.Ended = nil, &exited.Session })
+			}
+		case server.TopicScanProgress:
+			var progress library.ScanProgress
+			if json.Unmarshal(event.Data, &progress) == nil {
+				f.update(func(s *FeedStatus) { s.Progress, s.Scanning = progress, !progress.Done })
+			}
+		case server.TopicLogRecord:
+			var record logging.Record
+			if json.Unmarshal(event.Data, &record) == nil {
+				f.update(func(s *FeedStatus) { s.Logs = appendLogs(s.Logs, record) })
+			}
+		}
+	}
+	return nil
+}
+
+func (f *LibraryFeed) reload(ctx context.Context, conn *client.Client) error {
+	f.update(func(s *FeedStatus) { s.Loading = true })
+	ctx, cancel := context.WithTimeout(ctx, feedTimeout)
+	defer cancel()
+
+	var games []library.GameInfo
+	if err := conn.Call(ctx, server.MsgTypeListGames, server.GameListPayload{}, &games); err != nil {
+		return err
+	}
+	cacheErr := ""
+	if err := f.saveCache(games); err != nil {
+		cacheErr = fmt.Sprintf("cannot cache library: %v", err)
+	}
+	f.update(func(s *FeedStatus) {
+		s.Games, s.Cached, s.Loading, s.LastSync, s.Error = games, false, false, time.Now(), cacheErr
+		s.Version++
+	})
+	return nil
+}
+
+/**************************************************/
+/*                                                */
+/*                 LAUNCHING                      */
+/*   Returns at once; Playing is set when the     */
+/*   backend has started the emulator             */
+/*                                                */
+/**************************************************/
+
+func (f *LibraryFeed) Launch(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case f.status.Launching != "" || f.status.Playing != nil:
+		return
+	case f.conn == nil:
+		f.status.LaunchError, f.status.LaunchErrorAt = "backend offline - cannot launch games", time.Now()
+		return
+	}
+	f.status.Launching, f.status.LaunchError = id, ""
+
+	conn := f.conn
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), launchTimeout)
+		defer cancel()
+
+		var session launcher.Session
+		err := conn.Call(ctx, server.MsgTypeLaunchGame, id, &session)
+		f.update(func(s *FeedStatus) {
+			s.Launching = ""
+			if err != nil {
+				s.LaunchError, s.LaunchErrorAt = callErrorText(err), time.Now()
+				return
+			}
+			/*   game.exited may beat the reply when   */
+			/*   the emulator dies at once             */
+			if s.Playing == nil && (s.Ended == nil || s.Ended.ID != session.ID) {
+				s.Playing = &session
+			}
+		})
+	}()
+}
+
+/*   The list refreshes from library.changed,   */
+/*   so only a failure needs reporting          */
+func (f *LibraryFeed) ToggleFavorite(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conn == nil {
+		f.status.Notice, f.status.NoticeAt = "backend offline - cannot change favorites", time.Now()
+		return
+	}
+
+	conn := f.conn
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), feedTimeout)
+		defer cancel()
+		if err := conn.Call(ctx, server.MsgTypeToggleFavorite, id, nil); err != nil {
+			f.update(func(s *FeedStatus) { s.Notice, s.NoticeAt = callErrorText(err), time.Now() })
+		}
+	}()
+}
+
+/*   The backend's message without the code     */
+func callErrorText(err error) string {
+	var callErr *client.Error
+	if errors.As(err, &callErr) {
+		return callErr.Message
+	}
+	return err.Error()
+}
+
+/**************************************************/
+/*                                                */
+/*              BACKEND TELEMETRY                 */
+/*                                                */
+/**************************************************/
+
+/*   One status round trip; returns the game    */
+/*   running on the backend, if any             */
+func (f *LibraryFeed) poll(ctx context.Context, conn *client.Client) (*launcher.Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, feedTimeout)
+	defer cancel()
+
+	var backend struct {
+		ProtocolVersion string              `json:"protocol_version"`
+		Clients         int                 `json:"clients"`
+		Scanning        bool                `json:"scanning"`
+		LastScan        *library.ScanReport `json:"last_scan"`
+		Playing         *launcher.Session   `json:"playing"`
+	}
+	start := time.Now()
+	if err := conn.Call(ctx, server.MsgTypeStatus, nil, &backend); err != nil {
+		return nil, err
+	}
+	latency := time.Since(start)
+	f.update(func(s *FeedStatus) {
+		s.Latency, s.Protocol, s.Clients = latency, backend.ProtocolVersion, backend.Clients
+		s.Scanning, s.LastScan = backend.Scanning, backend.LastScan
+	})
+	return backend.Playing, nil
+}
+
+/*   Backs up game.started and game.exited,     */
+/*   which the client drops when its buffer is  */
+/*   full. A launch in flight settles Playing   */
+/*   itself, and a session already reported as  */
+/*   ended is a stale answer                    */
+func (s *FeedStatus) syncPlaying(playing *launcher.Session) {
+	if s.Launching != "" {
+		return
+	}
+	if playing != nil && s.Ended != nil && s.Ended.ID == playing.ID {
+		return
+	}
+	s.Playing = playing
+}
+
+/*   Logs need an admin token; without one the  */
+/*   subscribe is refused and the panel stays   */
+/*   empty                                      */
+func (f *LibraryFeed) loadLogs(ctx context.Context, conn *client.Client) {
+	if err := conn.Subscribe(ctx, server.TopicLogRecord); err != nil {
+		return
+	}
+	var records []logging.Record
+	err := conn.Call(ctx, server.MsgTypeGetLogs, server.GetLogsPayload{Limit: logLines}, &records)
+	if err != nil {
+		return
+	}
+	f.update(func(s *FeedStatus) { s.Logs = records })
+}
+
+/*   Copies, so a Status taken earlier keeps    */
+/*   its slice unchanged                        */
+func appendLogs(logs []logging.Record, record logging.Record) []logging.Record {
+	logs = append(append(make([]logging.Record, 0, len(logs)+1), logs...), record)
+	if len(logs) > logLines {
+		logs = logs[len(logs)-logLines:]
+	}
+	return logs
+}
+
+/**************************************************/
+/*                                                */
+/*                SCAN CONTROL                    */
+/*   The backend answers one request at a time    */
+/*   per connection, so the scan gets its own     */
+/*   and the feed keeps polling meanwhile         */
+/*                                                */
+/**************************************************/
+
+func (f *LibraryFeed) Scan() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case f.status.Scanning:
+		return
+	case f.conn == nil:
+		f.status.ScanResult, f.status.ScanResultAt = "backend offline - cannot scan", time.Now()
+		return
+	}
+	f.status.Scanning, f.status.Progress, f.status.ScanResult = true, library.ScanProgress{}, ""
+
+	addr, token := f.addr, f.token
+	go func() {
+		result, err := runScan(addr, token)
+		if err != nil {
+			result = callErrorText(err)
+		}
+		f.update(func(s *FeedStatus) {
+			s.Scanning, s.ScanResult, s.ScanResultAt = false, result, time.Now()
+		})
+	}()
+}
+
+func runScan(addr, token string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), scanTimeout)
+	defer cancel()
+
+	conn, err := client.Dial(ctx, addr)
+	if err != nil {
+		return "", fmt.Errorf("no backend at %s", addr)
+	}
+	defer conn.Close()
+	if _, err := conn.Hello(ctx, "frontend-scan", token); err != nil {
+		return "", err
+	}
+	var result string
+	err = conn.Call(ctx, server.MsgTypeScan, nil, &result)
+	return result, err
+}
+
+/*   The scan call then fails with canceled,    */
+/*   which Scan reports                         */
+func (f *LibraryFeed) CancelScan() {
+	f.mu.Lock()
+	conn, scanning := f.conn, f.status.Scanning
+	f.mu.Unlock()
+	if conn == nil || !scanning {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), feedTimeout)
+		defer cancel()
+		if err := conn.Call(ctx, server.MsgTypeCancelScan, nil, nil); err != nil {
+			f.update(func(s *FeedStatus) { s.ScanResult, s.ScanResultAt = callErrorText(err), time.Now() })
+		}
+	}()
+}
+
+/**************************************************/
+/*                                                */
+/*                  MEDIA LIST                    */
+/*   Fetched when the MEDIA blade asks; the       */
+/*   files themselves are read from disk          */
+/*                                                */
+/**************************************************/
+
+func (f *LibraryFeed) LoadMedia() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case f.status.MediaLoading:
+		return
+	case f.conn == nil:
+		f.status.MediaError = "backend offline"
+		return
+	}
+	f.status.MediaLoading, f.status.MediaError = true, ""
+
+	conn := f.conn
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), feedTimeout)
+		defer cancel()
+
+		var items []library.MediaItem
+		err := conn.Call(ctx, server.MsgTypeGetMedia, server.MediaPayload{}, &items)
+		f.update(func(s *FeedStatus) {
+			s.MediaLoading = false
+			if err != nil {
+				s.MediaError = callErrorText(err)
+				return
+			}
+			s.Media = items
+			s.MediaVersion++
+		})
+	}()
+}
+
+/**************************************************/
+/*                                                */
+/*                OFFLINE CACHE                   */
+/*                                                */
+/**************************************************/
+
+func (f *LibraryFeed) loadCache() ([]library.GameInfo, error) {
+	data, err := os.ReadFile(f.cachePath)
+	if err != nil {
+		return nil, err
+	}
+	var games []library.GameInfo
+	if err := json.Unmarshal(data, &games); err != nil {
+		return nil, fmt.Errorf("%s: %w", f.cachePath, err)
+	}
+	return games, nil
+}
+
+/*   Written beside the config, then renamed, so */
+/*   a crash never leaves half a cache           */
+func (f *LibraryFeed) saveCache(games []library.GameInfo) error {
+	if f.cachePath == "" {
+		return errors.New("no cache path")
+	}
+	data, err := json.Marshal(games)
+	if err != nil {
+		return err
+	}
+	os.MkdirAll(filepath.Dir(f.cachePath), 0755)
+	tmp := f.cachePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.cachePath)
+}
+
+/**************************************************/
+/*                                                */
+/*           GAMEINFO -> GAMEITEM MAPPING         */
+/*                                                */
+/**************************************************/
+
+var platformNames = map[string]string{
+	"NES":   "Nintendo NES",
+	"SNES":  "Super Nintendo",
+	"N64":   "Nintendo 64",
+	"GBA":   "Game Boy Advance",
+	"GB":    "Game Boy",
+	"ATARI": "Atari 2600",
+}
+
+/*   Replaced when a theme is applied   */
+var platformColors = map[string]rl.Color{
+	"NES":   AccentPink,
+	"SNES":  AccentPurple,
+	"N64":   NeonGreenBright,
+	"GBA":   AccentCyan,
+	"GB":    NeonGreen,
+	"ATARI": AccentOrange,
+}
+
+var platformShapes = map[string]int{
+	"NES": 0, "SNES": 1, "N64": 2, "GBA": 3, "GB": 0, "ATARI": 2,
+}
+
+/*   Card text is drawn at 14px and 10px in a   */
+/*   160px card                                 */
+const (
+	cardTitleRunes = 16
+	cardDescRunes  = 26
+)
+
+func gameItemFromInfo(game library.GameInfo) GameItem {
+	color, ok := platformColors[game.Platform]
+	if !ok {
+		color = TextGray
+	}
+
+	desc := game.Description
+	if desc == "" {
+		desc = platformNames[game.Platform]
+		if desc == "" {
+			desc = game.Platform
+		}
+	}
+	if game.PlayCount > 0 {
+		desc = fmt.Sprintf("%s | %d play", game.Platform, game.PlayCount)
+		if game.PlayCount > 1 {
+			desc += "s"
+		}
+		if game.PlaySeconds >= 60 {
+			desc += " | " + formatPlayTime(time.Duration(game.PlaySeconds)*time.Second)
+		}
+	}
+
+	return GameItem{
+		ID:          game.ID,
+		Title:       ellipsize(strings.ToUpper(game.Title), cardTitleRunes),
+		Description: ellipsize(desc, cardDescRunes),
+		Color:       color,
+		IconText:    initials(game.Title),
+		IconShape:   platformShapes[game.Platform],
+		Favorite:    game.Favorite,
+	}
+}
+
+/*   In title order, which letter jumps rely on */
+func gameItemsFromInfo(games []library.GameInfo) []GameItem {
+	sorted := append([]library.GameInfo{}, games...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToUpper(sorted[i].Title) < strings.ToUpper(sorted[j].Title)
+	})
+	items := make([]GameItem, 0, len(sorted))
+	for _, game := range sorted {
+		items = append(items, gameItemFromInfo(game))
+	}
+	return items
+}
+
+/*   "Pixel Quest" -> "PQ", "Metroid" -> "ME"   */
+func initials(title string) string {
+	words := strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	letters := make([]rune, 0, 2)
+	for _, word := range words {
+		if len(letters) == 2 {
+			break
+		}
+		letters = append(letters, []rune(word)[0])
+	}
+	if len(letters) == 1 && len([]rune(words[0])) > 1 {
+		letters = append(letters, []rune(words[0])[1])
+	}
+	if len(letters) == 0 {
+		return "??"
+	}
+	return strings.ToUpper(string(letters))
+}
+
+/*   "1h12m", "25m"                             */
+func formatPlayTime(d time.Duration) string {
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", hours, minutes)
+}
+
+func ellipsize(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-2]) + ".."
+}
@@ -1,10 +1,20 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"retro-gaming-ui/backend/config"
+	"retro-gaming-ui/backend/launcher"
 )
 
 /**************************************/
@@ -26,11 +36,16 @@ import (
 const (
 	SCREEN_WIDTH  = 1280
 	SCREEN_HEIGHT = 720
+
+	/*   Enough for the overlay clock   */
+	PLAYING_FPS = 10
 )
 
 /**************************************************/
 /*                                                */
 /*       NEON GREEN FRUTIGER AERO PALETTE         */
+/*   The built-in theme; a theme from theme.go    */
+/*   overwrites these when it is applied          */
 /*                                                */
 /**************************************************/
 
@@ -42,10 +57,22 @@ var (
 	NeonGreenBright = rl.Color{R: 100, G: 255, B: 100, A: 255}
 
 	/*  Frutiger Aero backgrounds - glossy, clean style  */
-	AeroBackground = rl.Color{R: 15, G: 25, B: 35, A: 255}
-	AeroDarkPanel  = rl.Color{R: 20, G: 35, B: 50, A: 230}
-	AeroGloss      = rl.Color{R: 255, G: 255, B: 255, A: 50}
-	AeroShadow     = rl.Color{R: 0, G: 0, B: 0, A: 150}
+	AeroBackground    = rl.Color{R: 15, G: 25, B: 35, A: 255}
+	AeroBackgroundLow = rl.Color{R: 5, G: 12, B: 22, A: 255}
+	AeroHeader        = rl.Color{R: 8, G: 15, B: 25, A: 255}
+	AeroDarkPanel     = rl.Color{R: 20, G: 35, B: 50, A: 230}
+	AeroSelected      = rl.Color{R: 25, G: 50, B: 35, A: 245}
+	AeroGloss         = rl.Color{R: 255, G: 255, B: 255, A: 50}
+	AeroShadow        = rl.Color{R: 0, G: 0, B: 0, A: 150}
+	AeroBladeBorder   = rl.Color{R: 60, G: 70, B: 80, A: 180}
+
+	/*           Cards and water droplets                */
+	AeroCardTop     = rl.Color{R: 35, G: 45, B: 55, A: 255}
+	AeroCardBottom  = rl.Color{R: 20, G: 28, B: 38, A: 255}
+	AeroCardBorder  = rl.Color{R: 50, G: 60, B: 70, A: 255}
+	AeroIconWell    = rl.Color{R: 25, G: 35, B: 45, A: 255}
+	AeroDroplet     = rl.Color{R: 180, G: 220, B: 255, A: 255}
+	AeroDropletGlow = rl.Color{R: 200, G: 240, B: 255, A: 255}
 
 	/*                   Accent colors                   */
 	AccentCyan   = rl.Color{R: 0, G: 255, B: 255, A: 255}
@@ -56,9 +83,6 @@ var (
 	/*                    Text colors                    */
 	TextWhite = rl.Color{R: 255, G: 255, B: 255, A: 255}
 	TextGray  = rl.Color{R: 180, G: 180, B: 180, A: 255}
-
-	/*              Global animation time                */
-	globalTime float32 = 0
 )
 
 /**************************************************/
@@ -68,11 +92,13 @@ var (
 /**************************************************/
 
 type GameItem struct {
+	ID          string /*   backend game ID   */
 	Title       string
 	Description string
 	Color       rl.Color
 	IconText    string
 	IconShape   int
+	Favorite    bool
 }
 
 /**************************************************/
@@ -81,13 +107,22 @@ type GameItem struct {
 /*                                                */
 /**************************************************/
 
+/*   Blade order in BladeNav.Categories   */
+const (
+	BLADE_GAMES = iota
+	BLADE_MEDIA
+	BLADE_SETTINGS
+	BLADE_NETWORK
+)
+
 type BladeNav struct {
 	Categories     []string
 	Icons          []string
 	CurrentBlade   int
 	TargetBlade    int
 	TransitionTime float32
-	BladeWidth     float32
+	BladeWidth     float32 /*   slot per blade, gap included   */
+	BladeHeight    int32
 }
 
 func NewBladeNav() *BladeNav {
@@ -97,12 +132,17 @@ func NewBladeNav() *BladeNav {
 		CurrentBlade: 0,
 		TargetBlade:  0,
 		BladeWidth:   280,
+		BladeHeight:  50,
 	}
 }
 
-func (b *BladeNav) Update() {
+func (b *BladeNav) SetGeometry(geometry BladeGeometry) {
+	b.BladeWidth, b.BladeHeight = geometry.Width, geometry.Height
+}
+
+func (b *BladeNav) Update(dt float32) {
 	if b.TransitionTime < 1.0 {
-		b.TransitionTime += rl.GetFrameTime() * 3.0
+		b.TransitionTime += dt * 3.0
 		if b.TransitionTime > 1.0 {
 			b.TransitionTime = 1.0
 			b.CurrentBlade = b.TargetBlade
@@ -137,7 +177,7 @@ func (b *BladeNav) Draw() {
 	for i := 0; i < len(b.Categories); i++ {
 		x := startX + float32(i)*b.BladeWidth - offset + b.BladeWidth*float32(b.CurrentBlade)
 		isActive := i == b.TargetBlade
-		drawAeroBlade(int32(x), 85, int32(b.BladeWidth-15), 50, isActive, b.Icons[i], b.Categories[i])
+		drawAeroBlade(int32(x), 85, int32(b.BladeWidth-15), b.BladeHeight, isActive, b.Icons[i], b.Categories[i])
 	}
 }
 
@@ -152,60 +192,271 @@ type GameGrid struct {
 	SelectedIndex int
 	HoverScale    float32
 	ScrollOffset  float32
-	TargetScroll  float32
+	TargetScroll  float32 /*   vertical, in pixels   */
 	bounceTime    float32
+	letterFlash   float32 /*   1 after a letter jump, fades to 0 */
+	Cards         CardGeometry
 }
 
+/*   Starts empty; games arrive from the feed   */
 func NewGameGrid() *GameGrid {
 	return &GameGrid{
-		Games: []GameItem{
-			{Title: "RETRO RACER", Description: "High-speed arcade racing", Color: NeonGreen, IconText: "RR", IconShape: 0},
-			{Title: "PIXEL QUEST", Description: "8-bit adventure game", Color: AccentCyan, IconText: "PQ", IconShape: 1},
-			{Title: "NEON BLASTER", Description: "Shoot-em-up action", Color: AccentPurple, IconText: "NB", IconShape: 2},
-			{Title: "SYNTH BEATS", Description: "Rhythm game", Color: AccentPink, IconText: "SB", IconShape: 3},
-			{Title: "CYBER MAZE", Description: "Puzzle challenge", Color: NeonGreenBright, IconText: "CM", IconShape: 0},
-			{Title: "STAR FORCE", Description: "Space shooter", Color: AccentOrange, IconText: "SF", IconShape: 1},
-		},
+		Games:         []GameItem{},
 		SelectedIndex: 0,
 		HoverScale:    1.0,
+		Cards:         DefaultTheme().Cards,
+	}
+}
+
+/*   Jumps straight to the new scroll; a theme  */
+/*   switch fades over it                       */
+func (g *GameGrid) SetCards(cards CardGeometry) {
+	g.Cards = cards
+	g.TargetScroll = 0
+	g.TargetScroll = g.scrollFor(g.SelectedIndex)
+	g.ScrollOffset = g.TargetScroll
+}
+
+/*   Keeps the selected game selected when the  */
+/*   list is refreshed around it                */
+func (g *GameGrid) SetGames(games []GameItem) {
+	selectedID := ""
+	if g.SelectedIndex < len(g.Games) {
+		selectedID = g.Games[g.SelectedIndex].ID
+	}
+	g.Games = games
+	g.SelectedIndex = 0
+	for i, game := range games {
+		if game.ID == selectedID {
+			g.SelectedIndex = i
+			break
+		}
+	}
+	g.TargetScroll = g.scrollFor(g.SelectedIndex)
+}
+
+func (g *GameGrid) Selected() (GameItem, bool) {
+	if g.SelectedIndex >= len(g.Games) {
+		return GameItem{}, false
+	}
+	return g.Games[g.SelectedIndex], true
+}
+
+func (g *GameGrid) Find(id string) (GameItem, bool) {
+	for _, game := range g.Games {
+		if game.ID == id {
+			return game, true
+		}
+	}
+	return GameItem{}, false
+}
+
+func (g *GameGrid) Update(dt float32) {
+	g.HoverScale = lerp(g.HoverScale, 1.12, dt*8.0)
+	g.ScrollOffset = lerp(g.ScrollOffset, g.TargetScroll, min(1, dt*10.0))
+	g.bounceTime += dt
+	g.letterFlash = max(0, g.letterFlash-dt*1.5)
+}
+
+/**************************************************/
+/*                                                */
+/*                GRID NAVIGATION                 */
+/*   Moves return false at an edge so the caller  */
+/*   can hand the key to the blades instead       */
+/*                                                */
+/**************************************************/
+
+func (g *GameGrid) selectIndex(index int) {
+	index = max(0, min(index, len(g.Games)-1))
+	if index == g.SelectedIndex || index < 0 {
+		return
+	}
+	g.SelectedIndex = index
+	g.HoverScale = 1.0
+	g.bounceTime = 0
+	g.TargetScroll = g.scrollFor(index)
+}
+
+func (g *GameGrid) MoveLeft() bool {
+	if len(g.Games) == 0 || g.SelectedIndex%gridColumns == 0 {
+		return false
+	}
+	g.selectIndex(g.SelectedIndex - 1)
+	return true
+}
+
+func (g *GameGrid) MoveRight() bool {
+	if g.SelectedIndex%gridColumns == gridColumns-1 || g.SelectedIndex >= len(g.Games)-1 {
+		return false
+	}
+	g.selectIndex(g.SelectedIndex + 1)
+	return true
+}
+
+func (g *GameGrid) MoveUp() {
+	if g.SelectedIndex >= gridColumns {
+		g.selectIndex(g.SelectedIndex - gridColumns)
+	}
+}
+
+/*   Into a shorter last row, lands on its end  */
+func (g *GameGrid) MoveDown() {
+	if g.row(g.SelectedIndex) < g.row(len(g.Games)-1) {
+		g.selectIndex(g.SelectedIndex + gridColumns)
+	}
+}
+
+func (g *GameGrid) PageUp() {
+	g.selectIndex(g.SelectedIndex - g.visibleRows()*gridColumns)
+}
+
+func (g *GameGrid) PageDown() {
+	g.selectIndex(g.SelectedIndex + g.visibleRows()*gridColumns)
+}
+
+func (g *GameGrid) First() {
+	g.selectIndex(0)
+}
+
+func (g *GameGrid) Last() {
+	g.selectIndex(len(g.Games) - 1)
+}
+
+/*   Games are in title order, so each letter   */
+/*   is one run of cards                         */
+func (g *GameGrid) NextLetter() {
+	if g.SelectedIndex >= len(g.Games) {
+		return
+	}
+	current := gameLetter(g.Games[g.SelectedIndex])
+	for i := g.SelectedIndex + 1; i < len(g.Games); i++ {
+		if gameLetter(g.Games[i]) != current {
+			g.jumpTo(i)
+			return
+		}
+	}
+}
+
+/*   To the start of this letter, then to the   */
+/*   start of the one before                     */
+func (g *GameGrid) PrevLetter() {
+	if g.SelectedIndex >= len(g.Games) || g.SelectedIndex == 0 {
+		return
 	}
+	i := g.SelectedIndex
+	if gameLetter(g.Games[i-1]) != gameLetter(g.Games[i]) {
+		i--
+	}
+	letter := gameLetter(g.Games[i])
+	for i > 0 && gameLetter(g.Games[i-1]) == letter {
+		i--
+	}
+	g.jumpTo(i)
+}
+
+/*   First title starting with text, else the  */
+/*   first one containing it                    */
+func (g *GameGrid) FindTitle(text string) bool {
+	text = strings.ToUpper(text)
+	for _, match := range []func(title, text string) bool{strings.HasPrefix, strings.Contains} {
+		for i, game := range g.Games {
+			if match(strings.ToUpper(game.Title), text) {
+				g.jumpTo(i)
+				return true
+			}
+		}
+	}
+	return false
 }
 
-func (g *GameGrid) Update() {
-	g.HoverScale = lerp(g.HoverScale, 1.12, rl.GetFrameTime()*8.0)
-	g.ScrollOffset = lerp(g.ScrollOffset, g.TargetScroll, rl.GetFrameTime()*6.0)
-	g.bounceTime += rl.GetFrameTime()
+func (g *GameGrid) jumpTo(index int) {
+	g.selectIndex(index)
+	g.letterFlash = 1.0
 }
 
-func (g *GameGrid) SelectNext() {
-	if g.SelectedIndex < len(g.Games)-1 {
-		g.SelectedIndex++
-		g.HoverScale = 1.0
-		g.bounceTime = 0
+/*   "#" for titles that start with a digit or  */
+/*   symbol                                     */
+func gameLetter(game GameItem) string {
+	for _, r := range game.Title {
+		if r >= 'A' && r <= 'Z' {
+			return string(r)
+		}
+		break
 	}
+	return "#"
+}
+
+/**************************************************/
+/*                                                */
+/*                 GRID LAYOUT                    */
+/*   Rows scroll vertically inside a clipped      */
+/*   viewport; only rows in view are drawn        */
+/*                                                */
+/**************************************************/
+
+const (
+	gridColumns = 6
+
+	/*   Room above and below for the selected    */
+	/*   card's glow and hover scale               */
+	gridPad        = int32(14)
+	gridViewTop    = int32(150)
+	gridViewBottom = int32(SCREEN_HEIGHT - 74)
+	gridViewHeight = gridViewBottom - gridViewTop - 2*gridPad
+)
+
+func (g *GameGrid) rowPitch() int32 {
+	return g.Cards.Height + g.Cards.Spacing
+}
+
+func (g *GameGrid) row(index int) int {
+	return index / gridColumns
+}
+
+func (g *GameGrid) rows() int {
+	return (len(g.Games) + gridColumns - 1) / gridColumns
+}
+
+/*   Whole rows that fit in the viewport        */
+func (g *GameGrid) visibleRows() int {
+	return max(1, int((gridViewHeight+g.Cards.Spacing)/g.rowPitch()))
 }
 
-func (g *GameGrid) SelectPrev() {
-	if g.SelectedIndex > 0 {
-		g.SelectedIndex--
-		g.HoverScale = 1.0
-		g.bounceTime = 0
+/*   Least scroll that shows index's whole row  */
+func (g *GameGrid) scrollFor(index int) float32 {
+	contentHeight := int32(g.rows())*g.rowPitch() - g.Cards.Spacing
+	if contentHeight <= gridViewHeight {
+		return 0
 	}
+	rowTop := int32(g.row(index)) * g.rowPitch()
+	scroll := int32(g.TargetScroll)
+	if rowTop < scroll {
+		scroll = rowTop
+	} else if rowTop+g.Cards.Height > scroll+gridViewHeight {
+		scroll = rowTop + g.Cards.Height - gridViewHeight
+	}
+	return float32(max(0, min(scroll, contentHeight-gridViewHeight)))
 }
 
 func (g *GameGrid) Draw() {
-	cardWidth := int32(160)
-	cardHeight := int32(220)
-	spacing := int32(20)
+	if len(g.Games) == 0 {
+		return
+	}
 
-	/*   Center the game grid on screen   */
-	totalWidth := int32(len(g.Games))*(cardWidth+spacing) - spacing
-	startX := (SCREEN_WIDTH - totalWidth) / 2
-	startY := int32(190)
+	/*   A single short row stays centered   */
+	columns := int32(min(len(g.Games), gridColumns))
+	rowWidth := columns*(g.Cards.Width+g.Cards.Spacing) - g.Cards.Spacing
+	startX := (SCREEN_WIDTH - rowWidth) / 2
+	startY := gridViewTop + gridPad - int32(g.ScrollOffset)
 
-	for i, game := range g.Games {
-		x := startX + int32(i)*(cardWidth+spacing)
-		y := startY
+	firstRow := max(0, int((g.ScrollOffset-float32(gridPad))/float32(g.rowPitch())))
+	lastRow := min(g.rows()-1, int((g.ScrollOffset+float32(gridViewHeight+gridPad))/float32(g.rowPitch())))
+
+	/*   Clip loosely: glow may spill a little   */
+	beginClip(0, gridViewTop-10, SCREEN_WIDTH, gridViewBottom-gridViewTop+20)
+	for i := firstRow * gridColumns; i < min(len(g.Games), (lastRow+1)*gridColumns); i++ {
+		x := startX + int32(i%gridColumns)*(g.Cards.Width+g.Cards.Spacing)
+		y := startY + int32(g.row(i))*g.rowPitch()
 		isSelected := i == g.SelectedIndex
 
 		scale := float32(1.0)
@@ -216,8 +467,63 @@ func (g *GameGrid) Draw() {
 			bounceOffset = float32(math.Sin(float64(g.bounceTime)*3)) * 4
 		}
 
-		drawGameCard(x, y-int32(bounceOffset), cardWidth, cardHeight, scale, isSelected, game)
+		drawGameCard(x, y-int32(bounceOffset), g.Cards.Width, g.Cards.Height, scale, isSelected, g.Games[i])
+	}
+	rl.EndScissorMode()
+
+	g.drawPosition()
+	if g.rows() > g.visibleRows() {
+		g.drawLetterStrip()
 	}
+	g.drawLetterFlash()
+}
+
+/*   "12 / 3400" under the bottom-right corner  */
+func (g *GameGrid) drawPosition() {
+	text := fmt.Sprintf("%d / %d", g.SelectedIndex+1, len(g.Games))
+	width := measureText(text, 12)
+	drawText(text, SCREEN_WIDTH-60-width, gridViewBottom+6, 12, TextGray)
+}
+
+/*   A-Z down the right edge; letters with no   */
+/*   games are dimmed                            */
+func (g *GameGrid) drawLetterStrip() {
+	present := make(map[string]bool)
+	for _, game := range g.Games {
+		present[gameLetter(game)] = true
+	}
+	current := gameLetter(g.Games[g.SelectedIndex])
+
+	letters := "#ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	step := float32(gridViewBottom-gridViewTop-2*gridPad) / float32(len(letters))
+	x := int32(SCREEN_WIDTH - 34)
+	for i, r := range letters {
+		letter := string(r)
+		y := gridViewTop + gridPad + int32(float32(i)*step)
+		color := rl.Color{R: TextGray.R, G: TextGray.G, B: TextGray.B, A: 70}
+		if present[letter] {
+			color = TextGray
+		}
+		if letter == current {
+			rl.DrawCircle(x+4, y+5, 9, NeonGreenGlow)
+			color = NeonGreen
+		}
+		drawText(letter, x, y, 12, color)
+	}
+}
+
+/*   Big fading letter after a letter jump      */
+func (g *GameGrid) drawLetterFlash() {
+	if g.letterFlash <= 0 {
+		return
+	}
+	letter := gameLetter(g.Games[g.SelectedIndex])
+	alpha := uint8(200 * g.letterFlash)
+	size := int32(120)
+	width := measureText(letter, size)
+	x, y := (SCREEN_WIDTH-width)/2, (gridViewTop+gridViewBottom)/2-size/2
+	rl.DrawRectangle(x-40, y-20, width+80, size+40, rl.Color{R: AeroDarkPanel.R, G: AeroDarkPanel.G, B: AeroDarkPanel.B, A: alpha})
+	drawText(letter, x, y, size, rl.Color{R: NeonGreen.R, G: NeonGreen.G, B: NeonGreen.B, A: alpha})
 }
 
 /**************************************************/
@@ -232,6 +538,10 @@ type AeroDecorations struct {
 	droplets  []WaterDroplet
 	scanlineY float32
 	glowPulse float32
+	clock     float32
+	effects   EffectsPrefs
+	params    DecorationParams /*   from the theme                   */
+	rng       *rand.Rand       /*   seeded, so a headless run repeats */
 }
 
 type Bubble struct {
@@ -249,48 +559,80 @@ type WaterDroplet struct {
 	maxLife  float32
 }
 
-func NewAeroDecorations() *AeroDecorations {
-	dec := &AeroDecorations{
-		bubbles:  make([]Bubble, 20),
-		droplets: make([]WaterDroplet, 8),
+func NewAeroDecorations(effects EffectsPrefs, rng *rand.Rand) *AeroDecorations {
+	dec := &AeroDecorations{rng: rng, params: DefaultTheme().Decorations}
+	dec.Configure(effects)
+	return dec
+}
+
+/*   New sizes and speeds apply as particles    */
+/*   respawn; counts change at once             */
+func (d *AeroDecorations) SetParams(params DecorationParams) {
+	d.params = params
+	d.Configure(d.effects)
+}
+
+/*   Grows or trims the particles to the new    */
+/*   density; existing ones keep drifting       */
+func (d *AeroDecorations) Configure(effects EffectsPrefs) {
+	d.effects = effects
+	bubbles := d.params.Bubbles * effects.Density / 100
+	droplets := d.params.Droplets * effects.Density / 100
+	if !effects.Bubbles {
+		bubbles = 0
+	}
+	if !effects.Droplets {
+		droplets = 0
 	}
 
-	/*   Initialize floating bubbles   */
-	for i := range dec.bubbles {
-		dec.bubbles[i] = Bubble{
-			x:      float32(rl.GetRandomValue(0, SCREEN_WIDTH)),
-			y:      float32(rl.GetRandomValue(0, SCREEN_HEIGHT)),
-			radius: float32(rl.GetRandomValue(4, 25)),
-			speed:  float32(rl.GetRandomValue(20, 70)),
-			alpha:  uint8(rl.GetRandomValue(25, 70)),
-		}
+	for len(d.bubbles) < bubbles {
+		d.bubbles = append(d.bubbles, d.newBubble())
 	}
+	d.bubbles = d.bubbles[:bubbles]
+	for len(d.droplets) < droplets {
+		d.droplets = append(d.droplets, d.newDroplet())
+	}
+	d.droplets = d.droplets[:droplets]
+}
 
-	/*   Initialize water droplets   */
-	for i := range dec.droplets {
-		dec.droplets[i] = WaterDroplet{
-			x:       float32(rl.GetRandomValue(100, SCREEN_WIDTH-100)),
-			y:       float32(rl.GetRandomValue(100, SCREEN_HEIGHT-100)),
-			size:    float32(rl.GetRandomValue(15, 40)),
-			alpha:   uint8(rl.GetRandomValue(30, 80)),
-			maxLife: float32(rl.GetRandomValue(3, 8)),
-		}
+/*   Inclusive, like rl.GetRandomValue   */
+func randomIn(rng *rand.Rand, lo, hi int) int {
+	return lo + rng.Intn(hi-lo+1)
+}
+
+/*   A floating bubble somewhere on screen   */
+func (d *AeroDecorations) newBubble() Bubble {
+	return Bubble{
+		x:      float32(randomIn(d.rng, 0, SCREEN_WIDTH)),
+		y:      float32(randomIn(d.rng, 0, SCREEN_HEIGHT)),
+		radius: float32(randomIn(d.rng, d.params.BubbleRadius[0], d.params.BubbleRadius[1])),
+		speed:  float32(randomIn(d.rng, d.params.BubbleSpeed[0], d.params.BubbleSpeed[1])),
+		alpha:  uint8(randomIn(d.rng, 25, 70)),
 	}
+}
 
-	return dec
+/*   A water droplet away from the edges   */
+func (d *AeroDecorations) newDroplet() WaterDroplet {
+	return WaterDroplet{
+		x:       float32(randomIn(d.rng, 100, SCREEN_WIDTH-100)),
+		y:       float32(randomIn(d.rng, 100, SCREEN_HEIGHT-100)),
+		size:    float32(randomIn(d.rng, d.params.DropletSize[0], d.params.DropletSize[1])),
+		alpha:   uint8(randomIn(d.rng, 30, 80)),
+		maxLife: float32(randomIn(d.rng, 3, 8)),
+	}
 }
 
-func (d *AeroDecorations) Update() {
-	dt := rl.GetFrameTime()
+func (d *AeroDecorations) Update(dt float32) {
+	d.clock += dt
 
 	/*   Update bubbles - float upward   */
 	for i := range d.bubbles {
 		d.bubbles[i].y -= d.bubbles[i].speed * dt
-		d.bubbles[i].x += float32(math.Sin(float64(globalTime+float32(i)))) * 0.5
+		d.bubbles[i].x += float32(math.Sin(float64(d.clock+float32(i)))) * 0.5
 
 		if d.bubbles[i].y < -d.bubbles[i].radius {
 			d.bubbles[i].y = SCREEN_HEIGHT + d.bubbles[i].radius
-			d.bubbles[i].x = float32(rl.GetRandomValue(0, SCREEN_WIDTH))
+			d.bubbles[i].x = float32(randomIn(d.rng, 0, SCREEN_WIDTH))
 		}
 	}
 
@@ -299,13 +641,13 @@ func (d *AeroDecorations) Update() {
 		d.droplets[i].lifeTime += dt
 		if d.droplets[i].lifeTime > d.droplets[i].maxLife {
 			d.droplets[i].lifeTime = 0
-			d.droplets[i].x = float32(rl.GetRandomValue(100, SCREEN_WIDTH-100))
-			d.droplets[i].y = float32(rl.GetRandomValue(100, SCREEN_HEIGHT-100))
-			d.droplets[i].size = float32(rl.GetRandomValue(15, 40))
+			d.droplets[i].x = float32(randomIn(d.rng, 100, SCREEN_WIDTH-100))
+			d.droplets[i].y = float32(randomIn(d.rng, 100, SCREEN_HEIGHT-100))
+			d.droplets[i].size = float32(randomIn(d.rng, d.params.DropletSize[0], d.params.DropletSize[1]))
 		}
 	}
 
-	d.scanlineY += 180 * dt
+	d.scanlineY += d.params.ScanlineSpeed * dt
 	if d.scanlineY > SCREEN_HEIGHT {
 		d.scanlineY = 0
 	}
@@ -335,22 +677,25 @@ func (d *AeroDecorations) Draw() {
 		fadeAlpha := uint8(float32(drop.alpha) * (1 - lifeRatio*0.5))
 
 		/*   Droplet body - ellipse shape   */
-		rl.DrawEllipse(int32(drop.x), int32(drop.y), drop.size*0.7, drop.size, rl.Color{R: 180, G: 220, B: 255, A: fadeAlpha / 2})
+		rl.DrawEllipse(int32(drop.x), int32(drop.y), drop.size*0.7, drop.size, rl.Color{R: AeroDroplet.R, G: AeroDroplet.G, B: AeroDroplet.B, A: fadeAlpha / 2})
 
 		/*   Inner glow   */
-		rl.DrawEllipse(int32(drop.x), int32(drop.y), drop.size*0.5, drop.size*0.7, rl.Color{R: 200, G: 240, B: 255, A: fadeAlpha / 3})
+		rl.DrawEllipse(int32(drop.x), int32(drop.y), drop.size*0.5, drop.size*0.7, rl.Color{R: AeroDropletGlow.R, G: AeroDropletGlow.G, B: AeroDropletGlow.B, A: fadeAlpha / 3})
 
 		/*   Highlight   */
 		rl.DrawCircle(int32(drop.x-drop.size*0.2), int32(drop.y-drop.size*0.4), drop.size*0.2, rl.Color{R: 255, G: 255, B: 255, A: fadeAlpha})
 	}
 
 	/*   Scanline effect   */
-	scanAlpha := uint8(10)
-	rl.DrawRectangle(0, int32(d.scanlineY), SCREEN_WIDTH, 2, rl.Color{R: 255, G: 255, B: 255, A: scanAlpha})
+	if d.effects.Scanline {
+		scanAlpha := uint8(10)
+		rl.DrawRectangle(0, int32(d.scanlineY), SCREEN_WIDTH, 2, rl.Color{R: 255, G: 255, B: 255, A: scanAlpha})
+	}
 
 	/*   Bottom glow   */
 	glowIntensity := uint8(20 + 12*float32(math.Sin(float64(d.glowPulse))))
-	rl.DrawRectangleGradientV(0, SCREEN_HEIGHT-70, SCREEN_WIDTH, 70,
+	glowHeight := d.params.GlowHeight
+	rl.DrawRectangleGradientV(0, SCREEN_HEIGHT-glowHeight, SCREEN_WIDTH, glowHeight,
 		rl.Color{R: 0, G: 0, B: 0, A: 0},
 		rl.Color{R: NeonGreen.R, G: NeonGreen.G, B: NeonGreen.B, A: glowIntensity})
 }
@@ -364,7 +709,7 @@ func (d *AeroDecorations) Draw() {
 func drawAeroBlade(x, y, width, height int32, isActive bool, icon, text string) {
 	panelColor := AeroDarkPanel
 	if isActive {
-		panelColor = rl.Color{R: 25, G: 50, B: 35, A: 245}
+		panelColor = AeroSelected
 	}
 
 	/*   Shadow   */
@@ -384,12 +729,12 @@ func drawAeroBlade(x, y, width, height int32, isActive bool, icon, text string)
 		rl.DrawRectangleLines(x, y, width, height, NeonGreen)
 
 		/*   Icon   */
-		rl.DrawText(icon, x+12, y+14, 22, NeonGreen)
-		rl.DrawText(text, x+38, y+15, 20, NeonGreen)
+		drawTitleText(icon, x+12, y+(height-22)/2, 22, NeonGreen)
+		drawTitleText(text, x+38, y+(height-20)/2, 20, NeonGreen)
 	} else {
-		rl.DrawRectangleLines(x, y, width, height, rl.Color{R: 60, G: 70, B: 80, A: 180})
-		rl.DrawText(icon, x+12, y+14, 22, TextGray)
-		rl.DrawText(text, x+38, y+15, 20, TextGray)
+		rl.DrawRectangleLines(x, y, width, height, AeroBladeBorder)
+		drawTitleText(icon, x+12, y+(height-22)/2, 22, TextGray)
+		drawTitleText(text, x+38, y+(height-20)/2, 20, TextGray)
 	}
 }
 
@@ -417,8 +762,7 @@ func drawGameCard(x, y, width, height int32, scale float32, isSelected bool, gam
 
 	/*   Card background   */
 	rl.DrawRectangleGradientV(drawX, drawY, scaledWidth, scaledHeight,
-		rl.Color{R: 35, G: 45, B: 55, A: 255},
-		rl.Color{R: 20, G: 28, B: 38, A: 255})
+		AeroCardTop, AeroCardBottom)
 
 	/*   Top accent bar   */
 	rl.DrawRectangle(drawX, drawY, scaledWidth, 5, game.Color)
@@ -435,8 +779,7 @@ func drawGameCard(x, y, width, height int32, scale float32, isSelected bool, gam
 	/*   Icon background with shape   */
 	rl.DrawCircle(iconCenterX, iconCenterY, iconRadius+3,
 		rl.Color{R: game.Color.R, G: game.Color.G, B: game.Color.B, A: 180})
-	rl.DrawCircle(iconCenterX, iconCenterY, iconRadius,
-		rl.Color{R: 25, G: 35, B: 45, A: 255})
+	rl.DrawCircle(iconCenterX, iconCenterY, iconRadius, AeroIconWell)
 
 	/*   Inner shape decoration   */
 	switch game.IconShape {
@@ -470,75 +813,244 @@ func drawGameCard(x, y, width, height int32, scale float32, isSelected bool, gam
 	}
 
 	/*   Icon letter   */
-	textWidth := rl.MeasureText(game.IconText, 22)
-	rl.DrawText(game.IconText, iconCenterX-textWidth/2, iconCenterY-10, 22, game.Color)
+	textWidth := measureText(game.IconText, 22)
+	drawText(game.IconText, iconCenterX-textWidth/2, iconCenterY-10, 22, game.Color)
 
 	/*   Icon highlight   */
 	rl.DrawCircle(iconCenterX-int32(iconRadius*0.4), iconCenterY-int32(iconRadius*0.4), iconRadius*0.2, rl.Color{R: 255, G: 255, B: 255, A: 60})
 
+	/*   Favorite star   */
+	if game.Favorite {
+		star := rl.Vector2{X: float32(drawX + scaledWidth - 16), Y: float32(drawY + 18)}
+		rl.DrawPoly(star, 3, 8, -90, AccentPink)
+		rl.DrawPoly(star, 3, 8, 90, AccentPink)
+	}
+
 	/*   Title   */
 	titleY := drawY + scaledHeight - 55
-	rl.DrawText(game.Title, drawX+10, titleY, 14, TextWhite)
+	drawText(game.Title, drawX+10, titleY, 14, TextWhite)
 
 	/*   Description   */
 	descY := titleY + 18
-	rl.DrawText(game.Description, drawX+10, descY, 10, TextGray)
+	drawText(game.Description, drawX+10, descY, 10, TextGray)
 
 	/*   Border   */
-	borderColor := rl.Color{R: 50, G: 60, B: 70, A: 255}
+	borderColor := AeroCardBorder
 	if isSelected {
 		borderColor = game.Color
 	}
 	rl.DrawRectangleLines(drawX, drawY, scaledWidth, scaledHeight, borderColor)
 }
 
-func drawHeader() {
+func drawHeader(header HeaderText) {
 	rl.DrawRectangleGradientV(0, 0, SCREEN_WIDTH, 80,
-		rl.Color{R: 8, G: 15, B: 25, A: 255},
-		AeroBackground)
+		AeroHeader, AeroBackground)
 
 	/*   Glass shine   */
 	rl.DrawRectangleGradientV(0, 0, SCREEN_WIDTH, 40, AeroGloss, rl.Color{A: 0})
 
 	/*   Title centered   */
-	title := "ARCADE PROJECT"
-	titleWidth := rl.MeasureText(title, 30)
+	title := header.Title
+	titleWidth := measureTitleText(title, 30)
 	titleX := (SCREEN_WIDTH - titleWidth) / 2
 
-	rl.DrawText(title, titleX+2, 17, 30, rl.Color{R: 57, G: 255, B: 20, A: 40})
-	rl.DrawText(title, titleX+1, 16, 30, NeonGreenGlow)
-	rl.DrawText(title, titleX, 15, 30, NeonGreen)
+	drawTitleText(title, titleX+2, 17, 30, rl.Color{R: NeonGreen.R, G: NeonGreen.G, B: NeonGreen.B, A: 40})
+	drawTitleText(title, titleX+1, 16, 30, NeonGreenGlow)
+	drawTitleText(title, titleX, 15, 30, NeonGreen)
 
 	/*   Subtitle   */
-	subtitle := "FRUTIGER AERO | Y2K EDITION"
-	subWidth := rl.MeasureText(subtitle, 10)
-	rl.DrawText(subtitle, (SCREEN_WIDTH-subWidth)/2, 48, 10, TextGray)
+	subtitle := header.Subtitle
+	subWidth := measureText(subtitle, 10)
+	drawText(subtitle, (SCREEN_WIDTH-subWidth)/2, 48, 10, TextGray)
 
-	/*   Date right side   */
-	rl.DrawText("02.01.2026", SCREEN_WIDTH-110, 20, 16, AccentCyan)
+	/*   Date right side; a longer tag moves left   */
+	tagX := min(SCREEN_WIDTH-110, SCREEN_WIDTH-30-measureText(header.Tag, 16))
+	drawText(header.Tag, tagX, 20, 16, AccentCyan)
 
 	/*   Decorative line   */
 	rl.DrawRectangle(100, 75, SCREEN_WIDTH-200, 1, rl.Color{R: NeonGreen.R, G: NeonGreen.G, B: NeonGreen.B, A: 50})
 }
 
-func drawFooter() {
+func drawFooter(actions *ActionMap) {
 	footerY := int32(SCREEN_HEIGHT - 40)
 
 	rl.DrawRectangleGradientV(0, footerY, SCREEN_WIDTH, 40,
-		rl.Color{A: 0}, rl.Color{R: 8, G: 15, B: 25, A: 220})
+		rl.Color{A: 0}, rl.Color{R: AeroHeader.R, G: AeroHeader.G, B: AeroHeader.B, A: 220})
 
 	/*   Decorative line   */
 	rl.DrawRectangle(100, footerY+5, SCREEN_WIDTH-200, 1, rl.Color{R: NeonGreen.R, G: NeonGreen.G, B: NeonGreen.B, A: 50})
 
 	/*   Control hints with arrow symbols   */
-	controls := "<< >>  NAVIGATE    ^v  SELECT    [ENTER] LAUNCH    [ESC] EXIT"
-	ctrlWidth := rl.MeasureText(controls, 12)
-	rl.DrawText(controls, (SCREEN_WIDTH-ctrlWidth)/2, footerY+16, 12, TextGray)
+	controls := fmt.Sprintf("<< >> ^v  NAVIGATE    [%s/%s] PAGE    [%s] FIND    [%s] FAVORITE    [%s] LAUNCH    [ESC] EXIT",
+		actions.Label(ActPageUp), actions.Label(ActPageDown), actions.Label(ActSearch),
+		actions.Label(ActFavorite), actions.Label(ActSelect))
+	ctrlWidth := measureText(controls, 12)
+	drawText(controls, (SCREEN_WIDTH-ctrlWidth)/2, footerY+16, 12, TextGray)
 
 	/*   FPS and version   */
 	fps := fmt.Sprintf("%d FPS", rl.GetFPS())
-	rl.DrawText(fps, SCREEN_WIDTH-100, footerY+16, 12, AccentCyan)
-	rl.DrawText("v1.0", 50, footerY+16, 12, NeonGreenDark)
+	drawText(fps, SCREEN_WIDTH-100, footerY+16, 12, AccentCyan)
+	drawText("v1.0", 50, footerY+16, 12, NeonGreenDark)
+}
+
+/**************************************************/
+/*                                                */
+/*          LIBRARY CONNECTION STATUS             */
+/*                                                */
+/**************************************************/
+
+func drawConnectionPill(status FeedStatus, clock float32) {
+	dotColor := NeonGreen
+	label := "ONLINE  " + status.Addr
+	switch {
+	case status.State == ConnConnecting:
+		dotColor = AccentOrange
+		label = "CONNECTING..."
+	case status.State == ConnOffline && status.Cached:
+		dotColor = AccentPink
+		label = "OFFLINE - CACHED"
+	case status.State == ConnOffline:
+		dotColor = AccentPink
+		label = "OFFLINE"
+	case status.Loading:
+		label = "SYNCING..."
+	}
+
+	/*   Pulse while waiting on the backend   */
+	if status.State != ConnOnline || status.Loading {
+		dotColor.A = uint8(150 + 105*math.Abs(math.Sin(float64(clock)*3)))
+	}
+
+	x, y := int32(30), int32(20)
+	width := measureText(label, 12) + 34
+	rl.DrawRectangle(x, y, width, 22, AeroDarkPanel)
+	rl.DrawRectangleGradientV(x, y, width, 11, AeroGloss, rl.Color{A: 0})
+	rl.DrawRectangleLines(x, y, width, 22, rl.Color{R: dotColor.R, G: dotColor.G, B: dotColor.B, A: 120})
+	rl.DrawCircle(x+13, y+11, 5, dotColor)
+	drawText(label, x+26, y+5, 12, TextWhite)
+}
+
+/*   Shown in place of the grid until there are */
+/*   games, and under it while offline          */
+func drawLibraryStatus(status FeedStatus, hasGames bool, clock float32) {
+	retry := ""
+	if wait := time.Until(status.RetryAt); status.State == ConnOffline && wait > 0 {
+		retry = fmt.Sprintf("retrying in %ds", int(wait.Seconds())+1)
+	}
+
+	if hasGames {
+		if status.State == ConnOffline {
+			note := "BACKEND OFFLINE - SHOWING CACHED LIBRARY   " + retry
+			drawText(note, 60, gridViewBottom+6, 12, AccentPink)
+		}
+		return
+	}
+
+	title, detail := "", ""
+	titleColor := TextGray
+	switch {
+	case status.State == ConnOffline:
+		title, detail, titleColor = "BACKEND OFFLINE", status.Error+"   "+retry, AccentPink
+	case status.State == ConnConnecting || status.Loading:
+		dots := int(clock*3) % 4
+		title = "LOADING LIBRARY" + "..."[:dots]
+		detail = "connecting to " + status.Addr
+	default:
+		title = "NO GAMES YET"
+		detail = "add a ROM folder with: hubctl add-path <dir>, then hubctl scan"
+	}
+
+	titleWidth := measureText(title, 26)
+	drawText(title, (SCREEN_WIDTH-titleWidth)/2, 280, 26, titleColor)
+	detailWidth := measureText(detail, 12)
+	drawText(detail, (SCREEN_WIDTH-detailWidth)/2, 320, 12, TextGray)
+}
+
+/**************************************************/
+/*                                                */
+/*           NOW PLAYING / LAUNCH TOASTS          */
+/*                                                */
+/**************************************************/
+
+const (
+	toastSeconds   = 4
+	errorSeconds   = 8
+	toastMaxLength = 110
+)
+
+/*   Covers the whole UI; seen when the window  */
+/*   is restored while the emulator still runs  */
+func drawNowPlaying(session launcher.Session, grid *GameGrid, clock float32) {
+	game, ok := grid.Find(session.GameID)
+	if !ok {
+		game = GameItem{Title: session.GameID, Color: NeonGreen}
+	}
+
+	rl.DrawRectangle(0, 0, SCREEN_WIDTH, SCREEN_HEIGHT, rl.Color{R: 0, G: 0, B: 0, A: 200})
+
+	width, height := int32(560), int32(240)
+	x, y := (SCREEN_WIDTH-width)/2, (SCREEN_HEIGHT-height)/2
+	rl.DrawRectangle(x+5, y+5, width, height, AeroShadow)
+	rl.DrawRectangleGradientV(x, y, width, height,
+		AeroCardTop, AeroCardBottom)
+	rl.DrawRectangle(x, y, width, 5, game.Color)
+	rl.DrawRectangleGradientV(x, y, width, height/3, AeroGloss, rl.Color{A: 0})
+	rl.DrawRectangleLines(x, y, width, height, game.Color)
+
+	centered := func(text string, ty, size int32, color rl.Color) {
+		tw := measureText(text, size)
+		drawText(text, x+(width-tw)/2, ty, size, color)
+	}
+
+	/*   Pulsing label   */
+	pulse := uint8(160 + 95*math.Abs(math.Sin(float64(clock)*2)))
+	centered("NOW PLAYING", y+24, 16, rl.Color{R: NeonGreen.R, G: NeonGreen.G, B: NeonGreen.B, A: pulse})
+	centered(game.Title, y+56, 28, TextWhite)
+	centered(session.Platform+"  |  "+filepath.Base(session.Command), y+94, 12, TextGray)
+
+	elapsed := time.Since(session.Started).Round(time.Second)
+	played := fmt.Sprintf("%02d:%02d:%02d", int(elapsed.Hours()), int(elapsed.Minutes())%60, int(elapsed.Seconds())%60)
+	centered(played, y+124, 44, game.Color)
+
+	centered("Quit the emulator to return", y+height-34, 12, TextGray)
+}
+
+/*   Launch progress, failures and the summary  */
+/*   of the session that just ended             */
+func drawLaunchToast(status FeedStatus, grid *GameGrid) {
+	title := func(id string) string {
+		if game, ok := grid.Find(id); ok {
+			return game.Title
+		}
+		return id
+	}
+
+	text, color := "", NeonGreen
+	switch {
+	case status.Launching != "":
+		text = "LAUNCHING " + title(status.Launching) + "..."
+	case status.LaunchError != "" && time.Since(status.LaunchErrorAt) < errorSeconds*time.Second:
+		text, color = "CANNOT LAUNCH: "+status.LaunchError, AccentPink
+	case status.Notice != "" && time.Since(status.NoticeAt) < errorSeconds*time.Second:
+		text, color = status.Notice, AccentPink
+	case status.Ended != nil && time.Since(status.Ended.Ended) < toastSeconds*time.Second:
+		played := time.Duration(status.Ended.Seconds * float64(time.Second)).Round(time.Second)
+		text = fmt.Sprintf("%s - PLAYED %s", title(status.Ended.GameID), played)
+		if status.Ended.Crashed {
+			text, color = fmt.Sprintf("%s - EMULATOR EXITED WITH CODE %d", title(status.Ended.GameID), status.Ended.ExitCode), AccentOrange
+		}
+	default:
+		return
+	}
+	text = ellipsize(text, toastMaxLength)
+
+	width := measureText(text, 14) + 40
+	x, y := (SCREEN_WIDTH-width)/2, int32(SCREEN_HEIGHT-92)
+	rl.DrawRectangle(x+3, y+3, width, 32, AeroShadow)
+	rl.DrawRectangle(x, y, width, 32, AeroDarkPanel)
+	rl.DrawRectangleGradientV(x, y, width, 16, AeroGloss, rl.Color{A: 0})
+	rl.DrawRectangleLines(x, y, width, 32, color)
+	drawText(text, x+20, y+9, 14, color)
 }
 
 /**************************************************/
@@ -555,6 +1067,41 @@ func easeOutCubic(t float32) float32 {
 	return 1 - float32(math.Pow(float64(1-t), 3))
 }
 
+/*   One whole frame; the ThemeFader also draws */
+/*   it into its snapshot                       */
+func drawScene(ui *Frontend, header HeaderText) {
+	status := ui.Status
+	rl.DrawRectangleGradientV(0, 0, SCREEN_WIDTH, SCREEN_HEIGHT,
+		AeroBackground, AeroBackgroundLow)
+
+	ui.Decorations.Draw()
+	drawHeader(header)
+	drawConnectionPill(status, ui.Clock)
+	ui.Blades.Draw()
+
+	switch ui.Blades.TargetBlade {
+	case BLADE_GAMES:
+		ui.Grid.Draw()
+		drawLibraryStatus(status, len(ui.Grid.Games) > 0, ui.Clock)
+	case BLADE_SETTINGS:
+		ui.Settings.Draw(ui.Clock)
+	case BLADE_MEDIA:
+		ui.Media.Draw(status, ui.Grid, ui.Clock)
+	case BLADE_NETWORK:
+		ui.Network.Draw(status)
+	}
+
+	drawFooter(ui.Actions)
+	drawLaunchToast(status, ui.Grid)
+	ui.Search.Draw(ui.Clock)
+	if ui.Remap.IsOpen() {
+		ui.Remap.Draw()
+	}
+	if status.Playing != nil {
+		drawNowPlaying(*status.Playing, ui.Grid, ui.Clock)
+	}
+}
+
 /**************************************************/
 /*                                                */
 /*              MAIN APPLICATION                  */
@@ -562,68 +1109,114 @@ func easeOutCubic(t float32) float32 {
 /**************************************************/
 
 func main() {
-	rl.InitWindow(SCREEN_WIDTH, SCREEN_HEIGHT, "Arcade Project v1.0 - Frutiger Aero Y2K Edition")
-	rl.SetTargetFPS(60)
+	headless := flag.Bool("headless", false, "run the input scripts given as arguments without a window, then exit")
+	flag.Parse()
+	if *headless {
+		os.Exit(runHeadless(flag.Args()))
+	}
 
-	bladeNav := NewBladeNav()
-	gameGrid := NewGameGrid()
-	decorations := NewAeroDecorations()
+	/*   Same config as the backend, for its address */
+	cfg, err := config.LoadFile("", "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config: %v; using defaults\n", err)
+		cfg = config.Default(config.DefaultDir())
+	}
+	prefs, err := LoadPrefs(cfg.Dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "preferences: %v; using defaults\n", err)
+	}
 
-	for !rl.WindowShouldClose() {
-		globalTime += rl.GetFrameTime()
+	/*   The user's themes, then the ones shipped  */
+	/*   beside the frontend                       */
+	themes, err := LoadThemes(filepath.Join(cfg.Dir, themesDir), themesDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "themes: %v\n", err)
+	}
 
-		/*   Input handling   */
-		if rl.IsKeyPressed(rl.KeyRight) {
-			bladeNav.NextBlade()
-		}
-		if rl.IsKeyPressed(rl.KeyLeft) {
-			bladeNav.PrevBlade()
+	bindings, err := LoadBindings(cfg.Dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bindings: %v\n", err)
+	}
+	/*   The old launch-key setting becomes a      */
+	/*   select binding, once                      */
+	if prefs.Input.LaunchKey != "" {
+		bindings.migrateLaunchKey(prefs.Input.LaunchKey)
+		prefs.Input.LaunchKey = ""
+		if err := bindings.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "bindings: %v\n", err)
 		}
+		prefs.Save()
+	}
 
-		if bladeNav.TargetBlade == 0 {
-			if rl.IsKeyPressed(rl.KeyDown) || rl.IsKeyPressed(rl.KeyS) {
-				gameGrid.SelectNext()
-			}
-			if rl.IsKeyPressed(rl.KeyUp) || rl.IsKeyPressed(rl.KeyW) {
-				gameGrid.SelectPrev()
+	/*   Any size works; the frame is letterboxed  */
+	flags := uint32(rl.FlagWindowResizable)
+	if prefs.Display.VSync {
+		flags |= rl.FlagVsyncHint
+	}
+	rl.SetConfigFlags(flags)
+	rl.InitWindow(prefs.Display.Width, prefs.Display.Height, "Arcade Project v1.0 - Frutiger Aero Y2K Edition")
+	rl.SetWindowMinSize(320, 180)
+	rl.InitAudioDevice()
+
+	feed := NewLibraryFeed(cfg)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var ui *Frontend
+	var fader *ThemeFader
+	ui = NewFrontend(prefs, bindings, raylibInput{}, feed, rand.New(rand.NewSource(time.Now().UnixNano())),
+		cfg.IPC.Listen, themeNames(themes), func(old Prefs) {
+			applyPrefs(prefs, &old, ui.Decorations, fader, feed, cfg.IPC.Listen)
+		})
+	fader = NewThemeFader(themes, ui.ApplyTheme)
+	canvas := &Canvas{}
+	applyPrefs(prefs, nil, ui.Decorations, fader, feed, cfg.IPC.Listen)
+	go feed.Run(ctx)
+
+	for !rl.WindowShouldClose() {
+		dt := rl.GetFrameTime()
+
+		wasPlaying := ui.Playing
+		ui.Update(dt, feed.Status())
+
+		/*   Step aside while the emulator runs   */
+		if ui.Playing != wasPlaying {
+			if ui.Playing {
+				rl.MinimizeWindow()
+				rl.SetTargetFPS(PLAYING_FPS)
+			} else {
+				rl.RestoreWindow()
+				rl.SetTargetFPS(prefs.Display.FPSCap)
 			}
 		}
-
-		bladeNav.Update()
-		gameGrid.Update()
-		decorations.Update()
-
-		/*   Drawing   */
-		rl.BeginDrawing()
-
-		rl.DrawRectangleGradientV(0, 0, SCREEN_WIDTH, SCREEN_HEIGHT,
-			AeroBackground,
-			rl.Color{R: 5, G: 12, B: 22, A: 255})
-
-		decorations.Draw()
-		drawHeader()
-		bladeNav.Draw()
-
-		if bladeNav.TargetBlade == 0 {
-			gameGrid.Draw()
+		if ui.CapturesEscape() {
+			rl.SetExitKey(rl.KeyNull)
 		} else {
-			content := ""
-			switch bladeNav.TargetBlade {
-			case 1:
-				content = "MEDIA CENTER - Coming Soon"
-			case 2:
-				content = "SYSTEM SETTINGS"
-			case 3:
-				content = "NETWORK STATUS"
-			}
-			contentWidth := rl.MeasureText(content, 26)
-			rl.DrawText(content, (SCREEN_WIDTH-contentWidth)/2, 280, 26, TextGray)
+			rl.SetExitKey(rl.KeyEscape)
 		}
+		ui.Media.Pump()
 
-		drawFooter()
+		/*   Drawing: the frame goes to the canvas in */
+		/*   virtual units, then out to the viewport; */
+		/*   resizes are picked up here each frame    */
+		view := ComputeViewport(int32(rl.GetScreenWidth()), int32(rl.GetScreenHeight()), prefs.Display.Scaling)
+		canvas.Resize(view.RenderScale())
+		fader.Update(dt, canvas.Scale(), func() { drawScene(ui, fader.Header()) })
 
+		canvas.Begin()
+		drawScene(ui, fader.Header())
+		fader.DrawFade()
+		canvas.End()
+
+		rl.BeginDrawing()
+		rl.ClearBackground(rl.Black)
+		canvas.Draw(view.Rect(), rl.White)
 		rl.EndDrawing()
 	}
 
+	cancel()
+	ui.Media.Close()
+	fader.Close()
+	canvas.Unload()
+	rl.CloseAudioDevice()
 	rl.CloseWindow()
 }
@@ -0,0 +1,741 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"retro-gaming-ui/backend/library"
+)
+
+/**************************************/
+/*                                    */
+/*     Media Blade & Thumbnail Cache  */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*               THUMBNAIL LOADER                 */
+/*   Workers decode and shrink PNG/JPEG files;    */
+/*   the render thread only uploads the pixels    */
+/*                                                */
+/**************************************************/
+
+const (
+	thumbMaxSize    = 480  /*   longest side after shrinking  */
+	thumbMaxSource  = 8192 /*   bigger files are refused      */
+	thumbWorkers    = 2
+	thumbUploads    = 2 /*   texture uploads per frame     */
+	thumbCacheSize  = 96
+	thumbQueueDepth = 64
+)
+
+type thumbState int
+
+const (
+	thumbPending thumbState = iota
+	thumbReady
+	thumbFailed
+)
+
+type thumb struct {
+	state   thumbState
+	texture rl.Texture2D
+	err     string
+	used    uint64 /*   frame it was last drawn */
+}
+
+type decodedThumb struct {
+	path   string
+	pixels *image.NRGBA
+	err    error
+}
+
+type ThumbLoader struct {
+	requests chan string
+	results  chan decodedThumb
+	thumbs   map[string]*thumb
+	frame    uint64
+}
+
+func NewThumbLoader() *ThumbLoader {
+	t := &ThumbLoader{
+		requests: make(chan string, thumbQueueDepth),
+		results:  make(chan decodedThumb, thumbQueueDepth),
+		thumbs:   make(map[string]*thumb),
+	}
+	for i := 0; i < thumbWorkers; i++ {
+		go t.work()
+	}
+	return t
+}
+
+func (t *ThumbLoader) work() {
+	for path := range t.requests {
+		pixels, err := decodeThumb(path)
+		t.results <- decodedThumb{path: path, pixels: pixels, err: err}
+	}
+}
+
+/*   Queues the file on first use; a full queue */
+/*   is simply asked again next frame           */
+func (t *ThumbLoader) Get(path string) *thumb {
+	th, ok := t.thumbs[path]
+	if !ok {
+		select {
+		case t.requests <- path:
+			th = &thumb{state: thumbPending}
+			t.thumbs[path] = th
+		default:
+			return &thumb{state: thumbPending}
+		}
+	}
+	th.used = t.frame
+	return th
+}
+
+/*   Render thread: uploads a few finished      */
+/*   decodes and drops the least used textures  */
+func (t *ThumbLoader) Update() {
+	t.frame++
+uploads:
+	for i := 0; i < thumbUploads; i++ {
+		select {
+		case result := <-t.results:
+			t.upload(result)
+		default:
+			break uploads
+		}
+	}
+	t.evict()
+}
+
+func (t *ThumbLoader) upload(result decodedThumb) {
+	th, ok := t.thumbs[result.path]
+	if !ok {
+		return /*   closed meanwhile */
+	}
+	if result.err != nil {
+		th.state, th.err = thumbFailed, result.err.Error()
+		return
+	}
+	bounds := result.pixels.Bounds()
+	img := rl.NewImage(result.pixels.Pix, int32(bounds.Dx()), int32(bounds.Dy()), 1, rl.UncompressedR8g8b8a8)
+	th.texture, th.state = rl.LoadTextureFromImage(img), thumbReady
+}
+
+func (t *ThumbLoader) evict() {
+	ready := make([]string, 0, len(t.thumbs))
+	for path, th := range t.thumbs {
+		if th.state == thumbReady {
+			ready = append(ready, path)
+		}
+	}
+	if len(ready) <= thumbCacheSize {
+		return
+	}
+	sort.Slice(ready, func(i, j int) bool { return t.thumbs[ready[i]].used < t.thumbs[ready[j]].used })
+	for _, path := range ready[:len(ready)-thumbCacheSize] {
+		if t.thumbs[path].used == t.frame-1 {
+			break /*   still on screen */
+		}
+		rl.UnloadTexture(t.thumbs[path].texture)
+		delete(t.thumbs, path)
+	}
+}
+
+func (t *ThumbLoader) Close() {
+	for _, th := range t.thumbs {
+		if th.state == thumbReady {
+			rl.UnloadTexture(th.texture)
+		}
+	}
+	t.thumbs = make(map[string]*thumb)
+}
+
+func decodeThumb(path string) (*image.NRGBA, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	/*   Check the size before allocating it       */
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return nil, err
+	}
+	if config.Width > thumbMaxSource || config.Height > thumbMaxSource {
+		return nil, fmt.Errorf("%dx%d is too large", config.Width, config.Height)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return shrink(img, thumbMaxSize), nil
+}
+
+/*   Averages whole blocks, so retro captures   */
+/*   small enough to keep stay pixel-sharp      */
+func shrink(img image.Image, maxSize int) *image.NRGBA {
+	bounds := img.Bounds()
+	factor := max(1, (max(bounds.Dx(), bounds.Dy())+maxSize-1)/maxSize)
+	width, height := max(1, bounds.Dx()/factor), max(1, bounds.Dy()/factor)
+
+	out := image.NewNRGBA(image.Rect(0, 0, width, height))
+	area := uint32(factor * factor)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var r, g, b, a uint32
+			for dy := 0; dy < factor; dy++ {
+				for dx := 0; dx < factor; dx++ {
+					c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x*factor+dx, bounds.Min.Y+y*factor+dy)).(color.NRGBA)
+					r, g, b, a = r+uint32(c.R), g+uint32(c.G), b+uint32(c.B), a+uint32(c.A)
+				}
+			}
+			i := out.PixOffset(x, y)
+			out.Pix[i], out.Pix[i+1], out.Pix[i+2], out.Pix[i+3] =
+				uint8(r/area), uint8(g/area), uint8(b/area), uint8(a/area)
+		}
+	}
+	return out
+}
+
+/**************************************************/
+/*                                                */
+/*                TRACK PLAYER                    */
+/*   Streams through raylib's audio device; with  */
+/*   none the gallery still lists the tracks      */
+/*                                                */
+/**************************************************/
+
+var errNoAudio = errors.New("no audio device")
+
+type TrackPlayer struct {
+	music rl.Music
+	path  string /*   empty when stopped */
+}
+
+func (p *TrackPlayer) Play(path string) error {
+	p.Stop()
+	if !rl.IsAudioDeviceReady() {
+		return errNoAudio
+	}
+	music := rl.LoadMusicStream(path)
+	if !rl.IsMusicValid(music) {
+		return fmt.Errorf("cannot play %s", filepath.Base(path))
+	}
+	music.Looping = false
+	rl.PlayMusicStream(music)
+	p.music, p.path = music, path
+	return nil
+}
+
+/*   Feeds the stream; call every frame         */
+func (p *TrackPlayer) Update() {
+	if p.path == "" {
+		return
+	}
+	rl.UpdateMusicStream(p.music)
+	if !rl.IsMusicStreamPlaying(p.music) {
+		p.Stop()
+	}
+}
+
+func (p *TrackPlayer) Stop() {
+	if p.path == "" {
+		return
+	}
+	rl.StopMusicStream(p.music)
+	rl.UnloadMusicStream(p.music)
+	p.path = ""
+}
+
+func (p *TrackPlayer) Playing() string {
+	return p.path
+}
+
+func (p *TrackPlayer) Progress() (played, length float32) {
+	if p.path == "" {
+		return 0, 0
+	}
+	return rl.GetMusicTimePlayed(p.music), rl.GetMusicTimeLength(p.music)
+}
+
+/**************************************************/
+/*                                                */
+/*                 MEDIA SCREEN                   */
+/*   Selected is -1 while the blade row has the   */
+/*   focus; TAB changes the filter at any time    */
+/*                                                */
+/**************************************************/
+
+type mediaFilter struct {
+	label string
+	kinds []library.MediaKind
+}
+
+var mediaFilters = []mediaFilter{
+	{"ALL", nil},
+	{"SCREENSHOTS", []library.MediaKind{library.MediaCover, library.MediaScreenshot}},
+	{"CLIPS", []library.MediaKind{library.MediaClip}},
+	{"SOUNDTRACK", []library.MediaKind{library.MediaTrack}},
+}
+
+const (
+	mediaColumns    = 6
+	mediaTileWidth  = 180
+	mediaTileHeight = 130
+	mediaImageH     = 100
+	mediaSpacing    = 16
+	mediaRowPitch   = mediaTileHeight + mediaSpacing
+	mediaViewTop    = 200
+	mediaViewBottom = SCREEN_HEIGHT - 104 /*   room for the now-playing bar */
+	mediaLeft       = (SCREEN_WIDTH - mediaColumns*mediaTileWidth - (mediaColumns-1)*mediaSpacing) / 2
+)
+
+type MediaScreen struct {
+	feed   FeedControl
+	thumbs *ThumbLoader
+	player TrackPlayer
+
+	all      []library.MediaItem
+	items    []library.MediaItem /*   after the filter */
+	filter   int
+	Selected int
+	scroll   float32
+	viewing  bool
+
+	loadedFor int /*   library version the list was asked for */
+	version   int /*   media version shown                    */
+
+	message   string
+	messageAt time.Time
+}
+
+func NewMediaScreen(feed FeedControl) *MediaScreen {
+	return &MediaScreen{feed: feed, thumbs: NewThumbLoader(), Selected: -1, loadedFor: -1}
+}
+
+/*   Asks for the list again once the library   */
+/*   changed                                    */
+func (m *MediaScreen) Update(dt float32, status FeedStatus, visible bool) {
+	if status.MediaVersion != m.version {
+		m.all, m.version = status.Media, status.MediaVersion
+		m.applyFilter()
+	}
+	if visible && status.State == ConnOnline && status.Version != m.loadedFor && !status.MediaLoading {
+		m.loadedFor = status.Version
+		m.feed.LoadMedia()
+	}
+
+	target := float32(0)
+	if m.Selected >= 0 {
+		row := float32(m.Selected / mediaColumns)
+		target = max(0, row*mediaRowPitch-(mediaViewBottom-mediaViewTop-mediaRowPitch))
+	}
+	m.scroll = lerp(m.scroll, target, dt*10)
+}
+
+/*   Render side, every frame: keeps music      */
+/*   streaming and textures uploading           */
+func (m *MediaScreen) Pump() {
+	m.player.Update()
+	m.thumbs.Update()
+}
+
+func (m *MediaScreen) applyFilter() {
+	var selectedPath string
+	if m.Selected >= 0 && m.Selected < len(m.items) {
+		selectedPath = m.items[m.Selected].Path
+	}
+
+	kinds := mediaFilters[m.filter].kinds
+	m.items = m.items[:0:0]
+	for _, item := range m.all {
+		if kinds == nil || indexOf(kinds, item.Kind) >= 0 {
+			m.items = append(m.items, item)
+		}
+	}
+
+	if m.Selected >= 0 {
+		m.Selected = max(0, min(m.Selected, len(m.items)-1))
+		for i, item := range m.items {
+			if item.Path == selectedPath {
+				m.Selected = i
+			}
+		}
+		if len(m.items) == 0 {
+			m.Selected = -1
+		}
+	}
+	if m.viewing && !m.showingImage() {
+		m.closeViewer()
+	}
+}
+
+func (m *MediaScreen) NextFilter() {
+	m.filter = (m.filter + 1) % len(mediaFilters)
+	m.applyFilter()
+}
+
+func (m *MediaScreen) Focused() bool {
+	return m.Selected >= 0
+}
+
+func (m *MediaScreen) Viewing() bool {
+	return m.viewing
+}
+
+/*   False at the row edge or while the blade   */
+/*   row has the focus                          */
+func (m *MediaScreen) Move(dir int) bool {
+	if !m.Focused() {
+		return false
+	}
+	column := m.Selected % mediaColumns
+	next := m.Selected + dir
+	if next < 0 || next >= len(m.items) || (dir < 0 && column == 0) || (dir > 0 && column == mediaColumns-1) {
+		return false
+	}
+	m.Selected = next
+	if m.viewing && !m.showingImage() {
+		m.closeViewer()
+	}
+	return true
+}
+
+func (m *MediaScreen) MoveDown() {
+	switch {
+	case len(m.items) == 0:
+	case !m.Focused():
+		m.Selected = 0
+	case m.Selected+mediaColumns < len(m.items):
+		m.Selected += mediaColumns
+	case m.Selected/mediaColumns < (len(m.items)-1)/mediaColumns:
+		m.Selected = len(m.items) - 1
+	}
+}
+
+func (m *MediaScreen) MoveUp() {
+	if m.Selected >= mediaColumns {
+		m.Selected -= mediaColumns
+	} else {
+		m.Selected = -1
+	}
+}
+
+/*   Images open full size, tracks play or      */
+/*   stop; clips are only listed                */
+func (m *MediaScreen) Activate() {
+	if !m.Focused() {
+		return
+	}
+	item := m.items[m.Selected]
+	switch item.Kind {
+	case library.MediaCover, library.MediaScreenshot:
+		m.viewing = true
+	case library.MediaTrack:
+		if m.player.Playing() == item.Path {
+			m.player.Stop()
+			return
+		}
+		if err := m.player.Play(item.Path); err != nil {
+			m.notify(strings.ToUpper(err.Error()) + " - " + item.Path)
+		}
+	case library.MediaClip:
+		m.notify("CLIPS PLAY IN AN EXTERNAL PLAYER - " + item.Path)
+	}
+}
+
+func (m *MediaScreen) HandleViewerInput(actions *ActionMap) {
+	if actions.KeyPressed(rl.KeyEscape) || actions.Pressed(ActBack) || actions.Pressed(ActSelect) {
+		m.closeViewer()
+	}
+	if actions.Repeated(ActNavRight) {
+		m.stepImage(1)
+	}
+	if actions.Repeated(ActNavLeft) {
+		m.stepImage(-1)
+	}
+}
+
+/*   Next or previous image, skipping tracks    */
+/*   and clips                                  */
+func (m *MediaScreen) stepImage(dir int) {
+	for i := m.Selected + dir; i >= 0 && i < len(m.items); i += dir {
+		if kind := m.items[i].Kind; kind == library.MediaCover || kind == library.MediaScreenshot {
+			m.Selected = i
+			return
+		}
+	}
+}
+
+func (m *MediaScreen) showingImage() bool {
+	if !m.Focused() || m.Selected >= len(m.items) {
+		return false
+	}
+	kind := m.items[m.Selected].Kind
+	return kind == library.MediaCover || kind == library.MediaScreenshot
+}
+
+func (m *MediaScreen) closeViewer() {
+	m.viewing = false
+}
+
+/*   Music stops when a game launches           */
+func (m *MediaScreen) StopTrack() {
+	m.player.Stop()
+}
+
+func (m *MediaScreen) Close() {
+	m.player.Stop()
+	m.thumbs.Close()
+}
+
+func (m *MediaScreen) notify(message string) {
+	m.message, m.messageAt = message, time.Now()
+}
+
+/**************************************************/
+/*                                                */
+/*                    DRAW                        */
+/*                                                */
+/**************************************************/
+
+/*   clock is the frontend's, in seconds, for   */
+/*   the pulses and bouncing bars               */
+func (m *MediaScreen) Draw(status FeedStatus, grid *GameGrid, clock float32) {
+	m.drawFilters()
+
+	switch {
+	case len(m.items) > 0:
+		m.drawGallery(grid, clock)
+	case status.MediaLoading || (status.State == ConnOnline && m.loadedFor != status.Version):
+		m.drawEmpty("LOADING MEDIA"+"..."[:int(clock*3)%4], "looking beside every ROM")
+	case len(m.all) == 0 && status.State != ConnOnline:
+		m.drawEmpty("BACKEND OFFLINE", "media is listed by the backend")
+	case status.MediaError != "":
+		m.drawEmpty("CANNOT LIST MEDIA", status.MediaError)
+	case len(m.all) == 0:
+		m.drawEmpty("NO MEDIA YET", "put files in screenshots/, videos/ or music/ beside the ROMs, named after the ROM")
+	default:
+		m.drawEmpty("NOTHING HERE", "[TAB] shows another kind")
+	}
+
+	m.drawNowPlaying(grid)
+
+	hint := "v  BROWSE    [TAB] FILTER"
+	switch {
+	case m.message != "" && time.Since(m.messageAt) < settingsMessageSeconds*time.Second:
+		hint = m.message
+	case m.Focused():
+		hint = "<< >> ^v  BROWSE    [ENTER] VIEW / PLAY    [TAB] FILTER"
+	}
+	hint = ellipsize(hint, toastMaxLength)
+	hintWidth := measureText(hint, 12)
+	drawText(hint, (SCREEN_WIDTH-hintWidth)/2, gridViewBottom+6, 12, TextGray)
+
+	if m.viewing && m.showingImage() {
+		m.drawViewer(grid, clock)
+	}
+}
+
+func (m *MediaScreen) drawFilters() {
+	x := int32(mediaLeft)
+	for i, filter := range mediaFilters {
+		count := 0
+		for _, item := range m.all {
+			if filter.kinds == nil || indexOf(filter.kinds, item.Kind) >= 0 {
+				count++
+			}
+		}
+		label := fmt.Sprintf("%s  %d", filter.label, count)
+		width := measureText(label, 12) + 28
+		color := TextGray
+		if i == m.filter {
+			rl.DrawRectangle(x, 160, width, 24, AeroSelected)
+			rl.DrawRectangleGradientV(x, 160, width, 12, AeroGloss, rl.Color{A: 0})
+			rl.DrawRectangleLines(x, 160, width, 24, NeonGreen)
+			color = TextWhite
+		}
+		drawText(label, x+14, 166, 12, color)
+		x += width + 10
+	}
+}
+
+func (m *MediaScreen) drawGallery(grid *GameGrid, clock float32) {
+	beginClip(0, mediaViewTop-8, SCREEN_WIDTH, mediaViewBottom-mediaViewTop+14)
+	defer rl.EndScissorMode()
+
+	first := max(0, int(m.scroll/mediaRowPitch)*mediaColumns)
+	for i := first; i < len(m.items); i++ {
+		row, column := i/mediaColumns, i%mediaColumns
+		y := int32(float32(mediaViewTop+row*mediaRowPitch) - m.scroll)
+		if y > mediaViewBottom {
+			break
+		}
+		x := int32(mediaLeft + column*(mediaTileWidth+mediaSpacing))
+		m.drawTile(m.items[i], x, y, i == m.Selected, grid, clock)
+	}
+}
+
+func (m *MediaScreen) drawTile(item library.MediaItem, x, y int32, selected bool, grid *GameGrid, clock float32) {
+	accent := NeonGreen
+	if game, ok := grid.Find(item.GameID); ok {
+		accent = game.Color
+	}
+
+	rl.DrawRectangle(x+3, y+3, mediaTileWidth, mediaTileHeight, AeroShadow)
+	rl.DrawRectangle(x, y, mediaTileWidth, mediaTileHeight, AeroDarkPanel)
+	rl.DrawRectangle(x, y, mediaTileWidth, mediaImageH, AeroHeader)
+
+	switch item.Kind {
+	case library.MediaCover, library.MediaScreenshot:
+		drawThumb(m.thumbs.Get(item.Path), x, y, mediaTileWidth, mediaImageH, clock)
+	case library.MediaClip:
+		drawClipIcon(x, y, accent, item.Path)
+	case library.MediaTrack:
+		drawTrackIcon(x, y, accent, m.player.Playing() == item.Path, clock)
+	}
+
+	rl.DrawRectangleGradientV(x, y, mediaTileWidth, mediaImageH/2, AeroGloss, rl.Color{A: 0})
+	rl.DrawRectangle(x, y+mediaImageH, mediaTileWidth, 2, accent)
+
+	title := mediaGameTitle(item, grid)
+	drawText(ellipsize(title, 22), x+8, y+mediaImageH+6, 10, TextWhite)
+	drawText(ellipsize(strings.ToUpper(string(item.Kind))+"  "+filepath.Base(item.Path), 30), x+8, y+mediaImageH+18, 8, TextGray)
+
+	if selected {
+		pulse := uint8(180 + 75*math.Abs(math.Sin(float64(clock)*3)))
+		rl.DrawRectangleLines(x-2, y-2, mediaTileWidth+4, mediaTileHeight+4, rl.Color{R: NeonGreen.R, G: NeonGreen.G, B: NeonGreen.B, A: pulse})
+		rl.DrawRectangleLines(x-1, y-1, mediaTileWidth+2, mediaTileHeight+2, NeonGreen)
+	} else {
+		rl.DrawRectangleLines(x, y, mediaTileWidth, mediaTileHeight, rl.Color{R: accent.R, G: accent.G, B: accent.B, A: 70})
+	}
+}
+
+/*   Fitted inside the box, keeping its shape   */
+func drawThumb(th *thumb, x, y, width, height int32, clock float32) {
+	switch th.state {
+	case thumbPending:
+		alpha := uint8(90 + 80*math.Abs(math.Sin(float64(clock)*4)))
+		label := "LOADING"
+		drawText(label, x+(width-measureText(label, 10))/2, y+height/2-5, 10, rl.Color{R: TextGray.R, G: TextGray.G, B: TextGray.B, A: alpha})
+	case thumbFailed:
+		label := "CANNOT DECODE"
+		drawText(label, x+(width-measureText(label, 10))/2, y+height/2-10, 10, AccentPink)
+		detail := ellipsize(th.err, 30)
+		drawText(detail, x+(width-measureText(detail, 8))/2, y+height/2+4, 8, TextGray)
+	case thumbReady:
+		texture := th.texture
+		scale := min(float32(width)/float32(texture.Width), float32(height)/float32(texture.Height))
+		w, h := float32(texture.Width)*scale, float32(texture.Height)*scale
+		dest := rl.Rectangle{X: float32(x) + (float32(width)-w)/2, Y: float32(y) + (float32(height)-h)/2, Width: w, Height: h}
+		source := rl.Rectangle{Width: float32(texture.Width), Height: float32(texture.Height)}
+		rl.DrawTexturePro(texture, source, dest, rl.Vector2{}, 0, rl.White)
+	}
+}
+
+func drawClipIcon(x, y int32, accent rl.Color, path string) {
+	cx, cy := x+mediaTileWidth/2, y+mediaImageH/2
+	rl.DrawRectangle(cx-40, cy-26, 80, 52, AeroIconWell)
+	for i := int32(0); i < 5; i++ {
+		rl.DrawRectangle(cx-34+i*16, cy-23, 8, 5, TextGray)
+		rl.DrawRectangle(cx-34+i*16, cy+18, 8, 5, TextGray)
+	}
+	rl.DrawTriangle(
+		rl.Vector2{X: float32(cx - 8), Y: float32(cy - 11)},
+		rl.Vector2{X: float32(cx - 8), Y: float32(cy + 11)},
+		rl.Vector2{X: float32(cx + 12), Y: float32(cy)},
+		accent)
+	ext := strings.ToUpper(strings.TrimPrefix(filepath.Ext(path), "."))
+	drawText(ext, x+mediaTileWidth-8-measureText(ext, 8), y+6, 8, TextGray)
+}
+
+/*   Bars bounce while the track plays          */
+func drawTrackIcon(x, y int32, accent rl.Color, playing bool, clock float32) {
+	cx, cy := x+mediaTileWidth/2, y+mediaImageH/2
+	if playing {
+		for i := int32(0); i < 5; i++ {
+			h := int32(10 + 26*math.Abs(math.Sin(float64(clock)*5+float64(i))))
+			rl.DrawRectangle(cx-30+i*13, cy+18-h, 8, h, accent)
+		}
+		return
+	}
+	rl.DrawCircle(cx-10, cy+14, 9, accent)
+	rl.DrawRectangle(cx-2, cy-22, 4, 36, accent)
+	rl.DrawRectangle(cx-2, cy-22, 18, 5, accent)
+	if !rl.IsAudioDeviceReady() {
+		label := "NO AUDIO DEVICE"
+		drawText(label, x+mediaTileWidth-8-measureText(label, 8), y+6, 8, AccentOrange)
+	}
+}
+
+func (m *MediaScreen) drawNowPlaying(grid *GameGrid) {
+	path := m.player.Playing()
+	if path == "" {
+		return
+	}
+	played, length := m.player.Progress()
+	clock := func(seconds float32) string {
+		return fmt.Sprintf("%02d:%02d", int(seconds)/60, int(seconds)%60)
+	}
+	text := fmt.Sprintf("NOW PLAYING  %s  %s / %s", filepath.Base(path), clock(played), clock(length))
+
+	width := measureText(text, 12) + 40
+	x, y := (SCREEN_WIDTH-width)/2, int32(mediaViewBottom+10)
+	rl.DrawRectangle(x, y, width, 22, AeroDarkPanel)
+	if length > 0 {
+		rl.DrawRectangle(x, y+20, int32(float32(width)*played/length), 2, AccentCyan)
+	}
+	rl.DrawRectangleLines(x, y, width, 22, rl.Color{R: AccentCyan.R, G: AccentCyan.G, B: AccentCyan.B, A: 120})
+	drawText(text, x+20, y+5, 12, TextWhite)
+}
+
+func (m *MediaScreen) drawViewer(grid *GameGrid, clock float32) {
+	item := m.items[m.Selected]
+	rl.DrawRectangle(0, 0, SCREEN_WIDTH, SCREEN_HEIGHT, rl.Color{R: 0, G: 0, B: 0, A: 220})
+	drawThumb(m.thumbs.Get(item.Path), 80, 70, SCREEN_WIDTH-160, SCREEN_HEIGHT-170, clock)
+
+	caption := mediaGameTitle(item, grid) + "  |  " + filepath.Base(item.Path)
+	captionWidth := measureText(caption, 14)
+	drawText(caption, (SCREEN_WIDTH-captionWidth)/2, SCREEN_HEIGHT-88, 14, TextWhite)
+	hint := "<< >>  PREVIOUS / NEXT    [ESC] CLOSE"
+	hintWidth := measureText(hint, 12)
+	drawText(hint, (SCREEN_WIDTH-hintWidth)/2, SCREEN_HEIGHT-60, 12, TextGray)
+}
+
+func (m *MediaScreen) drawEmpty(title, detail string) {
+	titleWidth := measureText(title, 26)
+	drawText(title, (SCREEN_WIDTH-titleWidth)/2, 300, 26, TextGray)
+	detailWidth := measureText(detail, 12)
+	drawText(detail, (SCREEN_WIDTH-detailWidth)/2, 340, 12, TextGray)
+}
+
+func mediaGameTitle(item library.MediaItem, grid *GameGrid) string {
+	if game, ok := grid.Find(item.GameID); ok {
+		return game.Title
+	}
+	return item.GameID
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"retro-gaming-ui/backend/logging"
+	"retro-gaming-ui/backend/server"
+)
+
+/**************************************/
+/*                                    */
+/*      Network Blade & Backend Info  */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*                NETWORK SCREEN                  */
+/*   Selected is -1 while the blade row has the   */
+/*   focus; otherwise left/right pick an action   */
+/*                                                */
+/**************************************************/
+
+type networkAction struct {
+	label   string
+	enabled func(s FeedStatus) bool
+	run     func()
+}
+
+type NetworkScreen struct {
+	feed     FeedControl
+	actions  []networkAction
+	Selected int
+}
+
+const (
+	networkLogRows  = 18
+	networkLogRunes = 70
+)
+
+func NewNetworkScreen(feed FeedControl) *NetworkScreen {
+	online := func(s FeedStatus) bool { return s.State == ConnOnline }
+	return &NetworkScreen{
+		feed:     feed,
+		Selected: -1,
+		actions: []networkAction{
+			{"RECONNECT", func(s FeedStatus) bool { return true }, feed.Reconnect},
+			{"SCAN LIBRARY", func(s FeedStatus) bool { return online(s) && !s.Scanning }, feed.Scan},
+			{"CANCEL SCAN", func(s FeedStatus) bool { return online(s) && s.Scanning }, feed.CancelScan},
+		},
+	}
+}
+
+func (n *NetworkScreen) Focused() bool {
+	return n.Selected >= 0
+}
+
+func (n *NetworkScreen) MoveDown() {
+	if !n.Focused() {
+		n.Selected = 0
+	}
+}
+
+func (n *NetworkScreen) MoveUp() {
+	n.Selected = -1
+}
+
+/*   False while the blade row has the focus,   */
+/*   so the key can switch blades instead       */
+func (n *NetworkScreen) Move(dir int) bool {
+	if !n.Focused() {
+		return false
+	}
+	n.Selected = max(0, min(n.Selected+dir, len(n.actions)-1))
+	return true
+}
+
+func (n *NetworkScreen) Activate(status FeedStatus) {
+	if !n.Focused() {
+		return
+	}
+	if action := n.actions[n.Selected]; action.enabled(status) {
+		action.run()
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                    DRAW                        */
+/*                                                */
+/**************************************************/
+
+func (n *NetworkScreen) Draw(status FeedStatus) {
+	left, right := int32(90), int32(650)
+	panelWidth := int32(540)
+
+	y := drawPanelTitle("CONNECTION", left, 160, panelWidth)
+	state, stateColor := "ONLINE", NeonGreen
+	switch status.State {
+	case ConnConnecting:
+		state, stateColor = "CONNECTING...", AccentOrange
+	case ConnOffline:
+		state, stateColor = "OFFLINE", AccentPink
+		if wait := time.Until(status.RetryAt); wait > 0 {
+			state += fmt.Sprintf(" - retrying in %ds", int(wait.Seconds())+1)
+		}
+	}
+	y = drawInfoRow("State", state, left, y, panelWidth, stateColor)
+	y = drawInfoRow("Address", status.Addr, left, y, panelWidth, TextWhite)
+
+	online := status.State == ConnOnline
+	latency, protocol, clients := "-", "-", "-"
+	if online {
+		latency = fmt.Sprintf("%d ms", status.Latency.Milliseconds())
+		protocol = status.Protocol
+		if protocol != server.ProtocolVersion {
+			protocol += "  (frontend " + server.ProtocolVersion + ")"
+		}
+		clients = fmt.Sprint(status.Clients)
+	}
+	y = drawInfoRow("Latency", latency, left, y, panelWidth, TextWhite)
+	y = drawInfoRow("Protocol", protocol, left, y, panelWidth, TextWhite)
+	y = drawInfoRow("Connected clients", clients, left, y, panelWidth, TextWhite)
+	if !online && status.Error != "" {
+		y = drawInfoRow("Last error", ellipsize(status.Error, 48), left, y, panelWidth, AccentPink)
+	}
+
+	y = drawPanelTitle("LIBRARY SCAN", left, y+14, panelWidth)
+	y = drawInfoRow("Last scan", describeLastScan(status), left, y, panelWidth, TextWhite)
+	if status.Scanning {
+		p := status.Progress
+		y = drawInfoRow("Scanning", fmt.Sprintf("%d / %d files, %d games", p.FilesScanned, p.FilesTotal, p.GamesFound),
+			left, y, panelWidth, AccentCyan)
+		drawScanBar(left, y, panelWidth, p.FilesScanned, p.FilesTotal)
+		y += 12
+	}
+
+	n.drawActions(status, left, y+16)
+	n.drawLog(status, right, 160)
+
+	hint := "v  BACKEND ACTIONS"
+	switch {
+	case status.ScanResult != "" && time.Since(status.ScanResultAt) < settingsMessageSeconds*time.Second:
+		hint = status.ScanResult
+	case n.Focused():
+		hint = "<< >>  CHOOSE    [ENTER] RUN    ^  BACK TO BLADES"
+	}
+	hintWidth := measureText(hint, 12)
+	drawText(hint, (SCREEN_WIDTH-hintWidth)/2, gridViewBottom+6, 12, TextGray)
+}
+
+func (n *NetworkScreen) drawActions(status FeedStatus, x, y int32) {
+	for i, action := range n.actions {
+		width := measureText(action.label, 12) + 32
+		selected := i == n.Selected
+		color := TextGray
+		if !action.enabled(status) {
+			color.A = 90
+		} else if selected {
+			color = TextWhite
+		}
+
+		rl.DrawRectangle(x, y, width, 26, AeroDarkPanel)
+		if selected {
+			rl.DrawRectangle(x, y, width, 26, AeroSelected)
+			rl.DrawRectangleGradientV(x, y, width, 13, AeroGloss, rl.Color{A: 0})
+			rl.DrawRectangleLines(x, y, width, 26, NeonGreen)
+		} else {
+			rl.DrawRectangleLines(x, y, width, 26, rl.Color{R: NeonGreen.R, G: NeonGreen.G, B: NeonGreen.B, A: 50})
+		}
+		drawText(action.label, x+16, y+7, 12, color)
+		x += width + 12
+	}
+}
+
+/*   Newest record at the bottom                */
+func (n *NetworkScreen) drawLog(status FeedStatus, x, y int32) {
+	width := int32(SCREEN_WIDTH - 90 - x)
+	y = drawPanelTitle("BACKEND LOG", x, y, width)
+
+	height := int32(networkLogRows*18 + 12)
+	rl.DrawRectangle(x, y, width, height, AeroDarkPanel)
+	rl.DrawRectangleLines(x, y, width, height, rl.Color{R: NeonGreen.R, G: NeonGreen.G, B: NeonGreen.B, A: 50})
+
+	logs := status.Logs
+	if len(logs) == 0 {
+		drawText("no log records", x+12, y+10, 12, TextGray)
+		return
+	}
+	if len(logs) > networkLogRows {
+		logs = logs[len(logs)-networkLogRows:]
+	}
+	for i, record := range logs {
+		line := record.Time.Local().Format("15:04:05") + "  " + record.Message
+		drawText(ellipsize(line, networkLogRunes), x+12, y+8+int32(i)*18, 10, logColor(record))
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                  HELPERS                       */
+/*                                                */
+/**************************************************/
+
+/*   Returns the y below the title              */
+func drawPanelTitle(title string, x, y, width int32) int32 {
+	titleWidth := measureText(title, 14)
+	drawText(title, x, y+6, 14, NeonGreen)
+	rl.DrawRectangle(x+titleWidth+12, y+13, width-titleWidth-12, 1,
+		rl.Color{R: NeonGreen.R, G: NeonGreen.G, B: NeonGreen.B, A: 50})
+	return y + 28
+}
+
+func drawInfoRow(label, value string, x, y, width int32, valueColor rl.Color) int32 {
+	drawText(label, x+16, y+4, 12, TextGray)
+	valueWidth := measureText(value, 12)
+	drawText(value, x+width-16-valueWidth, y+4, 12, valueColor)
+	return y + 22
+}
+
+func drawScanBar(x, y, width int32, done, total int) {
+	rl.DrawRectangle(x+16, y, width-32, 6, AeroDarkPanel)
+	if total > 0 {
+		filled := int32(float32(width-32) * float32(min(done, total)) / float32(total))
+		rl.DrawRectangle(x+16, y, filled, 6, AccentCyan)
+	}
+}
+
+func describeLastScan(status FeedStatus) string {
+	scan := status.LastScan
+	if scan == nil {
+		return "none since the backend started"
+	}
+	when := scan.Finished.Local().Format("02.01.2006 15:04")
+	took := scan.Finished.Sub(scan.Started).Round(100 * time.Millisecond)
+	switch {
+	case scan.Canceled:
+		return fmt.Sprintf("%s - canceled after %s", when, took)
+	case scan.Error != "":
+		return ellipsize(fmt.Sprintf("%s - failed: %s", when, scan.Error), 48)
+	}
+	return fmt.Sprintf("%s - %d games in %s", when, scan.Games, took)
+}
+
+func logColor(record logging.Record) rl.Color {
+	switch strings.ToUpper(record.Level) {
+	case "ERROR":
+		return AccentPink
+	case "WARN":
+		return AccentOrange
+	case "DEBUG":
+		return NeonGreenDark
+	}
+	return TextGray
+}
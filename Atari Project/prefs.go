@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+/**************************************/
+/*                                    */
+/*      Frontend User Preferences     */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*               PREFERENCE TYPES                 */
+/*   Saved beside the backend config; missing     */
+/*   fields keep their defaults                   */
+/*                                                */
+/**************************************************/
+
+const prefsFile = "frontend_prefs.json"
+
+type DisplayPrefs struct {
+	Fullscreen bool      `json:"fullscreen"`
+	Width      int32     `json:"width"` /*   window, or video mode when fullscreen   */
+	Height     int32     `json:"height"`
+	VSync      bool      `json:"vsync"`
+	FPSCap     int32     `json:"fps_cap"` /*   0 = unlimited   */
+	Scaling    ScaleMode `json:"scaling"` /*   see layout.go   */
+}
+
+type EffectsPrefs struct {
+	Bubbles  bool `json:"bubbles"`
+	Droplets bool `json:"droplets"`
+	Scanline bool `json:"scanline"`
+	Density  int  `json:"density"` /*   percent of the default count   */
+}
+
+type AudioPrefs struct {
+	Volume int  `json:"volume"` /*   0-100   */
+	Muted  bool `json:"muted"`
+}
+
+type InputPrefs struct {
+	EdgeSwitchesBlade bool   `json:"edge_switches_blade"`  /*   left/right past the grid edge   */
+	LaunchKey         string `json:"launch_key,omitempty"` /*   old setting; moved to bindings  */
+}
+
+type BackendPrefs struct {
+	Address string `json:"address,omitempty"` /*   empty = ipc.listen from the config   */
+}
+
+type Prefs struct {
+	Theme   string       `json:"theme"` /*   by name; see theme.go   */
+	Display DisplayPrefs `json:"display"`
+	Effects EffectsPrefs `json:"effects"`
+	Audio   AudioPrefs   `json:"audio"`
+	Input   InputPrefs   `json:"input"`
+	Backend BackendPrefs `json:"backend"`
+
+	path string
+}
+
+/*   Choices the settings blade cycles through  */
+var (
+	resolutions = [][2]int32{
+		{640, 480}, {800, 600}, {1024, 768}, /*   4:3 CRTs   */
+		{1280, 720}, {1600, 900}, {1920, 1080}, {2560, 1440}, {3840, 2160},
+	}
+	fpsCaps = []int32{30, 60, 120, 144, 0}
+)
+
+const (
+	densityMin  = 25
+	densityMax  = 200
+	densityStep = 25
+)
+
+func DefaultPrefs(path string) *Prefs {
+	return &Prefs{
+		Theme:   defaultThemeName,
+		Display: DisplayPrefs{Width: SCREEN_WIDTH, Height: SCREEN_HEIGHT, VSync: true, FPSCap: 60, Scaling: ScaleFit},
+		Effects: EffectsPrefs{Bubbles: true, Droplets: true, Scanline: true, Density: 100},
+		Audio:   AudioPrefs{Volume: 80},
+		Input:   InputPrefs{EdgeSwitchesBlade: true},
+		path:    path,
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                 LOAD / SAVE                    */
+/*                                                */
+/**************************************************/
+
+/*   A missing file gives the defaults; a bad   */
+/*   one gives the defaults and the error       */
+func LoadPrefs(dir string) (*Prefs, error) {
+	prefs := DefaultPrefs(filepath.Join(dir, prefsFile))
+	data, err := os.ReadFile(prefs.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return prefs, nil
+	}
+	if err != nil {
+		return prefs, err
+	}
+	if err := json.Unmarshal(data, prefs); err != nil {
+		return DefaultPrefs(prefs.path), fmt.Errorf("invalid preferences %s: %w", prefs.path, err)
+	}
+	prefs.clamp()
+	return prefs, nil
+}
+
+func (p *Prefs) Save() error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	os.MkdirAll(filepath.Dir(p.path), 0755)
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.path)
+}
+
+func (p *Prefs) Path() string {
+	return p.path
+}
+
+/*   Hand-edited values are pulled back into    */
+/*   range rather than rejected                 */
+func (p *Prefs) clamp() {
+	if p.Theme == "" {
+		p.Theme = defaultThemeName
+	}
+	if p.Display.Width < 640 || p.Display.Height < 360 {
+		p.Display.Width, p.Display.Height = SCREEN_WIDTH, SCREEN_HEIGHT
+	}
+	if p.Display.FPSCap < 0 {
+		p.Display.FPSCap = 60
+	}
+	if indexOf(scaleModes, p.Display.Scaling) < 0 {
+		p.Display.Scaling = ScaleFit
+	}
+	p.Effects.Density = max(densityMin, min(p.Effects.Density, densityMax))
+	p.Audio.Volume = max(0, min(p.Audio.Volume, 100))
+}
+
+/**************************************************/
+/*                                                */
+/*                  HELPERS                       */
+/*                                                */
+/**************************************************/
+
+func indexOf[T comparable](values []T, v T) int {
+	for i, value := range values {
+		if value == v {
+			return i
+		}
+	}
+	return -1
+}
+
+/*   Next or previous entry, wrapping; an       */
+/*   unknown value starts from the first        */
+func cycle[T comparable](values []T, current T, dir int) T {
+	i := indexOf(values, current)
+	if i < 0 {
+		return values[0]
+	}
+	return values[(i+dir+len(values))%len(values)]
+}
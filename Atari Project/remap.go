@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+/**************************************/
+/*                                    */
+/*      Control Remapping Overlay     */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*                REMAP SCREEN                    */
+/*   One row per action, then RESET and DONE.     */
+/*   Rebinding waits for the next input that was  */
+/*   not already held                             */
+/*                                                */
+/**************************************************/
+
+const remapSeconds = 6
+
+const (
+	remapRowReset = int(actionCount)
+	remapRowDone  = remapRowReset + 1
+)
+
+type RemapScreen struct {
+	actions  *ActionMap
+	open     bool
+	Selected int
+
+	capturing   bool
+	captureLeft float32 /*   seconds   */
+	held        []Input /*   down when the capture began       */
+	settling    *Input  /*   just bound; ignored until let go  */
+
+	message   string
+	messageAt time.Time
+}
+
+func NewRemapScreen(actions *ActionMap) *RemapScreen {
+	return &RemapScreen{actions: actions}
+}
+
+func (r *RemapScreen) Open() {
+	r.open, r.Selected, r.message = true, 0, ""
+}
+
+func (r *RemapScreen) IsOpen() bool {
+	return r.open
+}
+
+func (r *RemapScreen) close() {
+	r.open, r.capturing = false, false
+}
+
+func (r *RemapScreen) Update(dt float32) {
+	if r.settling != nil {
+		if indexOf(r.actions.ActiveInputs(), *r.settling) >= 0 {
+			return
+		}
+		r.settling = nil
+	}
+	if r.capturing {
+		r.capture(dt)
+		return
+	}
+
+	a := r.actions
+	switch {
+	case a.KeyPressed(rl.KeyEscape) || a.Pressed(ActBack):
+		r.close()
+	case a.Repeated(ActNavUp):
+		r.Selected = max(0, r.Selected-1)
+	case a.Repeated(ActNavDown):
+		r.Selected = min(remapRowDone, r.Selected+1)
+	case a.Pressed(ActSelect):
+		switch r.Selected {
+		case remapRowDone:
+			r.close()
+		case remapRowReset:
+			defaults := DefaultBindings(a.Bindings().Path())
+			a.SetBindings(defaults)
+			r.save("defaults restored")
+		default:
+			r.capturing, r.captureLeft, r.held = true, remapSeconds, a.ActiveInputs()
+		}
+	}
+}
+
+func (r *RemapScreen) capture(dt float32) {
+	r.captureLeft -= dt
+	if r.actions.KeyPressed(rl.KeyEscape) || r.captureLeft <= 0 {
+		r.capturing = false
+		r.notify("not changed")
+		return
+	}
+
+	active := r.actions.ActiveInputs()
+	stillHeld := r.held[:0]
+	for _, in := range r.held {
+		if indexOf(active, in) >= 0 {
+			stillHeld = append(stillHeld, in)
+		}
+	}
+	r.held = stillHeld
+
+	for _, in := range active {
+		if indexOf(r.held, in) >= 0 {
+			continue
+		}
+		action := Action(r.Selected)
+		from, moved := r.actions.Bindings().Assign(action, in)
+		r.capturing, r.settling = false, &in
+
+		note := in.Label() + " -> " + actionLabels[action]
+		if moved {
+			note += " (taken from " + actionLabels[from] + ")"
+		}
+		r.save(note)
+		return
+	}
+}
+
+func (r *RemapScreen) save(note string) {
+	if err := r.actions.Bindings().Save(); err != nil {
+		r.notify("cannot save bindings: " + err.Error())
+		return
+	}
+	r.notify(note)
+}
+
+func (r *RemapScreen) notify(message string) {
+	r.message, r.messageAt = message, time.Now()
+}
+
+/**************************************************/
+/*                                                */
+/*                    DRAW                        */
+/*                                                */
+/**************************************************/
+
+func (r *RemapScreen) Draw() {
+	rl.DrawRectangle(0, 0, SCREEN_WIDTH, SCREEN_HEIGHT, rl.Color{R: 0, G: 0, B: 0, A: 200})
+
+	width, height := int32(820), int32(590)
+	x, y := (SCREEN_WIDTH-width)/2, (SCREEN_HEIGHT-height)/2
+	rl.DrawRectangle(x+5, y+5, width, height, AeroShadow)
+	rl.DrawRectangleGradientV(x, y, width, height,
+		AeroCardTop, AeroCardBottom)
+	rl.DrawRectangleGradientV(x, y, width, 60, AeroGloss, rl.Color{A: 0})
+	rl.DrawRectangleLines(x, y, width, height, NeonGreen)
+
+	drawText("REMAP CONTROLS", x+24, y+18, 20, NeonGreen)
+	path := r.actions.Bindings().Path()
+	drawText(ellipsize(path, 60), x+width-24-measureText(ellipsize(path, 60), 10), y+24, 10, TextGray)
+
+	rowY := y + 56
+	for row := 0; row <= remapRowDone; row++ {
+		label, value := "", ""
+		switch row {
+		case remapRowReset:
+			label, value = "Reset to defaults", "[SELECT]"
+			rowY += 8
+		case remapRowDone:
+			label, value = "Done", "[SELECT]"
+		default:
+			label = actionLabels[row]
+			names := make([]string, 0)
+			for _, in := range r.actions.Bindings().For(Action(row)) {
+				names = append(names, in.Label())
+			}
+			value = strings.Join(names, "   ")
+			if value == "" {
+				value = "UNBOUND"
+			}
+		}
+
+		selected := row == r.Selected
+		color := TextGray
+		if selected {
+			rl.DrawRectangle(x+16, rowY, width-32, 22, AeroSelected)
+			rl.DrawRectangleLines(x+16, rowY, width-32, 22, NeonGreen)
+			color = TextWhite
+			if r.capturing {
+				value, color = fmt.Sprintf("PRESS A KEY, BUTTON OR STICK...  %.0fs", r.captureLeft), AccentCyan
+			}
+		}
+		drawText(label, x+32, rowY+5, 12, color)
+		value = ellipsize(value, 80)
+		drawText(value, x+width-32-measureText(value, 12), rowY+5, 12, color)
+		rowY += 26
+	}
+
+	hint := "A NEW KEY REPLACES THE ROW'S KEYS, A PAD INPUT ITS PAD INPUTS    [BACK] CLOSE"
+	if r.message != "" && time.Since(r.messageAt) < settingsMessageSeconds*time.Second {
+		hint = r.message
+	}
+	hintWidth := measureText(hint, 12)
+	drawText(hint, x+(width-hintWidth)/2, y+height-28, 12, TextGray)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+/**************************************/
+/*                                    */
+/*       Find-by-Title Type-Ahead     */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*                 TITLE SEARCH                   */
+/*   Each keystroke moves the grid to the first   */
+/*   match; SELECT or BACK closes the box         */
+/*                                                */
+/**************************************************/
+
+type TitleSearch struct {
+	active bool
+	text   []rune
+	found  bool
+}
+
+/*   The key that opened the box was typed on  */
+/*   this frame, so it is not picked up         */
+func (s *TitleSearch) Open() {
+	s.active, s.text, s.found = true, nil, true
+}
+
+func (s *TitleSearch) Active() bool {
+	return s.active
+}
+
+func (s *TitleSearch) close() {
+	s.active = false
+}
+
+/*   Typing goes straight to the box, so only   */
+/*   ENTER, ESC and pad buttons close it        */
+func (s *TitleSearch) HandleInput(actions *ActionMap, grid *GameGrid) {
+	changed := false
+	for _, r := range actions.Typed() {
+		if r >= 32 && r < 127 && len(s.text) < 24 {
+			s.text, changed = append(s.text, r), true
+		}
+	}
+	if actions.KeyPressed(rl.KeyBackspace) && len(s.text) > 0 {
+		s.text, changed = s.text[:len(s.text)-1], true
+	}
+	if changed {
+		s.found = len(s.text) == 0 || grid.FindTitle(string(s.text))
+	}
+
+	padClose := actions.PadPressed(ActSelect) || actions.PadPressed(ActBack) || actions.PadPressed(ActSearch)
+	if actions.KeyPressed(rl.KeyEnter) || actions.KeyPressed(rl.KeyEscape) || padClose {
+		s.close()
+	}
+}
+
+/*   The caret blinks with clock, in seconds    */
+func (s *TitleSearch) Draw(clock float32) {
+	if !s.active {
+		return
+	}
+	text := "FIND: " + string(s.text)
+	if int(clock*2)%2 == 0 {
+		text += "_"
+	}
+	color := TextWhite
+	if !s.found {
+		text, color = text+"   NO MATCH", AccentPink
+	}
+
+	width := max(measureText(text, 16)+40, 320)
+	x, y := (SCREEN_WIDTH-width)/2, int32(108)
+	rl.DrawRectangle(x+3, y+3, width, 32, AeroShadow)
+	rl.DrawRectangle(x, y, width, 32, AeroDarkPanel)
+	rl.DrawRectangleGradientV(x, y, width, 16, AeroGloss, rl.Color{A: 0})
+	rl.DrawRectangleLines(x, y, width, 32, NeonGreen)
+	drawText(text, x+20, y+8, 16, color)
+}
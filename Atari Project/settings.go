@@ -0,0 +1,369 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+/**************************************/
+/*                                    */
+/*     Settings Blade & Live Apply    */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*                SETTING ITEMS                   */
+/*                                                */
+/**************************************************/
+
+type settingKind int
+
+const (
+	settingChoice settingKind = iota /*   left/right or ENTER cycles */
+	settingText                      /*   ENTER edits                */
+	settingAction                    /*   ENTER runs                 */
+)
+
+type settingItem struct {
+	section string
+	label   string
+	kind    settingKind
+	value   func(p *Prefs) string
+	change  func(p *Prefs, dir int)
+	text    func(p *Prefs) *string
+	action  func()
+}
+
+func onOff(v bool) string {
+	if v {
+		return "ON"
+	}
+	return "OFF"
+}
+
+func toggle(field func(p *Prefs) *bool) (func(p *Prefs) string, func(p *Prefs, dir int)) {
+	return func(p *Prefs) string { return onOff(*field(p)) },
+		func(p *Prefs, dir int) { *field(p) = !*field(p) }
+}
+
+/**************************************************/
+/*                                                */
+/*               SETTINGS SCREEN                  */
+/*   Selected is -1 while the blade row has the   */
+/*   focus; left/right then switch blades         */
+/*                                                */
+/**************************************************/
+
+type SettingsScreen struct {
+	prefs    *Prefs
+	items    []settingItem
+	Selected int
+
+	editing bool
+	editBuf []rune
+
+	onChange  func(old Prefs)
+	message   string
+	messageAt time.Time
+}
+
+const settingsMessageSeconds = 4
+
+/*   defaultAddr is shown while the address is  */
+/*   left to the config; themes are the names   */
+/*   the Theme setting cycles through           */
+func NewSettingsScreen(prefs *Prefs, defaultAddr string, themes []string, onChange func(old Prefs), reconnect, remap func()) *SettingsScreen {
+	s := &SettingsScreen{prefs: prefs, Selected: -1, onChange: onChange}
+
+	choice := func(section, label string, value func(p *Prefs) string, change func(p *Prefs, dir int)) settingItem {
+		return settingItem{section: section, label: label, kind: settingChoice, value: value, change: change}
+	}
+	flag := func(section, label string, field func(p *Prefs) *bool) settingItem {
+		value, change := toggle(field)
+		return choice(section, label, value, change)
+	}
+
+	s.items = []settingItem{
+		flag("DISPLAY", "Fullscreen", func(p *Prefs) *bool { return &p.Display.Fullscreen }),
+		choice("DISPLAY", "Resolution",
+			func(p *Prefs) string { return fmt.Sprintf("%d x %d", p.Display.Width, p.Display.Height) },
+			func(p *Prefs, dir int) {
+				next := cycle(resolutions, [2]int32{p.Display.Width, p.Display.Height}, dir)
+				p.Display.Width, p.Display.Height = next[0], next[1]
+			}),
+		flag("DISPLAY", "VSync", func(p *Prefs) *bool { return &p.Display.VSync }),
+		choice("DISPLAY", "FPS cap",
+			func(p *Prefs) string {
+				if p.Display.FPSCap == 0 {
+					return "UNLIMITED"
+				}
+				return fmt.Sprint(p.Display.FPSCap)
+			},
+			func(p *Prefs, dir int) { p.Display.FPSCap = cycle(fpsCaps, p.Display.FPSCap, dir) }),
+		choice("DISPLAY", "Scaling",
+			func(p *Prefs) string { return strings.ToUpper(string(p.Display.Scaling)) },
+			func(p *Prefs, dir int) { p.Display.Scaling = cycle(scaleModes, p.Display.Scaling, dir) }),
+		choice("DISPLAY", "Theme",
+			func(p *Prefs) string { return p.Theme },
+			func(p *Prefs, dir int) { p.Theme = cycle(themes, p.Theme, dir) }),
+
+		flag("EFFECTS", "Bubbles", func(p *Prefs) *bool { return &p.Effects.Bubbles }),
+		flag("EFFECTS", "Water droplets", func(p *Prefs) *bool { return &p.Effects.Droplets }),
+		flag("EFFECTS", "Scanline", func(p *Prefs) *bool { return &p.Effects.Scanline }),
+		choice("EFFECTS", "Density",
+			func(p *Prefs) string { return fmt.Sprintf("%d%%", p.Effects.Density) },
+			func(p *Prefs, dir int) {
+				p.Effects.Density = max(densityMin, min(p.Effects.Density+dir*densityStep, densityMax))
+			}),
+
+		choice("AUDIO", "Volume",
+			func(p *Prefs) string { return fmt.Sprintf("%d%%", p.Audio.Volume) },
+			func(p *Prefs, dir int) { p.Audio.Volume = max(0, min(p.Audio.Volume+dir*10, 100)) }),
+		flag("AUDIO", "Mute", func(p *Prefs) *bool { return &p.Audio.Muted }),
+
+		flag("INPUT", "Grid edge changes blade", func(p *Prefs) *bool { return &p.Input.EdgeSwitchesBlade }),
+		{
+			section: "INPUT", label: "Remap controls", kind: settingAction,
+			value:  func(p *Prefs) string { return "[SELECT]" },
+			action: remap,
+		},
+
+		{
+			section: "BACKEND", label: "Address", kind: settingText,
+			value: func(p *Prefs) string {
+				if p.Backend.Address == "" {
+					return "CONFIG (" + defaultAddr + ")"
+				}
+				return p.Backend.Address
+			},
+			text: func(p *Prefs) *string { return &p.Backend.Address },
+		},
+		{
+			section: "BACKEND", label: "Reconnect now", kind: settingAction,
+			value:  func(p *Prefs) string { return "[SELECT]" },
+			action: reconnect,
+		},
+	}
+	return s
+}
+
+func (s *SettingsScreen) Focused() bool {
+	return s.Selected >= 0
+}
+
+func (s *SettingsScreen) Editing() bool {
+	return s.editing
+}
+
+func (s *SettingsScreen) MoveUp() {
+	s.Selected = max(-1, s.Selected-1)
+}
+
+func (s *SettingsScreen) MoveDown() {
+	s.Selected = min(len(s.items)-1, s.Selected+1)
+}
+
+/*   False while the blade row has the focus,   */
+/*   so the key can switch blades instead       */
+func (s *SettingsScreen) Adjust(dir int) bool {
+	if !s.Focused() {
+		return false
+	}
+	if item := s.items[s.Selected]; item.kind == settingChoice {
+		s.apply(func(p *Prefs) { item.change(p, dir) })
+	}
+	return true
+}
+
+func (s *SettingsScreen) Activate() {
+	if !s.Focused() {
+		return
+	}
+	switch item := s.items[s.Selected]; item.kind {
+	case settingChoice:
+		s.apply(func(p *Prefs) { item.change(p, 1) })
+	case settingText:
+		s.editing = true
+		s.editBuf = []rune(*item.text(s.prefs))
+	case settingAction:
+		item.action()
+		s.notify(item.label + ": done")
+	}
+}
+
+/*   For the fullscreen action, from any blade  */
+func (s *SettingsScreen) ToggleFullscreen() {
+	s.apply(func(p *Prefs) { p.Display.Fullscreen = !p.Display.Fullscreen })
+}
+
+/*   Typing while a text setting is open; ENTER */
+/*   keeps it, ESC drops it                     */
+func (s *SettingsScreen) HandleTextInput(actions *ActionMap) {
+	for _, r := range actions.Typed() {
+		if r >= 32 && r < 127 && len(s.editBuf) < 64 {
+			s.editBuf = append(s.editBuf, r)
+		}
+	}
+	if actions.KeyPressed(rl.KeyBackspace) && len(s.editBuf) > 0 {
+		s.editBuf = s.editBuf[:len(s.editBuf)-1]
+	}
+	switch {
+	case actions.KeyPressed(rl.KeyEnter):
+		item, text := s.items[s.Selected], string(s.editBuf)
+		s.endEdit()
+		s.apply(func(p *Prefs) { *item.text(p) = text })
+	case actions.KeyPressed(rl.KeyEscape):
+		s.endEdit()
+	}
+}
+
+func (s *SettingsScreen) endEdit() {
+	s.editing, s.editBuf = false, nil
+}
+
+/*   Changes, saves, then lets main apply the   */
+/*   difference                                 */
+func (s *SettingsScreen) apply(change func(p *Prefs)) {
+	old := *s.prefs
+	change(s.prefs)
+	s.prefs.clamp()
+	if err := s.prefs.Save(); err != nil {
+		s.notify("cannot save preferences: " + err.Error())
+	}
+	s.onChange(old)
+}
+
+func (s *SettingsScreen) notify(message string) {
+	s.message, s.messageAt = message, time.Now()
+}
+
+/**************************************************/
+/*                                                */
+/*                    DRAW                        */
+/*                                                */
+/**************************************************/
+
+/*   The edit caret blinks with clock           */
+func (s *SettingsScreen) Draw(clock float32) {
+	x, width := int32(240), int32(SCREEN_WIDTH-480)
+	y := int32(160)
+
+	section := ""
+	for i, item := range s.items {
+		if item.section != section {
+			section = item.section
+			drawText(section, x, y+6, 14, NeonGreen)
+			rl.DrawRectangle(x+measureText(section, 14)+12, y+13, width-measureText(section, 14)-12, 1,
+				rl.Color{R: NeonGreen.R, G: NeonGreen.G, B: NeonGreen.B, A: 50})
+			y += 26
+		}
+
+		selected := i == s.Selected
+		if selected {
+			rl.DrawRectangle(x, y, width, 20, AeroSelected)
+			rl.DrawRectangleGradientV(x, y, width, 10, AeroGloss, rl.Color{A: 0})
+			rl.DrawRectangleLines(x, y, width, 20, NeonGreen)
+		}
+
+		labelColor := TextGray
+		if selected {
+			labelColor = TextWhite
+		}
+		drawText(item.label, x+16, y+4, 12, labelColor)
+
+		value := item.value(s.prefs)
+		if selected && s.editing {
+			value = string(s.editBuf)
+			if int(clock*2)%2 == 0 {
+				value += "_"
+			}
+		} else if selected && item.kind == settingChoice {
+			value = "<  " + value + "  >"
+		}
+		valueWidth := measureText(value, 12)
+		drawText(value, x+width-16-valueWidth, y+4, 12, labelColor)
+
+		y += 22
+	}
+
+	hint := "v  CHANGE SETTINGS"
+	switch {
+	case s.message != "" && time.Since(s.messageAt) < settingsMessageSeconds*time.Second:
+		hint = s.message
+	case s.editing:
+		hint = "[ENTER] SAVE    [ESC] CANCEL    EMPTY = USE CONFIG"
+	case s.Focused():
+		hint = "<< >>  CHANGE    [ENTER] SELECT    saved to " + s.prefs.Path()
+	}
+	hintWidth := measureText(hint, 12)
+	drawText(hint, (SCREEN_WIDTH-hintWidth)/2, y+14, 12, TextGray)
+}
+
+/**************************************************/
+/*                                                */
+/*                 LIVE APPLY                     */
+/*   old is nil at startup, when everything is    */
+/*   applied once                                 */
+/*                                                */
+/**************************************************/
+
+func applyPrefs(p *Prefs, old *Prefs, decorations *AeroDecorations, themes *ThemeFader, feed *LibraryFeed, defaultAddr string) {
+	/*   Fullscreen takes the resolution as its    */
+	/*   video mode, so the size is set before     */
+	/*   going in, and again after coming out as   */
+	/*   leaving restores the old window size.     */
+	/*   Scaling needs no window work at all       */
+	display := p.Display
+	resized := old == nil || old.Display.Width != display.Width || old.Display.Height != display.Height
+	if resized || old.Display.Fullscreen != display.Fullscreen {
+		rl.SetWindowSize(int(display.Width), int(display.Height))
+		if display.Fullscreen != rl.IsWindowFullscreen() {
+			rl.ToggleFullscreen()
+		}
+		if !display.Fullscreen {
+			rl.SetWindowSize(int(display.Width), int(display.Height))
+		}
+	}
+	if old == nil || old.Display != display {
+		if display.VSync {
+			rl.SetWindowState(rl.FlagVsyncHint)
+		} else {
+			rl.ClearWindowState(rl.FlagVsyncHint)
+		}
+		rl.SetTargetFPS(display.FPSCap)
+	}
+
+	if old == nil || old.Effects != p.Effects {
+		decorations.Configure(p.Effects)
+	}
+
+	/*   Cross-fades, except at startup   */
+	switch {
+	case old == nil:
+		themes.Activate(p.Theme)
+	case old.Theme != p.Theme:
+		themes.Switch(p.Theme)
+	}
+
+	if rl.IsAudioDeviceReady() {
+		volume := float32(p.Audio.Volume) / 100
+		if p.Audio.Muted {
+			volume = 0
+		}
+		rl.SetMasterVolume(volume)
+	}
+
+	addr := p.Backend.Address
+	if addr == "" {
+		addr = defaultAddr
+	}
+	feed.SetAddr(addr)
+}
@@ -0,0 +1,465 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+/**************************************/
+/*                                    */
+/*     Theme Engine & Cross-Fade      */
+/*     Frutiger Aero + Y2K Edition    */
+/*           Programmed by            */
+/*            Sertaç Ataç             */
+/*            18.10.2026              */
+/*                                    */
+/**************************************/
+
+/**************************************************/
+/*                                                */
+/*                 THEME FILES                    */
+/*   One JSON file per theme under themes/; any   */
+/*   field left out keeps the built-in value      */
+/*                                                */
+/**************************************************/
+
+const (
+	themesDir        = "themes"
+	defaultThemeName = "Frutiger Aero"
+	themeFadeSeconds = 0.6
+)
+
+/*   "#RRGGBB" or "#RRGGBBAA"   */
+type ThemeColor rl.Color
+
+func (c ThemeColor) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("#%02X%02X%02X%02X", c.R, c.G, c.B, c.A)), nil
+}
+
+func (c *ThemeColor) UnmarshalText(text []byte) error {
+	hex, ok := strings.CutPrefix(string(text), "#")
+	if !ok || (len(hex) != 6 && len(hex) != 8) {
+		return fmt.Errorf("color %q: want #RRGGBB or #RRGGBBAA", text)
+	}
+	if len(hex) == 6 {
+		hex += "FF"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return fmt.Errorf("color %q: %w", text, err)
+	}
+	*c = ThemeColor{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}
+	return nil
+}
+
+/*   Key names in the file; each sets the      */
+/*   palette variable noted                     */
+type Palette struct {
+	Primary       ThemeColor `json:"primary"`        /*   NeonGreen        */
+	PrimaryDark   ThemeColor `json:"primary_dark"`   /*   NeonGreenDark    */
+	PrimaryGlow   ThemeColor `json:"primary_glow"`   /*   NeonGreenGlow    */
+	PrimaryBright ThemeColor `json:"primary_bright"` /*   NeonGreenBright  */
+
+	Background    ThemeColor `json:"background"`
+	BackgroundLow ThemeColor `json:"background_low"` /*   bottom of the gradient */
+	Header        ThemeColor `json:"header"`
+	Panel         ThemeColor `json:"panel"`
+	Selected      ThemeColor `json:"selected"` /*   focused rows and blade */
+	Gloss         ThemeColor `json:"gloss"`
+	Shadow        ThemeColor `json:"shadow"`
+	BladeBorder   ThemeColor `json:"blade_border"`
+	CardTop       ThemeColor `json:"card_top"`
+	CardBottom    ThemeColor `json:"card_bottom"`
+	CardBorder    ThemeColor `json:"card_border"`
+	IconWell      ThemeColor `json:"icon_well"`
+	Droplet       ThemeColor `json:"droplet"`
+	DropletGlow   ThemeColor `json:"droplet_glow"`
+
+	Accent    ThemeColor `json:"accent"`     /*   AccentCyan    */
+	AccentAlt ThemeColor `json:"accent_alt"` /*   AccentPurple  */
+	Warning   ThemeColor `json:"warning"`    /*   AccentOrange  */
+	Alert     ThemeColor `json:"alert"`      /*   AccentPink    */
+	Text      ThemeColor `json:"text"`
+	TextDim   ThemeColor `json:"text_dim"`
+
+	/*   Card color per platform, e.g. "NES"   */
+	Platforms map[string]ThemeColor `json:"platforms"`
+}
+
+type ThemeFonts struct {
+	UI      string  `json:"ui"`      /*   TTF/OTF, relative to the theme file  */
+	Title   string  `json:"title"`   /*   header title and blade labels        */
+	Size    int32   `json:"size"`    /*   px the glyphs are rendered at        */
+	Spacing float32 `json:"spacing"` /*   between letters, per px of text size */
+}
+
+type BladeGeometry struct {
+	Width  float32 `json:"width"` /*   slot per blade, gap included   */
+	Height int32   `json:"height"`
+}
+
+type CardGeometry struct {
+	Width   int32 `json:"width"`
+	Height  int32 `json:"height"`
+	Spacing int32 `json:"spacing"` /*   between cards, across and down   */
+}
+
+type DecorationParams struct {
+	Bubbles       int     `json:"bubbles"` /*   at 100% density   */
+	Droplets      int     `json:"droplets"`
+	BubbleRadius  [2]int  `json:"bubble_radius"` /*   min, max in px   */
+	BubbleSpeed   [2]int  `json:"bubble_speed"`  /*   px per second    */
+	DropletSize   [2]int  `json:"droplet_size"`
+	ScanlineSpeed float32 `json:"scanline_speed"`
+	GlowHeight    int32   `json:"glow_height"`
+}
+
+type HeaderText struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle"`
+	Tag      string `json:"tag"` /*   right side   */
+}
+
+type Theme struct {
+	Name        string           `json:"name"`
+	Palette     Palette          `json:"palette"`
+	Fonts       ThemeFonts       `json:"fonts"`
+	Blades      BladeGeometry    `json:"blades"`
+	Cards       CardGeometry     `json:"cards"`
+	Decorations DecorationParams `json:"decorations"`
+	Header      HeaderText       `json:"header"`
+
+	dir string /*   fonts are found from here   */
+}
+
+/*   The palette variables as they are before   */
+/*   any theme is applied                       */
+var builtinPalette = currentPalette()
+
+func currentPalette() Palette {
+	platforms := make(map[string]ThemeColor)
+	for name, color := range platformColors {
+		platforms[name] = ThemeColor(color)
+	}
+	return Palette{
+		Primary: ThemeColor(NeonGreen), PrimaryDark: ThemeColor(NeonGreenDark),
+		PrimaryGlow: ThemeColor(NeonGreenGlow), PrimaryBright: ThemeColor(NeonGreenBright),
+
+		Background: ThemeColor(AeroBackground), BackgroundLow: ThemeColor(AeroBackgroundLow),
+		Header: ThemeColor(AeroHeader), Panel: ThemeColor(AeroDarkPanel), Selected: ThemeColor(AeroSelected),
+		Gloss: ThemeColor(AeroGloss), Shadow: ThemeColor(AeroShadow), BladeBorder: ThemeColor(AeroBladeBorder),
+		CardTop: ThemeColor(AeroCardTop), CardBottom: ThemeColor(AeroCardBottom),
+		CardBorder: ThemeColor(AeroCardBorder), IconWell: ThemeColor(AeroIconWell),
+		Droplet: ThemeColor(AeroDroplet), DropletGlow: ThemeColor(AeroDropletGlow),
+
+		Accent: ThemeColor(AccentCyan), AccentAlt: ThemeColor(AccentPurple),
+		Warning: ThemeColor(AccentOrange), Alert: ThemeColor(AccentPink),
+		Text: ThemeColor(TextWhite), TextDim: ThemeColor(TextGray),
+
+		Platforms: platforms,
+	}
+}
+
+/*   Sets the palette variables; cards pick up  */
+/*   platform colors when they are next mapped  */
+func (p Palette) apply() {
+	NeonGreen, NeonGreenDark = rl.Color(p.Primary), rl.Color(p.PrimaryDark)
+	NeonGreenGlow, NeonGreenBright = rl.Color(p.PrimaryGlow), rl.Color(p.PrimaryBright)
+
+	AeroBackground, AeroBackgroundLow = rl.Color(p.Background), rl.Color(p.BackgroundLow)
+	AeroHeader, AeroDarkPanel, AeroSelected = rl.Color(p.Header), rl.Color(p.Panel), rl.Color(p.Selected)
+	AeroGloss, AeroShadow, AeroBladeBorder = rl.Color(p.Gloss), rl.Color(p.Shadow), rl.Color(p.BladeBorder)
+	AeroCardTop, AeroCardBottom = rl.Color(p.CardTop), rl.Color(p.CardBottom)
+	AeroCardBorder, AeroIconWell = rl.Color(p.CardBorder), rl.Color(p.IconWell)
+	AeroDroplet, AeroDropletGlow = rl.Color(p.Droplet), rl.Color(p.DropletGlow)
+
+	AccentCyan, AccentPurple = rl.Color(p.Accent), rl.Color(p.AccentAlt)
+	AccentOrange, AccentPink = rl.Color(p.Warning), rl.Color(p.Alert)
+	TextWhite, TextGray = rl.Color(p.Text), rl.Color(p.TextDim)
+
+	platformColors = make(map[string]rl.Color)
+	for name, color := range p.Platforms {
+		platformColors[name] = rl.Color(color)
+	}
+}
+
+/*   The Frutiger Aero / Y2K look; every theme  */
+/*   file is read on top of a fresh copy        */
+func DefaultTheme() *Theme {
+	palette := builtinPalette
+	palette.Platforms = maps.Clone(builtinPalette.Platforms)
+	return &Theme{
+		Name:    defaultThemeName,
+		Palette: palette,
+		Fonts:   ThemeFonts{Size: 32, Spacing: 0.1},
+		Blades:  BladeGeometry{Width: 280, Height: 50},
+		Cards:   CardGeometry{Width: 160, Height: 220, Spacing: 20},
+		Decorations: DecorationParams{
+			Bubbles: 20, Droplets: 8,
+			BubbleRadius: [2]int{4, 25}, BubbleSpeed: [2]int{20, 70}, DropletSize: [2]int{15, 40},
+			ScanlineSpeed: 180, GlowHeight: 70,
+		},
+		Header: HeaderText{Title: "ARCADE PROJECT", Subtitle: "FRUTIGER AERO | Y2K EDITION", Tag: "02.01.2026"},
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                   LOADING                      */
+/*                                                */
+/**************************************************/
+
+/*   The built-in theme first, then each file   */
+/*   in the directories given, by name. A name  */
+/*   already taken is skipped, so the earlier   */
+/*   directory wins. Bad files are left out and */
+/*   reported together                          */
+func LoadThemes(dirs ...string) ([]*Theme, error) {
+	themes := []*Theme{DefaultTheme()}
+	var errs []error
+	for _, dir := range dirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		slices.Sort(files)
+		for _, file := range files {
+			theme, err := LoadTheme(file)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if slices.ContainsFunc(themes, func(t *Theme) bool { return strings.EqualFold(t.Name, theme.Name) }) {
+				continue
+			}
+			themes = append(themes, theme)
+		}
+	}
+	return themes, errors.Join(errs...)
+}
+
+func LoadTheme(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	theme := DefaultTheme()
+	theme.Name = ""
+	if err := json.Unmarshal(data, theme); err != nil {
+		return nil, fmt.Errorf("invalid theme %s: %w", path, err)
+	}
+	if theme.Name == "" {
+		theme.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	theme.dir = filepath.Dir(path)
+	theme.clamp()
+	return theme, nil
+}
+
+func themeNames(themes []*Theme) []string {
+	names := make([]string, len(themes))
+	for i, theme := range themes {
+		names[i] = theme.Name
+	}
+	return names
+}
+
+/*   Keeps six columns of cards and the blade   */
+/*   row clear of the grid at 1280 x 720        */
+func (t *Theme) clamp() {
+	t.Blades.Width = max(160, min(t.Blades.Width, 400))
+	t.Blades.Height = max(30, min(t.Blades.Height, 55))
+
+	t.Cards.Width = max(130, min(t.Cards.Width, 170))
+	t.Cards.Height = max(160, min(t.Cards.Height, 240))
+	t.Cards.Spacing = max(8, min(t.Cards.Spacing, 24))
+
+	d := &t.Decorations
+	d.Bubbles = max(0, min(d.Bubbles, 100))
+	d.Droplets = max(0, min(d.Droplets, 50))
+	for _, r := range []*[2]int{&d.BubbleRadius, &d.BubbleSpeed, &d.DropletSize} {
+		r[0] = max(1, r[0])
+		r[1] = max(r[0], r[1])
+	}
+	d.ScanlineSpeed = max(0, d.ScanlineSpeed)
+	d.GlowHeight = max(0, min(d.GlowHeight, SCREEN_HEIGHT/2))
+
+	t.Fonts.Size = max(8, min(t.Fonts.Size, 128))
+	t.Fonts.Spacing = max(0, min(t.Fonts.Spacing, 1))
+}
+
+/*   Font paths are relative to the theme file  */
+func (t *Theme) fontPath(name string) string {
+	if name == "" || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(t.dir, name)
+}
+
+/**************************************************/
+/*                                                */
+/*                  THEME TEXT                    */
+/*   Every label goes through these so a theme    */
+/*   font replaces raylib's built-in one          */
+/*                                                */
+/**************************************************/
+
+type themeFont struct {
+	font    rl.Font
+	loaded  bool
+	spacing float32
+}
+
+var uiFont, titleFont themeFont
+
+func (f themeFont) draw(text string, x, y, size int32, color rl.Color) {
+	if !f.loaded {
+		rl.DrawText(text, x, y, size, color)
+		return
+	}
+	rl.DrawTextEx(f.font, text, rl.Vector2{X: float32(x), Y: float32(y)}, float32(size), float32(size)*f.spacing, color)
+}
+
+func (f themeFont) measure(text string, size int32) int32 {
+	if !f.loaded {
+		return rl.MeasureText(text, size)
+	}
+	return int32(rl.MeasureTextEx(f.font, text, float32(size), float32(size)*f.spacing).X)
+}
+
+func drawText(text string, x, y, size int32, color rl.Color) {
+	uiFont.draw(text, x, y, size, color)
+}
+
+func measureText(text string, size int32) int32 {
+	return uiFont.measure(text, size)
+}
+
+func drawTitleText(text string, x, y, size int32, color rl.Color) {
+	titleFont.draw(text, x, y, size, color)
+}
+
+func measureTitleText(text string, size int32) int32 {
+	return titleFont.measure(text, size)
+}
+
+/*   A missing or bad file falls back to the    */
+/*   built-in font                              */
+func loadThemeFont(theme *Theme, name string) themeFont {
+	path := theme.fontPath(name)
+	if path == "" {
+		return themeFont{}
+	}
+	font := rl.LoadFontEx(path, theme.Fonts.Size, nil)
+	if !rl.IsFontValid(font) {
+		fmt.Fprintf(os.Stderr, "theme %s: cannot load font %s\n", theme.Name, path)
+		return themeFont{}
+	}
+	rl.SetTextureFilter(font.Texture, rl.FilterBilinear)
+	return themeFont{font: font, loaded: true, spacing: theme.Fonts.Spacing}
+}
+
+func (f themeFont) unload() {
+	if f.loaded {
+		rl.UnloadFont(f.font)
+	}
+}
+
+/**************************************************/
+/*                                                */
+/*                 THEME FADER                    */
+/*   Switch keeps a snapshot of the last frame in */
+/*   the old theme and fades it out over the new  */
+/*   one, so fonts and geometry blend as well     */
+/*                                                */
+/**************************************************/
+
+type ThemeFader struct {
+	themes  []*Theme
+	current *Theme
+	pending *Theme
+	header  HeaderText
+
+	onApply  func(theme *Theme) /*   geometry and particles   */
+	snapshot Canvas
+	fade     float32 /*   snapshot alpha, 1 down to 0   */
+}
+
+func NewThemeFader(themes []*Theme, onApply func(theme *Theme)) *ThemeFader {
+	return &ThemeFader{themes: themes, onApply: onApply}
+}
+
+/*   An unknown name gives the built-in theme   */
+func (f *ThemeFader) find(name string) *Theme {
+	for _, theme := range f.themes {
+		if strings.EqualFold(theme.Name, name) {
+			return theme
+		}
+	}
+	return f.themes[0]
+}
+
+/*   At once, with no fade; for startup         */
+func (f *ThemeFader) Activate(name string) {
+	f.apply(f.find(name))
+}
+
+/*   Takes effect on the next Update            */
+func (f *ThemeFader) Switch(name string) {
+	if theme := f.find(name); theme != f.current {
+		f.pending = theme
+	}
+}
+
+/*   Call before the frame is drawn; drawScene  */
+/*   draws one, here into the snapshot at the   */
+/*   canvas's render scale                      */
+func (f *ThemeFader) Update(dt float32, renderScale int32, drawScene func()) {
+	if f.pending == nil {
+		f.fade = max(0, f.fade-dt/themeFadeSeconds)
+		return
+	}
+	f.snapshot.Resize(renderScale)
+	f.snapshot.Begin()
+	drawScene()
+	f.snapshot.End()
+
+	f.apply(f.pending)
+	f.pending, f.fade = nil, 1
+}
+
+func (f *ThemeFader) apply(theme *Theme) {
+	uiFont.unload()
+	titleFont.unload()
+	uiFont = loadThemeFont(theme, theme.Fonts.UI)
+	titleFont = loadThemeFont(theme, theme.Fonts.Title)
+
+	theme.Palette.apply()
+	f.current, f.header = theme, theme.Header
+	f.onApply(theme)
+}
+
+func (f *ThemeFader) Header() HeaderText {
+	return f.header
+}
+
+/*   Call last while drawing the frame          */
+func (f *ThemeFader) DrawFade() {
+	if f.fade <= 0 {
+		return
+	}
+	f.snapshot.Draw(rl.Rectangle{Width: SCREEN_WIDTH, Height: SCREEN_HEIGHT}, rl.Fade(rl.White, f.fade))
+}
+
+func (f *ThemeFader) Close() {
+	uiFont.unload()
+	titleFont.unload()
+	uiFont, titleFont = themeFont{}, themeFont{}
+	f.snapshot.Unload()
+}